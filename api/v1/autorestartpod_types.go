@@ -17,24 +17,922 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // AutoRestartPodSpec defines the desired state of AutoRestartPod.
+// +kubebuilder:validation:XValidation:rule="(has(self.schedule) && size(self.schedule) > 0) || (has(self.weekly) && size(self.weekly) > 0) || (has(self.weeklySchedules) && size(self.weeklySchedules) > 0) || has(self.at) || (has(self.restarts) && size(self.restarts) > 0) || has(self.policyRef)",message="schedule must be set unless weekly, weeklySchedules, at, restarts or policyRef are set"
+// +kubebuilder:validation:XValidation:rule="!(has(self.at) && has(self.schedule) && size(self.schedule) > 0)",message="only one of schedule and at may be set"
+// +kubebuilder:validation:XValidation:rule="!(has(self.weekly) && size(self.weekly) > 0 && ((has(self.schedule) && size(self.schedule) > 0) || has(self.at)))",message="weekly cannot be combined with schedule or at"
+// +kubebuilder:validation:XValidation:rule="!(has(self.weeklySchedules) && size(self.weeklySchedules) > 0 && ((has(self.weekly) && size(self.weekly) > 0) || has(self.at)))",message="weeklySchedules cannot be combined with weekly or at"
+// +kubebuilder:validation:XValidation:rule="!(has(self.selector) && has(self.targetRef))",message="only one of selector and targetRef may be set"
+// +kubebuilder:validation:XValidation:rule="((!has(self.schedule) || size(self.schedule) == 0) && (!has(self.weekly) || size(self.weekly) == 0) && (!has(self.weeklySchedules) || size(self.weeklySchedules) == 0) && !has(self.at)) || has(self.selector) || has(self.targetRef)",message="one of selector and targetRef must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.maxUnavailablePerDomain) || has(self.topologyKey)",message="maxUnavailablePerDomain requires topologyKey"
+// +kubebuilder:validation:XValidation:rule="has(self.maxRestartsPerWindow) == has(self.window)",message="maxRestartsPerWindow and window must be set together"
+// +kubebuilder:validation:XValidation:rule="!has(self.namespaces) || size(self.namespaces) == 0 || !has(self.targetRef)",message="namespaces cannot be combined with targetRef"
 type AutoRestartPodSpec struct {
-	Schedule string               `json:"schedule"`           // 定义Cron表达式 (例如 "0 3 * * *" 或 "30 */5 * * * *")
-	Selector metav1.LabelSelector `json:"selector"`           // 定义用于选择要重启的Pod的标签选择器
-	TimeZone string               `json:"timeZone,omitempty"` // 可选：时区 (例如 "Asia/Shanghai")
+	Schedule string                `json:"schedule,omitempty"` // 定义Cron表达式 (例如 "0 3 * * *" 或 "30 */5 * * * *")
+	Selector *metav1.LabelSelector `json:"selector,omitempty"` // 定义用于选择要重启的Pod的标签选择器
+	TimeZone string                `json:"timeZone,omitempty"` // 可选：时区 (例如 "Asia/Shanghai")
+
+	// Weekly is an alternative to a cron Schedule listing the days and times
+	// of day to restart on, e.g. Monday at 03:00 and Friday at 01:30. The
+	// times are read in TimeZone.
+	// +kubebuilder:validation:MaxItems=50
+	// +optional
+	Weekly []WeeklyEntry `json:"weekly,omitempty"`
+
+	// WeeklySchedules overrides Schedule on single days of the week, e.g. to
+	// restart earlier on Fridays. It is keyed by MON, TUE, WED, THU, FRI, SAT
+	// and SUN; on every day it lists, only the fire times of its cron
+	// expression on that day count, read in TimeZone. Days that are absent
+	// use Schedule, and do not restart when there is none.
+	// +kubebuilder:validation:MaxProperties=7
+	// +kubebuilder:validation:XValidation:rule="self.all(day, day in ['MON', 'TUE', 'WED', 'THU', 'FRI', 'SAT', 'SUN'])",message="weeklySchedules must be keyed by MON, TUE, WED, THU, FRI, SAT or SUN"
+	// +optional
+	WeeklySchedules map[string]string `json:"weeklySchedules,omitempty"`
+
+	// PolicyRef names a cluster-scoped AutoRestartPolicy whose defaults are
+	// inherited for every field this object leaves unset: schedule,
+	// timeZone, strategy, restartOrder, minInterval, the restart budget and
+	// skipDates.
+	// +optional
+	PolicyRef *PolicyReference `json:"policyRef,omitempty"`
+
+	// At schedules a single restart at an exact time instead of a recurring
+	// Schedule. Once it has been performed the Completed condition is set and
+	// no further restarts happen; a time in the past fires right away.
+	// +optional
+	At *metav1.Time `json:"at,omitempty"`
+
+	// Suspend stops every restart of the object while true. A restart in
+	// progress is continued once the object is resumed, ticks of the
+	// schedule in between are not caught up.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// PauseUntil defers every restart of the object until this time, e.g.
+	// until after a launch. Scheduling resumes as usual afterwards; ticks
+	// in between are not caught up.
+	// +optional
+	PauseUntil *metav1.Time `json:"pauseUntil,omitempty"`
+
+	// StartingDeadlineSeconds is how late a fire time may be caught up.
+	// Fire times that passed while the controller was not running are
+	// caught up with a single restart for the latest of them, unless it is
+	// more than this many seconds ago. They are always caught up when
+	// unset.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+
+	// ActiveDeadline is how long after its creation the object restarts
+	// pods, e.g. "72h" for a temporary restart job. Once it has passed no
+	// restart is fired anymore and the object is marked Completed. It
+	// restarts pods indefinitely when unset.
+	// +optional
+	ActiveDeadline *metav1.Duration `json:"activeDeadline,omitempty"`
+
+	// DryRun reports the pods every restart would restart, in
+	// Status.LastDryRun and an event, without restarting any of them. Ticks
+	// are still handled as if the pods had been restarted.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// RestartOnChangeOf lets a tick of the schedule only restart the pods
+	// when one of these objects changed since the previous restart: the data
+	// of a ConfigMap or Secret, or the pod template of a Deployment or
+	// StatefulSet. The ticks of Spec.Restarts are not affected.
+	// +kubebuilder:validation:MaxItems=20
+	// +listType=atomic
+	// +optional
+	RestartOnChangeOf []ObjectReference `json:"restartOnChangeOf,omitempty"`
+
+	// RestartOnSecretChange restarts the pods right away whenever the data
+	// of this Secret of the object's namespace changes, e.g. after a TLS
+	// certificate was rotated. It works with or without a schedule. The
+	// Secret as first seen does not cause a restart.
+	// +optional
+	RestartOnSecretChange *corev1.LocalObjectReference `json:"restartOnSecretChange,omitempty"`
+
+	// RestartTrigger requests an immediate restart, independent of the
+	// schedule, whenever it changes, e.g. by incrementing it with
+	// `kubectl patch`. Without a selector or targetRef the pods of every
+	// entry of Restarts are restarted.
+	// +optional
+	RestartTrigger int64 `json:"restartTrigger,omitempty"`
+
+	// RestartOnStart restarts the pods once whenever the controller starts,
+	// like a @reboot cron entry, e.g. to pick up changes after an upgrade of
+	// the operator. The schedule is not consulted for this restart.
+	// +optional
+	RestartOnStart bool `json:"restartOnStart,omitempty"`
+
+	// Restarts are additional schedules, each restarting its own pods
+	// independently of the others and of the top-level Schedule. Schedule
+	// may be left empty when Restarts is set.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Restarts []RestartRule `json:"restarts,omitempty"`
+
+	// EnvironmentSchedules maps an environment name to the schedule used
+	// instead of Schedule when the AutoRestartPod carries the label
+	// "environment: <name>". Other environments use Schedule.
+	// +optional
+	EnvironmentSchedules map[string]string `json:"environmentSchedules,omitempty"`
+
+	// Namespaces lists the namespaces Selector picks pods in, e.g. a known
+	// set of environments. Defaults to the namespace of the AutoRestartPod.
+	// The pods of TargetRef are always in the AutoRestartPod's namespace.
+	// The webhook only admits namespaces in which the user creating or
+	// updating the object may delete pods.
+	// +kubebuilder:validation:MaxItems=50
+	// +listType=set
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// TargetRef names the workload whose pods are restarted, as an
+	// alternative to Selector. Exactly one of Selector and TargetRef must be set.
+	// +optional
+	TargetRef *TargetReference `json:"targetRef,omitempty"`
+
+	// AllowOverlap admits the object, with a warning, although another
+	// AutoRestartPod targets the same pods, through its selector, targetRef
+	// or the selector of an entry of restarts in a namespace both pick pods
+	// in. Overlapping objects are denied otherwise, as they restart the same
+	// pods twice.
+	// +optional
+	AllowOverlap bool `json:"allowOverlap,omitempty"`
+
+	// ExcludeSelector keeps the pods it matches out of every restart, e.g.
+	// a leader. Pods can also exclude themselves with the annotation
+	// "autorestart.crazyfrank.com/exclude: true". Under RolloutRestart an
+	// excluded pod is still replaced when its workload is restarted for
+	// one of its other pods.
+	// +optional
+	ExcludeSelector *metav1.LabelSelector `json:"excludeSelector,omitempty"`
+
+	// RequireControllerOwner only restarts pods that have a controller
+	// owner reference, such as pods of a ReplicaSet or StatefulSet. Bare
+	// pods matched by the same selector are left alone, as nothing would
+	// recreate them once deleted.
+	// +optional
+	RequireControllerOwner bool `json:"requireControllerOwner,omitempty"`
+
+	// FieldSelector further restricts the pods picked by Selector or
+	// TargetRef by their fields, e.g. "spec.nodeName=node-1,status.phase=Running".
+	// Only equality is supported, on the fields spec.nodeName,
+	// spec.restartPolicy, spec.schedulerName, spec.serviceAccountName,
+	// status.phase and status.podIP, which the controller indexes.
+	// +optional
+	FieldSelector string `json:"fieldSelector,omitempty"`
+
+	// MatchExpression is a CEL expression evaluated against every pod picked
+	// by Selector or TargetRef; only pods for which it is true are restarted.
+	// The pod is available as `pod`, e.g. "pod.spec.nodeName.startsWith('gpu-')".
+	// +optional
+	MatchExpression string `json:"matchExpression,omitempty"`
+
+	// PodPhaseFilter restricts restarts to pods in one of the listed phases,
+	// typically [Running] so Pending or crash-looping pods keep their backoff.
+	// Pods in every phase are restarted when unset.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	// +optional
+	PodPhaseFilter []corev1.PodPhase `json:"podPhaseFilter,omitempty"`
+
+	// MinContainerRestartCount restricts restarts to pods with a container,
+	// init containers included, that restarted more than this many times,
+	// so that with a frequent schedule only crash-looping pods are
+	// restarted. Pods are restarted whatever their restart counts when unset.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinContainerRestartCount *int32 `json:"minContainerRestartCount,omitempty"`
+
+	// NodeConditions restricts restarts to pods on nodes with one of the
+	// listed conditions, such as DiskPressure=True, so pods are moved off
+	// problematic nodes on a schedule. Pods on every node are restarted when
+	// unset; unscheduled pods never match.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	NodeConditions []NodeConditionMatch `json:"nodeConditions,omitempty"`
+
+	// NodeSelector restricts restarts to pods on nodes with all of these
+	// labels, e.g. to restart a single node pool. Combined with
+	// NodeConditions a node must match both. Pods on every node are
+	// restarted when unset; unscheduled pods never match.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// AlignToMidnight snaps "@every <duration>" schedules to boundaries
+	// counted from midnight in the schedule's timezone, so "@every 6h" fires
+	// at 00:00, 06:00, 12:00 and 18:00 instead of relative to the last check.
+	// It has no effect on cron expressions.
+	// +optional
+	AlignToMidnight bool `json:"alignToMidnight,omitempty"`
+
+	// IntervalJitterPercent moves every fire time of the top-level schedule
+	// by up to this percentage of the interval around it, earlier or later,
+	// so that restarts across a fleet do not line up. The shift of each fire
+	// time is derived from the object's namespace and name, and stays the
+	// same for every reconcile.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=50
+	// +optional
+	IntervalJitterPercent *int32 `json:"intervalJitterPercent,omitempty"`
+
+	// Strategy selects how pods are restarted. Defaults to Delete.
+	// +optional
+	Strategy RestartStrategy `json:"strategy,omitempty"`
+
+	// ValidateEvictionFirst makes the Evict strategy check with a dry-run
+	// eviction of every pod of a pass that no PodDisruptionBudget blocks it
+	// before evicting any. Pods that would be blocked are left alone and
+	// reported as failed.
+	// +optional
+	ValidateEvictionFirst bool `json:"validateEvictionFirst,omitempty"`
+
+	// ResumePaused lets the RolloutRestart strategy restart a paused
+	// Deployment by resuming it until the restart has been rolled out and
+	// pausing it again. Paused Deployments are skipped otherwise.
+	// +optional
+	ResumePaused bool `json:"resumePaused,omitempty"`
+
+	// RestartAnnotationKey is the pod template annotation the RolloutRestart
+	// strategy sets to the restart time, for tooling that watches its own
+	// key. Defaults to kubectl.kubernetes.io/restartedAt, the key
+	// `kubectl rollout restart` sets.
+	// +kubebuilder:validation:MaxLength=317
+	// +optional
+	RestartAnnotationKey string `json:"restartAnnotationKey,omitempty"`
+
+	// DeletePropagationPolicy is passed along when the Delete strategy
+	// deletes a pod, deciding how its dependents are handled. The API server
+	// default applies when unset.
+	// +kubebuilder:validation:Enum=Background;Foreground;Orphan
+	// +optional
+	DeletePropagationPolicy *metav1.DeletionPropagation `json:"deletePropagationPolicy,omitempty"`
+
+	// GracePeriodSeconds overrides the termination grace period of the pods
+	// deleted by the Delete strategy. Zero kills them right away. Pods with a
+	// preStop hook that are annotated with
+	// "autorestart.crazyfrank.com/prestop-duration" are given at least that
+	// duration, so the hook can complete.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"`
+
+	// WaitForPreStop makes the Delete strategy delete one pod per pass and
+	// only delete the next once no matched pod is terminating anymore, or
+	// the grace period of the terminating ones is over. It gives preStop
+	// hooks and connection draining of stateful apps time to complete.
+	// +optional
+	WaitForPreStop bool `json:"waitForPreStop,omitempty"`
+
+	// RestartOrder decides which pods are restarted first, which matters
+	// when a restart is split over several passes. Defaults to Oldest.
+	// +optional
+	RestartOrder RestartOrder `json:"restartOrder,omitempty"`
+
+	// RestartOrderByLabel names a pod label whose value decides which pods
+	// are restarted first, e.g. "role" to restart followers before the
+	// leader. Pods with the same value keep RestartOrder among themselves
+	// and pods without the label go last.
+	// +optional
+	RestartOrderByLabel string `json:"restartOrderByLabel,omitempty"`
+
+	// RestartOrderLabelValues lists the values of RestartOrderByLabel in the
+	// order their pods are restarted in, e.g. ["follower", "leader"]. Values
+	// not listed follow in lexical order, as do all values when unset.
+	// +optional
+	RestartOrderLabelValues []string `json:"restartOrderLabelValues,omitempty"`
+
+	// RestartProbability is the chance, between 0 and 1, that a tick of
+	// Schedule actually restarts pods, for chaos testing at a controlled
+	// rate. The outcome of a tick is derived from the object's UID and the
+	// tick, so retries of the same tick agree. Every tick restarts when unset.
+	// It is a string such as "0.25" because the API avoids floating point
+	// fields.
+	// +kubebuilder:validation:Pattern=`^(0(\.[0-9]+)?|1(\.0+)?)$`
+	// +optional
+	RestartProbability string `json:"restartProbability,omitempty"`
+
+	// MinHealthyFraction is the share of the matched pods, between 0 and 1,
+	// that must be Ready for a tick of Schedule to restart them. Restarting
+	// pods that are mostly not Ready, e.g. during an incident, may make
+	// things worse, so such a tick passes and is reported in the Skipped
+	// condition. It is a string such as "0.5" like RestartProbability.
+	// Every tick may restart when unset.
+	// +kubebuilder:validation:Pattern=`^(0(\.[0-9]+)?|1(\.0+)?)$`
+	// +optional
+	MinHealthyFraction string `json:"minHealthyFraction,omitempty"`
+
+	// SkipDates lists calendar dates on which no scheduled restart happens,
+	// e.g. known heavy days. Dates are RFC 3339 full-dates such as
+	// "2025-12-24", not timestamps, and are read in the timezone of the
+	// schedule. Unlike a recurring time of day, every date applies once.
+	// +kubebuilder:validation:items:Pattern=`^[0-9]{4}-(0[1-9]|1[0-2])-(0[1-9]|[12][0-9]|3[01])$`
+	// +listType=set
+	// +optional
+	SkipDates []string `json:"skipDates,omitempty"`
+
+	// TimeWindows restricts scheduled restarts to daily time ranges. A tick
+	// that falls outside every window passes without restarting. Every tick
+	// may restart when unset.
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	TimeWindows []TimeWindow `json:"timeWindows,omitempty"`
+
+	// MinInterval is the minimum time that must pass between two restarts.
+	// A due restart that falls inside the cooldown is skipped and the
+	// controller requeues until the cooldown has elapsed.
+	// +optional
+	MinInterval *metav1.Duration `json:"minInterval,omitempty"`
+
+	// MaxRestartsPerWindow caps how many restarts may be fired within any
+	// trailing Window, whatever the schedule says. A restart that would
+	// exceed the budget is skipped.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxRestartsPerWindow *int32 `json:"maxRestartsPerWindow,omitempty"`
+
+	// Window is the trailing time window MaxRestartsPerWindow applies to.
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty"`
+
+	// MaxPodsPerRestart caps how many pods are deleted in a single pass of
+	// the Delete strategy, protecting against overly broad selectors.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxPodsPerRestart *int32 `json:"maxPodsPerRestart,omitempty"`
+
+	// RestartFraction restarts only part of the matched pods on every fire
+	// of the Delete strategy, either a number of pods such as 2 or a
+	// percentage such as "25%" rounded up, so that successive fires cycle
+	// through the pods and spread the churn over time. Pods restarted by
+	// earlier fires of the cycle are picked last. Every matched pod is
+	// restarted when unset.
+	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:validation:XValidation:rule="type(self) == int ? self > 0 : self.matches('^([1-9][0-9]?|100)%$')",message="restartFraction must be a positive number of pods or a percentage between 1% and 100%"
+	// +optional
+	RestartFraction *intstr.IntOrString `json:"restartFraction,omitempty"`
+
+	// RestartSampleSize restarts exactly this many randomly chosen matched
+	// pods on every fire of the Delete strategy, for chaos and soak testing.
+	// Every matched pod is restarted when fewer match. The sample is
+	// derived from RestartSampleSeed and the fire time, so retries of the
+	// same fire agree.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RestartSampleSize *int32 `json:"restartSampleSize,omitempty"`
+
+	// RestartSampleSeed seeds the sample of RestartSampleSize, so that a
+	// fire time always picks the same pods, e.g. to reproduce a test.
+	// Defaults to the object's UID.
+	// +optional
+	RestartSampleSeed *int64 `json:"restartSampleSeed,omitempty"`
+
+	// OverflowPolicy decides what happens when more pods match than
+	// MaxPodsPerRestart allows. Defaults to Truncate.
+	// +optional
+	OverflowPolicy OverflowPolicy `json:"overflowPolicy,omitempty"`
+
+	// ReplacementWait is how long the controller waits, after deleting pods,
+	// for their replacements to be created before it schedules the next run.
+	// Replacements need not be Ready. No waiting is done when unset.
+	// +optional
+	ReplacementWait *metav1.Duration `json:"replacementWait,omitempty"`
+
+	// RunTimeout is how long a restart split over several passes, or
+	// deferred by the circuit breaker or SLA gate, may take from its start.
+	// A run that is still restarting pods after RunTimeout is stopped and
+	// reported with the RunTimedOut condition and a Warning event, so a
+	// stuck rolling restart surfaces. Waiting for replacements is bounded by
+	// ReplacementWait instead. Runs never time out when unset.
+	// +optional
+	RunTimeout *metav1.Duration `json:"runTimeout,omitempty"`
+
+	// NotificationWebhook receives a POST request with a JSON summary of
+	// every restart, e.g. a Slack, Teams or PagerDuty webhook. Delivery
+	// failures are logged and do not fail the restart.
+	// +optional
+	NotificationWebhook string `json:"notificationWebhook,omitempty"`
+
+	// TopologyKey is a node label, such as topology.kubernetes.io/zone, that
+	// splits the pods into failure domains. When set, the Delete strategy
+	// restarts one domain per pass so that two domains are never restarting
+	// at the same time. MaxPodsPerRestart applies to each pass.
+	// +optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+
+	// MaxUnavailablePerDomain caps how many pods of a failure domain are
+	// restarted in a single pass. It only applies together with TopologyKey.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxUnavailablePerDomain *int32 `json:"maxUnavailablePerDomain,omitempty"`
+
+	// DaemonSetNodesPerPass is on how many nodes the Delete strategy
+	// restarts the pods of each DaemonSet in a single pass, so that a
+	// node-level service is never down on every node at once. It defaults to
+	// one node. Pods of other owners are not affected.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	DaemonSetNodesPerPass *int32 `json:"daemonSetNodesPerPass,omitempty"`
+
+	// HonorDaemonSetUpdateStrategy restarts the pods of a DaemonSet with a
+	// RollingUpdate strategy on as many nodes per pass as its maxUnavailable
+	// allows, instead of DaemonSetNodesPerPass.
+	// +optional
+	HonorDaemonSetUpdateStrategy bool `json:"honorDaemonSetUpdateStrategy,omitempty"`
+
+	// PreDrain deregisters each pod from an external load balancer or
+	// service mesh before it is deleted.
+	// +optional
+	PreDrain *PreDrainSpec `json:"preDrain,omitempty"`
+
+	// SLAGate defers restarts while a metric, such as the current request
+	// latency, is above a threshold, so pods are not restarted at peak load.
+	// +optional
+	SLAGate *SLAGateSpec `json:"slaGate,omitempty"`
+}
+
+// RestartRule restarts the pods matching its selector on its own schedule.
+// The options of the spec apply to the pods of a rule as they do to those of
+// the top-level selector, including the guards and the options that split a
+// restart over several passes. Only one restart runs at a time: the rules
+// due while another restart is in progress wait for it to finish.
+type RestartRule struct {
+	// Name identifies the rule in status.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Schedule is a cron expression like the top-level Schedule.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Selector picks the pods the rule restarts.
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// TimeZone of the schedule. UTC is used when empty.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// TargetReference identifies a workload in the AutoRestartPod's namespace.
+type TargetReference struct {
+	// Kind of the workload.
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet
+	Kind string `json:"kind"`
+
+	// Name of the workload.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// ObjectReference identifies an object in the AutoRestartPod's namespace
+// whose changes are watched.
+type ObjectReference struct {
+	// Kind of the object.
+	// +kubebuilder:validation:Enum=ConfigMap;Secret;Deployment;StatefulSet
+	Kind string `json:"kind"`
+
+	// Name of the object.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// WeeklyEntry is a day of the week and a time of day to restart at.
+type WeeklyEntry struct {
+	// Day of the week, e.g. Monday.
+	// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+	Day string `json:"day"`
+
+	// Time of day in 24-hour "HH:MM" format, e.g. "03:00".
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Time string `json:"time"`
+}
+
+// NodeConditionMatch matches nodes by one of their conditions.
+type NodeConditionMatch struct {
+	// Type of the node condition, e.g. DiskPressure or a custom condition
+	// set by a node problem detector.
+	Type corev1.NodeConditionType `json:"type"`
+
+	// Status the condition must have. Defaults to True.
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	// +kubebuilder:default=True
+	// +optional
+	Status corev1.ConditionStatus `json:"status,omitempty"`
+}
+
+// TimeWindow is a daily time range in which scheduled restarts may happen.
+type TimeWindow struct {
+	// Start of the window in 24-hour "HH:MM" format, inclusive.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// End of the window in 24-hour "HH:MM" format, exclusive. A window that
+	// ends before it starts spans midnight.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
+
+	// TimeZone the window is read in, so that windows of one object can
+	// follow different regions. Defaults to the timezone of the schedule
+	// when unset.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// PolicyReference names an AutoRestartPolicy.
+type PolicyReference struct {
+	// Name of the AutoRestartPolicy.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// RestartStrategy describes how the targeted pods are restarted.
+// +kubebuilder:validation:Enum=Delete;RolloutRestart;Evict
+type RestartStrategy string
+
+const (
+	// DeleteStrategy deletes every matched pod and lets its controller recreate it.
+	DeleteStrategy RestartStrategy = "Delete"
+
+	// RolloutRestartStrategy triggers a rolling restart of the owning workload
+	// the same way `kubectl rollout restart` does, by stamping its pod template.
+	// Pods without a restartable owner are deleted instead.
+	RolloutRestartStrategy RestartStrategy = "RolloutRestart"
+
+	// EvictStrategy evicts every matched pod through the eviction API, so
+	// that PodDisruptionBudgets are respected, and lets its controller
+	// recreate it.
+	EvictStrategy RestartStrategy = "Evict"
+)
+
+// ScheduleFormat describes how a cron expression was parsed.
+// +kubebuilder:validation:Enum=standard;seconds
+type ScheduleFormat string
+
+const (
+	// StandardScheduleFormat is the five field format starting with the
+	// minute, or a descriptor such as @daily.
+	StandardScheduleFormat ScheduleFormat = "standard"
+
+	// SecondsScheduleFormat is the six field format starting with the second.
+	SecondsScheduleFormat ScheduleFormat = "seconds"
+)
+
+// RestartOrder describes the order pods are restarted in.
+// +kubebuilder:validation:Enum=Oldest;Newest;Random
+type RestartOrder string
+
+const (
+	// OldestFirst restarts the longest-running pods first.
+	OldestFirst RestartOrder = "Oldest"
+
+	// NewestFirst restarts the most recently created pods first.
+	NewestFirst RestartOrder = "Newest"
+
+	// RandomOrder restarts pods in a random order.
+	RandomOrder RestartOrder = "Random"
+)
+
+// OverflowPolicy describes how a restart that matches more pods than
+// MaxPodsPerRestart is handled.
+// +kubebuilder:validation:Enum=Truncate;Refuse
+type OverflowPolicy string
+
+const (
+	// TruncateOverflow restarts the oldest MaxPodsPerRestart pods and
+	// continues with the remaining ones on the following passes.
+	TruncateOverflow OverflowPolicy = "Truncate"
+
+	// RefuseOverflow skips the restart and reports the PodLimitExceeded condition.
+	RefuseOverflow OverflowPolicy = "Refuse"
+)
+
+// PreDrainSpec configures the endpoint deregistration performed before a pod is deleted.
+// Re-registration of the replacement pod is left to the normal endpoint controllers.
+type PreDrainSpec struct {
+	// URL receives a POST request describing the pod to deregister.
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// DrainPeriod is how long to wait after deregistration for open
+	// connections to drain before the pod is deleted. The restart does not
+	// block on it: pods are marked with the annotation
+	// "autorestart.crazyfrank.com/drained-at" and deleted by the first pass
+	// of the run after the period.
+	// +optional
+	DrainPeriod *metav1.Duration `json:"drainPeriod,omitempty"`
+}
+
+// SLAGateSpec queries a Prometheus compatible server before every restart
+// pass. A pass is deferred while the query result is above Threshold, or
+// while the query fails.
+type SLAGateSpec struct {
+	// URL of the server, e.g. "http://prometheus.monitoring:9090". The query
+	// is sent to its /api/v1/query endpoint.
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// Query is a PromQL expression evaluating to a single value.
+	// +kubebuilder:validation:MinLength=1
+	Query string `json:"query"`
+
+	// Threshold is the highest value of Query restarts are allowed at, as a
+	// decimal string such as "0.5".
+	// +kubebuilder:validation:Pattern=`^-?[0-9]+(\.[0-9]+)?$`
+	Threshold string `json:"threshold"`
 }
 
 // AutoRestartPodStatus defines the observed state of AutoRestartPod.
 type AutoRestartPodStatus struct {
 	LastRestartTime *metav1.Time `json:"lastRestartTime,omitempty"` // Record the last reboot time
+
+	// Initialized is when the controller first reconciled the
+	// AutoRestartPod. Unlike LastRestartTime it is set before any restart
+	// happened.
+	// +optional
+	Initialized *metav1.Time `json:"initialized,omitempty"`
+
+	// LastScheduleTime is the fire time of the schedule the controller last
+	// reacted to, by restarting pods or by deliberately skipping the tick.
+	// Unlike LastRestartTime it is the scheduled instant, not when the pods
+	// were actually restarted.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// UpcomingRestartTimes lists the next fire times of the top-level
+	// schedule, to check it fires when intended. Skip dates, time windows
+	// and the other reasons a tick may pass are not taken into account.
+	// +listType=atomic
+	// +optional
+	UpcomingRestartTimes []metav1.Time `json:"upcomingRestartTimes,omitempty"`
+
+	// ParsedScheduleFormat is how the cron expressions of the top-level
+	// schedule were read: standard with five fields, or seconds when an
+	// expression only parses with a leading seconds field. The first of
+	// UpcomingRestartTimes is the next fire time under that reading.
+	// +optional
+	ParsedScheduleFormat ScheduleFormat `json:"parsedScheduleFormat,omitempty"`
+
+	// LastTriggeredRestart is the value of Spec.RestartTrigger the last
+	// triggered restart was performed for.
+	// +optional
+	LastTriggeredRestart int64 `json:"lastTriggeredRestart,omitempty"`
+
+	// LastStartupRestartTime is when the pods were last restarted because
+	// the controller started, see Spec.RestartOnStart.
+	// +optional
+	LastStartupRestartTime *metav1.Time `json:"lastStartupRestartTime,omitempty"`
+
+	// ObservedGeneration is the most recent generation of the spec the
+	// controller has reconciled successfully.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastScheduleLag is how long after its scheduled fire time the last
+	// restart pass ran. It is negative when the pass ran early.
+	// +optional
+	LastScheduleLag *metav1.Duration `json:"lastScheduleLag,omitempty"`
+
+	// MissedSchedules is how many fire times of the schedule had passed
+	// without the controller running when it last caught up, whether the
+	// catch-up restarted or was past Spec.StartingDeadlineSeconds.
+	// +optional
+	MissedSchedules int64 `json:"missedSchedules,omitempty"`
+
+	// TargetKinds are the kinds of the objects the last restart acted on:
+	// Pod for deleted pods, Deployment or StatefulSet for rollout restarted
+	// workloads.
+	// +listType=set
+	// +optional
+	TargetKinds []string `json:"targetKinds,omitempty"`
+
+	// LastRestartStrategy is the strategy the last restart actually used:
+	// RolloutRestart when it rollout restarted a workload, otherwise Delete
+	// or Evict for the pods it restarted itself, including the pods without
+	// a restartable owner a RolloutRestart falls back to deleting.
+	// +optional
+	LastRestartStrategy RestartStrategy `json:"lastRestartStrategy,omitempty"`
+
+	// TerminatingPods is how many matched pods were already terminating, and
+	// therefore left alone, during the last restart pass.
+	// +optional
+	TerminatingPods int32 `json:"terminatingPods,omitempty"`
+
+	// FractionRestartedPods names the pods restarted by the fires of the
+	// current cycle of Spec.RestartFraction. A new cycle starts once every
+	// matched pod was restarted.
+	// +listType=set
+	// +optional
+	FractionRestartedPods []string `json:"fractionRestartedPods,omitempty"`
+
+	// RestartHistory lists when restarts were fired within the trailing
+	// Spec.Window. It is only kept while a restart budget is configured.
+	// +listType=atomic
+	// +optional
+	RestartHistory []metav1.Time `json:"restartHistory,omitempty"`
+
+	// Rules reports the state of every entry of Spec.Restarts.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Rules []RuleStatus `json:"rules,omitempty"`
+
+	// LastRestartResults reports the outcome of every pod the most recent
+	// restart tried to restart, over all passes of its run.
+	// +listType=atomic
+	// +optional
+	LastRestartResults []PodRestartResult `json:"lastRestartResults,omitempty"`
+
+	// ObservedChangeHash is the hash of the objects of
+	// Spec.RestartOnChangeOf as of the last restart they caused.
+	// +optional
+	ObservedChangeHash string `json:"observedChangeHash,omitempty"`
+
+	// ObservedSecretHash is the hash of the data of the Secret of
+	// Spec.RestartOnSecretChange as last seen.
+	// +optional
+	ObservedSecretHash string `json:"observedSecretHash,omitempty"`
+
+	// LastDryRun reports the pods the most recent restart of the top-level
+	// schedule would have restarted under Spec.DryRun.
+	// +optional
+	LastDryRun *DryRunResult `json:"lastDryRun,omitempty"`
+
+	// ActiveRun is set while a restart is carried out, from before its
+	// first pass until its last one, so that a controller taking over
+	// after a leader failover continues it instead of firing it again.
+	// +optional
+	ActiveRun *RestartRun `json:"activeRun,omitempty"`
+
+	// ConsecutiveFailures counts the restart passes in a row that failed to
+	// restart some of their pods. A pass without failures resets it.
+	// +optional
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
+
+	// Conditions describe the latest observations of the AutoRestartPod.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// RuleStatus is the observed state of a RestartRule.
+type RuleStatus struct {
+	// Name of the rule.
+	Name string `json:"name"`
+
+	// LastRestartTime is when the rule last restarted its pods.
+	// +optional
+	LastRestartTime *metav1.Time `json:"lastRestartTime,omitempty"`
+}
+
+// PodRestartResult is the outcome of restarting a single pod.
+type PodRestartResult struct {
+	// Pod is the name of the pod. Workloads restarted by the
+	// RolloutRestart strategy are named "Kind/name".
+	Pod string `json:"pod"`
+
+	// Success reports whether the pod was restarted.
+	Success bool `json:"success"`
+
+	// Error is why the pod could not be restarted.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// DryRunResult is the outcome of a dry run.
+type DryRunResult struct {
+	// Time is when the dry run was performed.
+	Time metav1.Time `json:"time"`
+
+	// Pods are the names of the pods that would have been restarted.
+	// +listType=atomic
+	// +optional
+	Pods []string `json:"pods,omitempty"`
+
+	// Added are the pods that the previous dry run would not have restarted.
+	// Every pod is added by the first dry run.
+	// +listType=atomic
+	// +optional
+	Added []string `json:"added,omitempty"`
+
+	// Removed are the pods the previous dry run would have restarted that
+	// this one would not.
+	// +listType=atomic
+	// +optional
+	Removed []string `json:"removed,omitempty"`
+}
+
+// RestartRun tracks a restart that did not finish in a single pass.
+type RestartRun struct {
+	// StartTime is when the run began. Pods created afterwards are
+	// replacements and are not restarted again by the same run.
+	StartTime metav1.Time `json:"startTime"`
+
+	// ScheduledTime is the fire time the run was started for.
+	ScheduledTime metav1.Time `json:"scheduledTime"`
+
+	// RestartedPods is how many pods the run has restarted so far.
+	// +optional
+	RestartedPods int32 `json:"restartedPods,omitempty"`
+
+	// AwaitingReplacements is set once every pod has been restarted and the
+	// run only waits for their replacements, see Spec.ReplacementWait.
+	// +optional
+	AwaitingReplacements bool `json:"awaitingReplacements,omitempty"`
+
+	// Rule names the entry of Spec.Restarts whose pods the run restarts,
+	// empty for the pods of the top-level selector or targetRef.
+	// +optional
+	Rule string `json:"rule,omitempty"`
+}
+
+// Condition types reported in AutoRestartPodStatus.Conditions.
+const (
+	// ConditionReady is True when the latest generation of the spec was
+	// reconciled successfully, and False with the error otherwise.
+	ConditionReady = "Ready"
+
+	// ConditionScheduleDrift is True when restarts no longer follow the
+	// schedule, because a fire time was missed or pods were restarted by
+	// something other than the controller.
+	ConditionScheduleDrift = "ScheduleDrift"
+
+	// ConditionTimeZoneOffset explains how TimeZone shifts the next run
+	// against UTC. It is True when the run lands on another day in UTC.
+	ConditionTimeZoneOffset = "TimeZoneOffset"
+
+	// ConditionPodLimitExceeded is True when a restart was refused because
+	// more pods matched than MaxPodsPerRestart allows.
+	ConditionPodLimitExceeded = "PodLimitExceeded"
+
+	// ConditionCircuitOpen is True while restarts are paused because the
+	// controller observed a burst of failed restarts across all objects.
+	ConditionCircuitOpen = "CircuitOpen"
+
+	// ConditionWorkloadPaused is True when the last restart skipped paused
+	// Deployments, which ignore rollout restarts.
+	ConditionWorkloadPaused = "WorkloadPaused"
+
+	// ConditionRateLimited is True when a restart was skipped because
+	// Spec.MaxRestartsPerWindow restarts were already fired within
+	// Spec.Window.
+	ConditionRateLimited = "RateLimited"
+
+	// ConditionCompleted is True once the one-time restart of Spec.At has
+	// been performed, or Spec.ActiveDeadline has passed.
+	ConditionCompleted = "Completed"
+
+	// ConditionSLADeferred is True while restarts are deferred by
+	// Spec.SLAGate.
+	ConditionSLADeferred = "SLADeferred"
+
+	// ConditionProgressing is True while a restart is carried out.
+	ConditionProgressing = "Progressing"
+
+	// ConditionAvailable is True once the replacements of the restarted
+	// pods are Ready. It is only tracked with Spec.ReplacementWait and is
+	// Unknown otherwise.
+	ConditionAvailable = "Available"
+
+	// ConditionDegraded is True when several restart passes in a row failed
+	// to restart some of their pods, or a reconcile failed with an error
+	// that retrying does not fix.
+	ConditionDegraded = "Degraded"
+
+	// ConditionSuspended is True while Spec.Suspend stops every restart.
+	ConditionSuspended = "Suspended"
+
+	// ConditionSkipped is True when the last tick of Schedule passed
+	// without restarting because fewer than Spec.MinHealthyFraction of the
+	// matched pods were Ready.
+	ConditionSkipped = "Skipped"
+
+	// ConditionRunTimedOut is True when the last restart was stopped because
+	// it did not finish within Spec.RunTimeout.
+	ConditionRunTimedOut = "RunTimedOut"
+
+	// ConditionInvalidSchedule is True with the parse error while a
+	// schedule of the spec does not parse.
+	ConditionInvalidSchedule = "InvalidSchedule"
+
+	// ConditionNamespacesUnavailable is True with the errors when pods could
+	// not be listed in some of Namespaces, whose pods are then left alone
+	// while the other namespaces are still restarted.
+	ConditionNamespacesUnavailable = "NamespacesUnavailable"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 
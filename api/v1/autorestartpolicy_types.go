@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AutoRestartPolicySpec holds the defaults AutoRestartPods referencing the
+// policy inherit. Every field an AutoRestartPod sets itself overrides the
+// policy.
+// +kubebuilder:validation:XValidation:rule="has(self.maxRestartsPerWindow) == has(self.window)",message="maxRestartsPerWindow and window must be set together"
+type AutoRestartPolicySpec struct {
+	// Schedule is the cron expression inherited by AutoRestartPods that set
+	// neither schedule, at nor restarts.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// TimeZone the schedule is evaluated in, e.g. "Asia/Shanghai".
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Strategy selects how pods are restarted.
+	// +optional
+	Strategy RestartStrategy `json:"strategy,omitempty"`
+
+	// RestartOrder decides which pods are restarted first.
+	// +optional
+	RestartOrder RestartOrder `json:"restartOrder,omitempty"`
+
+	// MinInterval is the minimum time that must pass between two restarts.
+	// +optional
+	MinInterval *metav1.Duration `json:"minInterval,omitempty"`
+
+	// MaxRestartsPerWindow caps how many restarts may be fired within any
+	// trailing Window. Both are inherited together, and only by
+	// AutoRestartPods that set neither.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxRestartsPerWindow *int32 `json:"maxRestartsPerWindow,omitempty"`
+
+	// Window is the trailing time window MaxRestartsPerWindow applies to.
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty"`
+
+	// SkipDates lists calendar dates on which no scheduled restart happens,
+	// as RFC 3339 full-dates such as "2025-12-24".
+	// +kubebuilder:validation:items:Pattern=`^[0-9]{4}-(0[1-9]|1[0-2])-(0[1-9]|[12][0-9]|3[01])$`
+	// +listType=set
+	// +optional
+	SkipDates []string `json:"skipDates,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// AutoRestartPolicy is the Schema for the autorestartpolicies API. It shares
+// restart defaults between the AutoRestartPods that reference it through
+// their policyRef.
+type AutoRestartPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AutoRestartPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AutoRestartPolicyList contains a list of AutoRestartPolicy.
+type AutoRestartPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AutoRestartPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AutoRestartPolicy{}, &AutoRestartPolicyList{})
+}
@@ -21,7 +21,10 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -86,7 +89,202 @@ func (in *AutoRestartPodList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AutoRestartPodSpec) DeepCopyInto(out *AutoRestartPodSpec) {
 	*out = *in
-	in.Selector.DeepCopyInto(&out.Selector)
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Weekly != nil {
+		in, out := &in.Weekly, &out.Weekly
+		*out = make([]WeeklyEntry, len(*in))
+		copy(*out, *in)
+	}
+	if in.WeeklySchedules != nil {
+		in, out := &in.WeeklySchedules, &out.WeeklySchedules
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PolicyRef != nil {
+		in, out := &in.PolicyRef, &out.PolicyRef
+		*out = new(PolicyReference)
+		**out = **in
+	}
+	if in.At != nil {
+		in, out := &in.At, &out.At
+		*out = (*in).DeepCopy()
+	}
+	if in.PauseUntil != nil {
+		in, out := &in.PauseUntil, &out.PauseUntil
+		*out = (*in).DeepCopy()
+	}
+	if in.StartingDeadlineSeconds != nil {
+		in, out := &in.StartingDeadlineSeconds, &out.StartingDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ActiveDeadline != nil {
+		in, out := &in.ActiveDeadline, &out.ActiveDeadline
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RestartOnChangeOf != nil {
+		in, out := &in.RestartOnChangeOf, &out.RestartOnChangeOf
+		*out = make([]ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.RestartOnSecretChange != nil {
+		in, out := &in.RestartOnSecretChange, &out.RestartOnSecretChange
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Restarts != nil {
+		in, out := &in.Restarts, &out.Restarts
+		*out = make([]RestartRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvironmentSchedules != nil {
+		in, out := &in.EnvironmentSchedules, &out.EnvironmentSchedules
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TargetRef != nil {
+		in, out := &in.TargetRef, &out.TargetRef
+		*out = new(TargetReference)
+		**out = **in
+	}
+	if in.ExcludeSelector != nil {
+		in, out := &in.ExcludeSelector, &out.ExcludeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodPhaseFilter != nil {
+		in, out := &in.PodPhaseFilter, &out.PodPhaseFilter
+		*out = make([]corev1.PodPhase, len(*in))
+		copy(*out, *in)
+	}
+	if in.MinContainerRestartCount != nil {
+		in, out := &in.MinContainerRestartCount, &out.MinContainerRestartCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NodeConditions != nil {
+		in, out := &in.NodeConditions, &out.NodeConditions
+		*out = make([]NodeConditionMatch, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.IntervalJitterPercent != nil {
+		in, out := &in.IntervalJitterPercent, &out.IntervalJitterPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DeletePropagationPolicy != nil {
+		in, out := &in.DeletePropagationPolicy, &out.DeletePropagationPolicy
+		*out = new(metav1.DeletionPropagation)
+		**out = **in
+	}
+	if in.GracePeriodSeconds != nil {
+		in, out := &in.GracePeriodSeconds, &out.GracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RestartOrderLabelValues != nil {
+		in, out := &in.RestartOrderLabelValues, &out.RestartOrderLabelValues
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SkipDates != nil {
+		in, out := &in.SkipDates, &out.SkipDates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TimeWindows != nil {
+		in, out := &in.TimeWindows, &out.TimeWindows
+		*out = make([]TimeWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.MinInterval != nil {
+		in, out := &in.MinInterval, &out.MinInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxRestartsPerWindow != nil {
+		in, out := &in.MaxRestartsPerWindow, &out.MaxRestartsPerWindow
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxPodsPerRestart != nil {
+		in, out := &in.MaxPodsPerRestart, &out.MaxPodsPerRestart
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RestartFraction != nil {
+		in, out := &in.RestartFraction, &out.RestartFraction
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.RestartSampleSize != nil {
+		in, out := &in.RestartSampleSize, &out.RestartSampleSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RestartSampleSeed != nil {
+		in, out := &in.RestartSampleSeed, &out.RestartSampleSeed
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ReplacementWait != nil {
+		in, out := &in.ReplacementWait, &out.ReplacementWait
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RunTimeout != nil {
+		in, out := &in.RunTimeout, &out.RunTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxUnavailablePerDomain != nil {
+		in, out := &in.MaxUnavailablePerDomain, &out.MaxUnavailablePerDomain
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DaemonSetNodesPerPass != nil {
+		in, out := &in.DaemonSetNodesPerPass, &out.DaemonSetNodesPerPass
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PreDrain != nil {
+		in, out := &in.PreDrain, &out.PreDrain
+		*out = new(PreDrainSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SLAGate != nil {
+		in, out := &in.SLAGate, &out.SLAGate
+		*out = new(SLAGateSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoRestartPodSpec.
@@ -106,6 +304,76 @@ func (in *AutoRestartPodStatus) DeepCopyInto(out *AutoRestartPodStatus) {
 		in, out := &in.LastRestartTime, &out.LastRestartTime
 		*out = (*in).DeepCopy()
 	}
+	if in.Initialized != nil {
+		in, out := &in.Initialized, &out.Initialized
+		*out = (*in).DeepCopy()
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.UpcomingRestartTimes != nil {
+		in, out := &in.UpcomingRestartTimes, &out.UpcomingRestartTimes
+		*out = make([]metav1.Time, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastStartupRestartTime != nil {
+		in, out := &in.LastStartupRestartTime, &out.LastStartupRestartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastScheduleLag != nil {
+		in, out := &in.LastScheduleLag, &out.LastScheduleLag
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TargetKinds != nil {
+		in, out := &in.TargetKinds, &out.TargetKinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FractionRestartedPods != nil {
+		in, out := &in.FractionRestartedPods, &out.FractionRestartedPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RestartHistory != nil {
+		in, out := &in.RestartHistory, &out.RestartHistory
+		*out = make([]metav1.Time, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]RuleStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastRestartResults != nil {
+		in, out := &in.LastRestartResults, &out.LastRestartResults
+		*out = make([]PodRestartResult, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastDryRun != nil {
+		in, out := &in.LastDryRun, &out.LastDryRun
+		*out = new(DryRunResult)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ActiveRun != nil {
+		in, out := &in.ActiveRun, &out.ActiveRun
+		*out = new(RestartRun)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoRestartPodStatus.
@@ -117,3 +385,319 @@ func (in *AutoRestartPodStatus) DeepCopy() *AutoRestartPodStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoRestartPolicy) DeepCopyInto(out *AutoRestartPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoRestartPolicy.
+func (in *AutoRestartPolicy) DeepCopy() *AutoRestartPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoRestartPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AutoRestartPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoRestartPolicyList) DeepCopyInto(out *AutoRestartPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AutoRestartPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoRestartPolicyList.
+func (in *AutoRestartPolicyList) DeepCopy() *AutoRestartPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoRestartPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AutoRestartPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoRestartPolicySpec) DeepCopyInto(out *AutoRestartPolicySpec) {
+	*out = *in
+	if in.MinInterval != nil {
+		in, out := &in.MinInterval, &out.MinInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxRestartsPerWindow != nil {
+		in, out := &in.MaxRestartsPerWindow, &out.MaxRestartsPerWindow
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SkipDates != nil {
+		in, out := &in.SkipDates, &out.SkipDates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoRestartPolicySpec.
+func (in *AutoRestartPolicySpec) DeepCopy() *AutoRestartPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoRestartPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunResult) DeepCopyInto(out *DryRunResult) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Added != nil {
+		in, out := &in.Added, &out.Added
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Removed != nil {
+		in, out := &in.Removed, &out.Removed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunResult.
+func (in *DryRunResult) DeepCopy() *DryRunResult {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeConditionMatch) DeepCopyInto(out *NodeConditionMatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeConditionMatch.
+func (in *NodeConditionMatch) DeepCopy() *NodeConditionMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeConditionMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectReference) DeepCopyInto(out *ObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectReference.
+func (in *ObjectReference) DeepCopy() *ObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodRestartResult) DeepCopyInto(out *PodRestartResult) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodRestartResult.
+func (in *PodRestartResult) DeepCopy() *PodRestartResult {
+	if in == nil {
+		return nil
+	}
+	out := new(PodRestartResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyReference) DeepCopyInto(out *PolicyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyReference.
+func (in *PolicyReference) DeepCopy() *PolicyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreDrainSpec) DeepCopyInto(out *PreDrainSpec) {
+	*out = *in
+	if in.DrainPeriod != nil {
+		in, out := &in.DrainPeriod, &out.DrainPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreDrainSpec.
+func (in *PreDrainSpec) DeepCopy() *PreDrainSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PreDrainSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestartRule) DeepCopyInto(out *RestartRule) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestartRule.
+func (in *RestartRule) DeepCopy() *RestartRule {
+	if in == nil {
+		return nil
+	}
+	out := new(RestartRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestartRun) DeepCopyInto(out *RestartRun) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.ScheduledTime.DeepCopyInto(&out.ScheduledTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestartRun.
+func (in *RestartRun) DeepCopy() *RestartRun {
+	if in == nil {
+		return nil
+	}
+	out := new(RestartRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleStatus) DeepCopyInto(out *RuleStatus) {
+	*out = *in
+	if in.LastRestartTime != nil {
+		in, out := &in.LastRestartTime, &out.LastRestartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleStatus.
+func (in *RuleStatus) DeepCopy() *RuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLAGateSpec) DeepCopyInto(out *SLAGateSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLAGateSpec.
+func (in *SLAGateSpec) DeepCopy() *SLAGateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SLAGateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetReference) DeepCopyInto(out *TargetReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetReference.
+func (in *TargetReference) DeepCopy() *TargetReference {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeWindow) DeepCopyInto(out *TimeWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeWindow.
+func (in *TimeWindow) DeepCopy() *TimeWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WeeklyEntry) DeepCopyInto(out *WeeklyEntry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WeeklyEntry.
+func (in *WeeklyEntry) DeepCopy() *WeeklyEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(WeeklyEntry)
+	in.DeepCopyInto(out)
+	return out
+}
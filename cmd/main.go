@@ -17,18 +17,27 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"flag"
+	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
@@ -38,7 +47,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+	"github.com/crazyfrankie/autorestart-operator/internal/admin"
+	"github.com/crazyfrankie/autorestart-operator/internal/audit"
 	"github.com/crazyfrankie/autorestart-operator/internal/controller"
+	webhookstablev1 "github.com/crazyfrankie/autorestart-operator/internal/webhook/v1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -63,6 +75,17 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var auditLogPath string
+	var breakerThreshold int
+	var breakerWindow time.Duration
+	var eventMirrorNamespace string
+	var minScheduleInterval time.Duration
+	var maxConcurrentReconciles int
+	var deleteConcurrency int
+	var maxRequeueInterval time.Duration
+	var adminAddr string
+	var adminCertPath, adminCertName, adminCertKey, adminClientCAName string
+	var defaultTimeZone string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -81,6 +104,36 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&auditLogPath, "audit-log-path", "",
+		"If set, every restart is recorded in this file and can be queried on the metrics server under /audit.")
+	flag.IntVar(&breakerThreshold, "circuit-breaker-threshold", 0,
+		"Number of failed restarts within --circuit-breaker-window that pauses all restarts. 0 disables the breaker.")
+	flag.DurationVar(&breakerWindow, "circuit-breaker-window", 5*time.Minute,
+		"Sliding window over which failed restarts are counted by the circuit breaker.")
+	flag.StringVar(&eventMirrorNamespace, "event-mirror-namespace", "",
+		"If set, every event about an AutoRestartPod is also recorded in this namespace.")
+	flag.DurationVar(&minScheduleInterval, "min-schedule-interval", 0,
+		"If set, the webhook denies schedules firing more often than this, e.g. 5m. 0 allows any schedule. "+
+			"Updates that leave the spec of an existing object alone are not checked.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"The number of AutoRestartPods reconciled in parallel.")
+	flag.IntVar(&deleteConcurrency, "delete-concurrency", 1,
+		"The number of pods of a restart deleted in parallel. Keep it low so bursts of deletions do not trip "+
+			"API priority and fairness.")
+	flag.DurationVar(&maxRequeueInterval, "max-requeue-interval", time.Hour,
+		"The longest an AutoRestartPod waits to be reconciled again, even when its next restart is further off. "+
+			"0 waits until the next restart.")
+	flag.StringVar(&adminAddr, "admin-bind-address", "0",
+		"The address the gRPC admin API binds to, e.g. :9090. Leave as 0 to disable the admin API. "+
+			"Without --admin-cert-path it is served without authentication and only on a loopback address.")
+	flag.StringVar(&adminCertPath, "admin-cert-path", "",
+		"The directory that contains the admin API certificate and the CA of its client certificates.")
+	flag.StringVar(&adminCertName, "admin-cert-name", "tls.crt", "The name of the admin API certificate file.")
+	flag.StringVar(&adminCertKey, "admin-cert-key", "tls.key", "The name of the admin API key file.")
+	flag.StringVar(&adminClientCAName, "admin-client-ca-name", "ca.crt",
+		"The name of the file of the CA that signs the client certificates of the admin API.")
+	flag.StringVar(&defaultTimeZone, "default-timezone", "UTC",
+		"The timezone of AutoRestartPods that do not set spec.timeZone, e.g. Asia/Shanghai.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -89,6 +142,13 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	// An unknown default timezone would fail every object relying on it
+	defaultLocation, err := time.LoadLocation(defaultTimeZone)
+	if err != nil {
+		setupLog.Error(err, "invalid default timezone", "timezone", defaultTimeZone)
+		os.Exit(1)
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -104,8 +164,8 @@ func main() {
 		tlsOpts = append(tlsOpts, disableHTTP2)
 	}
 
-	// Create watchers for metrics and webhooks certificates
-	var metricsCertWatcher, webhookCertWatcher *certwatcher.CertWatcher
+	// Create watchers for metrics, webhooks and admin API certificates
+	var metricsCertWatcher, webhookCertWatcher, adminCertWatcher *certwatcher.CertWatcher
 
 	// Initial webhook TLS options
 	webhookTLSOpts := tlsOpts
@@ -178,6 +238,20 @@ func main() {
 		})
 	}
 
+	// The audit store is optional; restarts are not recorded unless a path is given
+	var auditStore audit.Store = audit.NopStore{}
+	if len(auditLogPath) > 0 {
+		fileStore, err := audit.NewFileStore(auditLogPath)
+		if err != nil {
+			setupLog.Error(err, "unable to open audit log", "audit-log-path", auditLogPath)
+			os.Exit(1)
+		}
+		auditStore = fileStore
+		metricsServerOptions.ExtraHandlers = map[string]http.Handler{
+			"/audit": audit.Handler(fileStore),
+		}
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		Metrics:                metricsServerOptions,
@@ -202,13 +276,102 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := (&controller.AutoRestartPodReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	var breaker *controller.CircuitBreaker
+	if breakerThreshold > 0 {
+		breaker = controller.NewCircuitBreaker(breakerThreshold, breakerWindow)
+	}
+
+	ctx := ctrl.SetupSignalHandler()
+
+	// Spans are only exported when an OTLP endpoint is configured
+	tracerProvider, err := newTracerProvider(ctx)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	defer func() {
+		if err := tracerProvider.Shutdown(context.Background()); err != nil {
+			setupLog.Error(err, "unable to flush traces")
+		}
+	}()
+
+	reconciler := &controller.AutoRestartPodReconciler{
+		Client:                  mgr.GetClient(),
+		Scheme:                  mgr.GetScheme(),
+		APIReader:               mgr.GetAPIReader(),
+		Audit:                   auditStore,
+		Breaker:                 breaker,
+		Recorder:                mgr.GetEventRecorderFor(controller.EventSource),
+		TracerProvider:          tracerProvider,
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		DeleteConcurrency:       deleteConcurrency,
+		MaxRequeueInterval:      maxRequeueInterval,
+		DefaultTimeZone:         defaultLocation,
+	}
+	if eventMirrorNamespace != "" {
+		events, err := typedcorev1.NewForConfigAndClient(mgr.GetConfig(), mgr.GetHTTPClient())
+		if err != nil {
+			setupLog.Error(err, "unable to create event mirror client")
+			os.Exit(1)
+		}
+		reconciler.MirrorRecorder = controller.NewMirrorRecorder(ctx, events, mgr.GetScheme(), eventMirrorNamespace)
+	}
+	if err := reconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AutoRestartPod")
 		os.Exit(1)
 	}
+	// Upcoming restarts are served next to the metrics from the manager's cache
+	if err := mgr.AddMetricsServerExtraHandler("/upcoming", reconciler.UpcomingHandler()); err != nil {
+		setupLog.Error(err, "unable to add upcoming restarts handler")
+		os.Exit(1)
+	}
+	if adminAddr != "0" {
+		adminServer := &admin.Server{Addr: adminAddr, Client: mgr.GetClient(), Upcoming: reconciler}
+		// The admin API changes AutoRestartPods, so callers must present a
+		// certificate of the client CA. tlsOpts are left out, gRPC needs HTTP/2.
+		if len(adminCertPath) > 0 {
+			setupLog.Info("Initializing admin API certificate watcher using provided certificates",
+				"admin-cert-path", adminCertPath, "admin-cert-name", adminCertName, "admin-cert-key", adminCertKey,
+				"admin-client-ca-name", adminClientCAName)
+
+			var err error
+			adminCertWatcher, err = certwatcher.New(
+				filepath.Join(adminCertPath, adminCertName),
+				filepath.Join(adminCertPath, adminCertKey),
+			)
+			if err != nil {
+				setupLog.Error(err, "Failed to initialize admin API certificate watcher")
+				os.Exit(1)
+			}
+			clientCA, err := os.ReadFile(filepath.Join(adminCertPath, adminClientCAName))
+			if err != nil {
+				setupLog.Error(err, "unable to read admin API client CA")
+				os.Exit(1)
+			}
+			clientCAs := x509.NewCertPool()
+			if !clientCAs.AppendCertsFromPEM(clientCA) {
+				setupLog.Error(nil, "no certificates in admin API client CA", "admin-client-ca-name", adminClientCAName)
+				os.Exit(1)
+			}
+			adminServer.TLSConfig = &tls.Config{
+				MinVersion:     tls.VersionTLS12,
+				GetCertificate: adminCertWatcher.GetCertificate,
+				ClientAuth:     tls.RequireAndVerifyClientCert,
+				ClientCAs:      clientCAs,
+			}
+		}
+		if err := mgr.Add(adminServer); err != nil {
+			setupLog.Error(err, "unable to add admin API server")
+			os.Exit(1)
+		}
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := webhookstablev1.SetupAutoRestartPodWebhookWithManager(mgr, minScheduleInterval, defaultTimeZone); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "AutoRestartPod")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -227,6 +390,14 @@ func main() {
 		}
 	}
 
+	if adminCertWatcher != nil {
+		setupLog.Info("Adding admin API certificate watcher to manager")
+		if err := mgr.Add(adminCertWatcher); err != nil {
+			setupLog.Error(err, "unable to add admin API certificate watcher to manager")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -237,8 +408,31 @@ func main() {
 	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// newTracerProvider returns the tracer provider of the controller's spans.
+// Spans are exported over OTLP/gRPC when OTEL_EXPORTER_OTLP_ENDPOINT or
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT is set, along with the other standard
+// OTEL_* variables; they are dropped otherwise.
+func newTracerProvider(ctx context.Context) (*sdktrace.TracerProvider, error) {
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return sdktrace.NewTracerProvider(), nil
+	}
+	exporter, err := otlptracegrpc.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the service name
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", "autorestartpod-controller")),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK())
+	if err != nil {
+		return nil, err
+	}
+	return sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res)), nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// Client calls the admin service over conn.
+type Client struct {
+	conn grpc.ClientConnInterface
+}
+
+// NewClient returns a Client of the admin service served on conn.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{conn: conn}
+}
+
+// ListUpcoming calls Admin.ListUpcoming.
+func (c *Client) ListUpcoming(ctx context.Context, req *ListUpcomingRequest) (*ListUpcomingResponse, error) {
+	resp := &ListUpcomingResponse{}
+	if err := c.invoke(ctx, "ListUpcoming", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// TriggerRestart calls Admin.TriggerRestart.
+func (c *Client) TriggerRestart(ctx context.Context, req *ObjectRequest) (*TriggerRestartResponse, error) {
+	resp := &TriggerRestartResponse{}
+	if err := c.invoke(ctx, "TriggerRestart", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Suspend calls Admin.Suspend.
+func (c *Client) Suspend(ctx context.Context, req *ObjectRequest) (*SuspensionResponse, error) {
+	resp := &SuspensionResponse{}
+	if err := c.invoke(ctx, "Suspend", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Resume calls Admin.Resume.
+func (c *Client) Resume(ctx context.Context, req *ObjectRequest) (*SuspensionResponse, error) {
+	resp := &SuspensionResponse{}
+	if err := c.invoke(ctx, "Resume", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// invoke calls method with the JSON codec of the service.
+func (c *Client) invoke(ctx context.Context, method string, req, resp any) error {
+	return c.conn.Invoke(ctx, "/"+ServiceName+"/"+method, req, resp, grpc.ForceCodec(Codec))
+}
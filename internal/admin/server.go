@@ -0,0 +1,286 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package admin serves the gRPC admin API of the controller, see
+// admin.proto, so that ops tooling can list upcoming restarts, trigger
+// restarts and suspend AutoRestartPods without editing them by hand.
+package admin
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+	"github.com/crazyfrankie/autorestart-operator/internal/controller"
+)
+
+// ServiceName is the full name of the admin service.
+const ServiceName = "autorestartpod.admin.v1.Admin"
+
+// ListUpcomingRequest selects the upcoming restarts to list.
+type ListUpcomingRequest struct {
+	// Namespace limits the objects listed, every namespace when empty.
+	Namespace string `json:"namespace,omitempty"`
+	// Within limits the restarts to those due within a duration such as "1h".
+	Within string `json:"within,omitempty"`
+}
+
+// ListUpcomingResponse lists upcoming restarts, soonest first.
+type ListUpcomingResponse struct {
+	Restarts []controller.UpcomingRestart `json:"restarts"`
+}
+
+// ObjectRequest names an AutoRestartPod.
+type ObjectRequest struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// TriggerRestartResponse reports the new Spec.RestartTrigger of an object.
+type TriggerRestartResponse struct {
+	RestartTrigger int64 `json:"restartTrigger"`
+}
+
+// SuspensionResponse reports the new Spec.Suspend of an object.
+type SuspensionResponse struct {
+	Suspended bool `json:"suspended"`
+}
+
+// AdminServer is the server API of the admin service.
+type AdminServer interface {
+	ListUpcoming(context.Context, *ListUpcomingRequest) (*ListUpcomingResponse, error)
+	TriggerRestart(context.Context, *ObjectRequest) (*TriggerRestartResponse, error)
+	Suspend(context.Context, *ObjectRequest) (*SuspensionResponse, error)
+	Resume(context.Context, *ObjectRequest) (*SuspensionResponse, error)
+}
+
+// UpcomingLister lists upcoming restarts, as the reconciler does.
+type UpcomingLister interface {
+	UpcomingRestarts(ctx context.Context, namespace string, within time.Duration) ([]controller.UpcomingRestart, error)
+}
+
+// Server implements the admin service against the Kubernetes API. It runs
+// as a manager.Runnable on every replica, not only the leader.
+type Server struct {
+	// Addr is the TCP address Start listens on, e.g. ":9443".
+	Addr string
+
+	// TLSConfig secures the connections, normally requiring client
+	// certificates. Without it the API is served in plaintext and without
+	// authentication, so Start only listens on a loopback Addr.
+	TLSConfig *tls.Config
+
+	// Client reads and patches the AutoRestartPods.
+	Client client.Client
+
+	// Upcoming computes the upcoming restarts.
+	Upcoming UpcomingLister
+}
+
+var _ AdminServer = &Server{}
+
+// serviceDesc describes the admin service the way protoc-gen-go-grpc would.
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		unaryMethod("ListUpcoming", AdminServer.ListUpcoming),
+		unaryMethod("TriggerRestart", AdminServer.TriggerRestart),
+		unaryMethod("Suspend", AdminServer.Suspend),
+		unaryMethod("Resume", AdminServer.Resume),
+	},
+	Metadata: "admin.proto",
+}
+
+// unaryMethod describes the unary method name served by call.
+func unaryMethod[Req, Resp any](name string,
+	call func(AdminServer, context.Context, *Req) (*Resp, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv any, ctx context.Context, dec func(any) error,
+			interceptor grpc.UnaryServerInterceptor) (any, error) {
+			in := new(Req)
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, req any) (any, error) {
+				return call(srv.(AdminServer), ctx, req.(*Req))
+			}
+			if interceptor == nil {
+				return handler(ctx, in)
+			}
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + ServiceName + "/" + name}
+			return interceptor(ctx, in, info, handler)
+		},
+	}
+}
+
+// Register registers s as the admin service of registrar.
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	registrar.RegisterService(&serviceDesc, s)
+}
+
+// Start listens on Addr and serves the admin service until ctx is done.
+func (s *Server) Start(ctx context.Context) error {
+	if s.TLSConfig == nil && !isLoopback(s.Addr) {
+		return fmt.Errorf("refusing to serve the admin API on %s without TLS, bind it to a loopback address "+
+			"or configure a certificate", s.Addr)
+	}
+	lis, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.Addr, err)
+	}
+	return s.Serve(ctx, lis)
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Serve serves the admin service on lis until ctx is done, then lets the
+// calls in flight finish.
+func (s *Server) Serve(ctx context.Context, lis net.Listener) error {
+	opts := []grpc.ServerOption{grpc.ForceServerCodec(Codec)}
+	if s.TLSConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.TLSConfig)))
+	}
+	srv := grpc.NewServer(opts...)
+	s.Register(srv)
+	go func() {
+		<-ctx.Done()
+		srv.GracefulStop()
+	}()
+	logf.FromContext(ctx).Info("Serving admin API", "address", lis.Addr().String())
+	return srv.Serve(lis)
+}
+
+// isLoopback reports whether the TCP address addr only accepts connections
+// from the host itself. An empty host listens on every interface.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ListUpcoming implements AdminServer.
+func (s *Server) ListUpcoming(ctx context.Context, req *ListUpcomingRequest) (*ListUpcomingResponse, error) {
+	var within time.Duration
+	if req.Within != "" {
+		var err error
+		if within, err = time.ParseDuration(req.Within); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid within: %v", err)
+		}
+	}
+	restarts, err := s.Upcoming.UpcomingRestarts(ctx, req.Namespace, within)
+	if err != nil {
+		return nil, apiError(err)
+	}
+	return &ListUpcomingResponse{Restarts: restarts}, nil
+}
+
+// TriggerRestart implements AdminServer.
+func (s *Server) TriggerRestart(ctx context.Context, req *ObjectRequest) (*TriggerRestartResponse, error) {
+	obj, err := s.patch(ctx, req, func(obj *stablev1.AutoRestartPod) {
+		obj.Spec.RestartTrigger++
+	})
+	if err != nil {
+		return nil, err
+	}
+	logf.FromContext(ctx).Info("Triggered restart through the admin API",
+		"namespace", req.Namespace, "name", req.Name, "restartTrigger", obj.Spec.RestartTrigger)
+	return &TriggerRestartResponse{RestartTrigger: obj.Spec.RestartTrigger}, nil
+}
+
+// Suspend implements AdminServer.
+func (s *Server) Suspend(ctx context.Context, req *ObjectRequest) (*SuspensionResponse, error) {
+	return s.setSuspend(ctx, req, true)
+}
+
+// Resume implements AdminServer.
+func (s *Server) Resume(ctx context.Context, req *ObjectRequest) (*SuspensionResponse, error) {
+	return s.setSuspend(ctx, req, false)
+}
+
+// setSuspend sets Spec.Suspend of the requested object to suspend.
+func (s *Server) setSuspend(ctx context.Context, req *ObjectRequest, suspend bool) (*SuspensionResponse, error) {
+	obj, err := s.patch(ctx, req, func(obj *stablev1.AutoRestartPod) {
+		obj.Spec.Suspend = suspend
+	})
+	if err != nil {
+		return nil, err
+	}
+	logf.FromContext(ctx).Info("Changed suspension through the admin API",
+		"namespace", req.Namespace, "name", req.Name, "suspend", suspend)
+	return &SuspensionResponse{Suspended: obj.Spec.Suspend}, nil
+}
+
+// patch applies mutate to the requested object. The optimistic lock turns a
+// stale read into an Aborted error the caller can retry.
+func (s *Server) patch(ctx context.Context, req *ObjectRequest,
+	mutate func(*stablev1.AutoRestartPod)) (*stablev1.AutoRestartPod, error) {
+	if req.Namespace == "" || req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "namespace and name are required")
+	}
+	obj := &stablev1.AutoRestartPod{}
+	if err := s.Client.Get(ctx, types.NamespacedName{Namespace: req.Namespace, Name: req.Name}, obj); err != nil {
+		return nil, apiError(err)
+	}
+	patch := client.MergeFromWithOptions(obj.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	mutate(obj)
+	if err := s.Client.Patch(ctx, obj, patch); err != nil {
+		return nil, apiError(err)
+	}
+	return obj, nil
+}
+
+// apiError converts an error of the Kubernetes API into a gRPC status.
+func apiError(err error) error {
+	message := err.Error()
+	switch {
+	case apierrors.IsNotFound(err):
+		return status.Error(codes.NotFound, message)
+	case apierrors.IsConflict(err):
+		return status.Error(codes.Aborted, message)
+	case apierrors.IsForbidden(err):
+		return status.Error(codes.PermissionDenied, message)
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, message)
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), apierrors.IsTooManyRequests(err),
+		apierrors.IsServiceUnavailable(err):
+		return status.Error(codes.Unavailable, message)
+	}
+	return status.Error(codes.Internal, message)
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+	"github.com/crazyfrankie/autorestart-operator/internal/controller"
+)
+
+// newObject returns an AutoRestartPod in the default namespace restarting on schedule.
+func newObject(name, schedule string) *stablev1.AutoRestartPod {
+	return &stablev1.AutoRestartPod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: stablev1.AutoRestartPodSpec{
+			Schedule: schedule,
+			TimeZone: "UTC",
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+		},
+	}
+}
+
+// newCertificate returns a certificate for name signed by parent, or a
+// self-signed CA when parent is nil.
+func newCertificate(name string, parent *tls.Certificate) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := template, any(key)
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	Expect(err).NotTo(HaveOccurred())
+	leaf, err := x509.ParseCertificate(der)
+	Expect(err).NotTo(HaveOccurred())
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+var _ = Describe("Admin API security", func() {
+	It("should refuse to serve without TLS on an address other than loopback", func() {
+		server := &Server{Addr: ":0"}
+		Expect(server.Start(context.Background())).To(MatchError(ContainSubstring("without TLS")))
+	})
+
+	It("should serve without TLS on a loopback address", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		server := &Server{Addr: "127.0.0.1:0"}
+		done := make(chan error)
+		go func() { done <- server.Start(ctx) }()
+		Consistently(done, 100*time.Millisecond).ShouldNot(Receive())
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+	})
+
+	It("should only serve clients with a certificate of the client CA", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		scheme := runtime.NewScheme()
+		Expect(stablev1.AddToScheme(scheme)).To(Succeed())
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newObject("hourly", "0 * * * *")).Build()
+
+		ca := newCertificate("ca", nil)
+		serverCert := newCertificate("admin", &ca)
+		pool := x509.NewCertPool()
+		pool.AddCert(ca.Leaf)
+		lis := bufconn.Listen(1 << 20)
+		server := &Server{Client: c, TLSConfig: &tls.Config{
+			Certificates: []tls.Certificate{serverCert},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    pool,
+		}}
+		go func() { _ = server.Serve(ctx, lis) }()
+
+		dial := func(certs ...tls.Certificate) *Client {
+			creds := credentials.NewTLS(&tls.Config{RootCAs: pool, ServerName: "admin", Certificates: certs})
+			conn, err := grpc.NewClient("passthrough:///admin", grpc.WithTransportCredentials(creds),
+				grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+					return lis.DialContext(ctx)
+				}))
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(conn.Close)
+			return NewClient(conn)
+		}
+
+		_, err := dial().Suspend(ctx, &ObjectRequest{Namespace: "default", Name: "hourly"})
+		Expect(status.Code(err)).To(Equal(codes.Unavailable))
+		resp, err := dial(newCertificate("ops", &ca)).Suspend(ctx, &ObjectRequest{Namespace: "default", Name: "hourly"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Suspended).To(BeTrue())
+	})
+})
+
+var _ = Describe("Admin API", func() {
+	var (
+		ctx    context.Context
+		c      client.Client
+		api    *Client
+		cancel context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		scheme := runtime.NewScheme()
+		Expect(stablev1.AddToScheme(scheme)).To(Succeed())
+		c = fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(newObject("nightly", "0 3 * * *"), newObject("hourly", "0 * * * *")).Build()
+		reconciler := &controller.AutoRestartPodReconciler{Client: c, Scheme: scheme,
+			Clock: testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 30, 0, 0, time.UTC))}
+
+		lis := bufconn.Listen(1 << 20)
+		server := &Server{Client: c, Upcoming: reconciler}
+		done := make(chan error)
+		go func() { done <- server.Serve(ctx, lis) }()
+		DeferCleanup(func() {
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+		})
+
+		conn, err := grpc.NewClient("passthrough:///admin",
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return lis.DialContext(ctx)
+			}))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(conn.Close)
+		api = NewClient(conn)
+	})
+
+	It("should list upcoming restarts soonest first", func() {
+		resp, err := api.ListUpcoming(ctx, &ListUpcomingRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Restarts).To(HaveLen(2))
+		Expect(resp.Restarts[0].Name).To(Equal("hourly"))
+		Expect(resp.Restarts[0].NextRestartTime).To(BeTemporally("==", time.Date(2025, 5, 26, 11, 0, 0, 0, time.UTC)))
+		Expect(resp.Restarts[1].Name).To(Equal("nightly"))
+
+		resp, err = api.ListUpcoming(ctx, &ListUpcomingRequest{Within: "1h"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Restarts).To(HaveLen(1))
+
+		_, err = api.ListUpcoming(ctx, &ListUpcomingRequest{Within: "soon"})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+
+	It("should trigger a restart by incrementing the restart trigger", func() {
+		for _, want := range []int64{1, 2} {
+			resp, err := api.TriggerRestart(ctx, &ObjectRequest{Namespace: "default", Name: "nightly"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.RestartTrigger).To(Equal(want))
+		}
+
+		obj := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "nightly"}, obj)).To(Succeed())
+		Expect(obj.Spec.RestartTrigger).To(BeEquivalentTo(2))
+	})
+
+	It("should suspend and resume an object", func() {
+		req := &ObjectRequest{Namespace: "default", Name: "hourly"}
+		resp, err := api.Suspend(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Suspended).To(BeTrue())
+
+		// A suspended object has no upcoming restarts
+		upcoming, err := api.ListUpcoming(ctx, &ListUpcomingRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(upcoming.Restarts).To(HaveLen(1))
+		Expect(upcoming.Restarts[0].Name).To(Equal("nightly"))
+
+		resp, err = api.Resume(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Suspended).To(BeFalse())
+		obj := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "hourly"}, obj)).To(Succeed())
+		Expect(obj.Spec.Suspend).To(BeFalse())
+	})
+
+	It("should report missing objects and names", func() {
+		_, err := api.TriggerRestart(ctx, &ObjectRequest{Namespace: "default", Name: "missing"})
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+
+		_, err = api.Suspend(ctx, &ObjectRequest{Name: "hourly"})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+})
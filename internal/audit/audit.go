@@ -0,0 +1,136 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit records restart decisions made by the AutoRestartPod
+// controller so they can be inspected later, for example in air-gapped
+// environments without a central logging pipeline.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Record describes a single restart performed for an AutoRestartPod.
+type Record struct {
+	// Time is when the restart was performed.
+	Time time.Time `json:"time"`
+	// Namespace and Name identify the AutoRestartPod.
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Schedule and TimeZone are the spec values the decision was based on.
+	Schedule string `json:"schedule"`
+	TimeZone string `json:"timeZone,omitempty"`
+	// Selector is the label selector used to find the pods.
+	Selector string `json:"selector"`
+	// ScheduledTime is the fire time the restart was performed for.
+	ScheduledTime time.Time `json:"scheduledTime"`
+	// RestartedPods lists the pods that were deleted.
+	RestartedPods []string `json:"restartedPods,omitempty"`
+	// FailedPods lists the pods whose restart failed.
+	FailedPods []string `json:"failedPods,omitempty"`
+}
+
+// Query filters the records returned by Store.Query. Zero values match everything.
+type Query struct {
+	Namespace string
+	Name      string
+	// Since and Until bound Record.Time, inclusive.
+	Since time.Time
+	Until time.Time
+	// Limit caps the number of records returned, keeping the most recent ones.
+	Limit int
+}
+
+// Matches reports whether rec satisfies the query filters.
+func (q Query) Matches(rec Record) bool {
+	if q.Namespace != "" && rec.Namespace != q.Namespace {
+		return false
+	}
+	if q.Name != "" && rec.Name != q.Name {
+		return false
+	}
+	if !q.Since.IsZero() && rec.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && rec.Time.After(q.Until) {
+		return false
+	}
+	return true
+}
+
+// Store persists and queries audit records.
+type Store interface {
+	// Record appends rec to the store.
+	Record(ctx context.Context, rec Record) error
+	// Query returns the matching records, oldest first.
+	Query(ctx context.Context, q Query) ([]Record, error)
+}
+
+// NopStore discards every record. It is the default when auditing is disabled.
+type NopStore struct{}
+
+// Record implements Store.
+func (NopStore) Record(context.Context, Record) error { return nil }
+
+// Query implements Store.
+func (NopStore) Query(context.Context, Query) ([]Record, error) { return nil, nil }
+
+// Handler serves the records of store as JSON. The namespace, name, since,
+// until (RFC3339) and limit query parameters map onto Query.
+func Handler(store Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		params := req.URL.Query()
+		q := Query{
+			Namespace: params.Get("namespace"),
+			Name:      params.Get("name"),
+		}
+
+		var err error
+		if v := params.Get("since"); v != "" {
+			if q.Since, err = time.Parse(time.RFC3339, v); err != nil {
+				http.Error(w, "invalid since: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		if v := params.Get("until"); v != "" {
+			if q.Until, err = time.Parse(time.RFC3339, v); err != nil {
+				http.Error(w, "invalid until: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		if v := params.Get("limit"); v != "" {
+			if q.Limit, err = strconv.Atoi(v); err != nil {
+				http.Error(w, "invalid limit: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		records, err := store.Query(req.Context(), q)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if records == nil {
+			records = []Record{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(records)
+	})
+}
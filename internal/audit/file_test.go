@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FileStore", func() {
+	var (
+		ctx   context.Context
+		store *FileStore
+		base  time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		base = time.Date(2025, 5, 26, 3, 0, 0, 0, time.UTC)
+
+		var err error
+		store, err = NewFileStore(filepath.Join(GinkgoT().TempDir(), "audit.log"))
+		Expect(err).NotTo(HaveOccurred())
+
+		for i, name := range []string{"nginx", "redis", "nginx"} {
+			Expect(store.Record(ctx, Record{
+				Time:          base.Add(time.Duration(i) * time.Hour),
+				Namespace:     "default",
+				Name:          name,
+				Schedule:      "0 * * * *",
+				Selector:      "app=" + name,
+				ScheduledTime: base.Add(time.Duration(i) * time.Hour),
+				RestartedPods: []string{name + "-0"},
+			})).To(Succeed())
+		}
+	})
+
+	It("should read back every record in order", func() {
+		records, err := store.Query(ctx, Query{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(3))
+		Expect(records[0].Name).To(Equal("nginx"))
+		Expect(records[1].Name).To(Equal("redis"))
+		Expect(records[2].Time).To(BeTemporally("==", base.Add(2*time.Hour)))
+		Expect(records[2].RestartedPods).To(Equal([]string{"nginx-0"}))
+	})
+
+	It("should filter by name, time range and limit", func() {
+		records, err := store.Query(ctx, Query{Name: "nginx"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(2))
+
+		records, err = store.Query(ctx, Query{Since: base.Add(time.Hour), Until: base.Add(time.Hour)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Name).To(Equal("redis"))
+
+		records, err = store.Query(ctx, Query{Limit: 1})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Time).To(BeTemporally("==", base.Add(2*time.Hour)))
+	})
+
+	It("should keep records across store instances", func() {
+		reopened, err := NewFileStore(store.path)
+		Expect(err).NotTo(HaveOccurred())
+		records, err := reopened.Query(ctx, Query{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(3))
+	})
+
+	It("should serve queries over HTTP", func() {
+		rec := httptest.NewRecorder()
+		Handler(store).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/audit?name=redis", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		var records []Record
+		Expect(json.Unmarshal(rec.Body.Bytes(), &records)).To(Succeed())
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Selector).To(Equal("app=redis"))
+
+		rec = httptest.NewRecorder()
+		Handler(store).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/audit?since=yesterday", nil))
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+	})
+})
+
+var _ = Describe("NopStore", func() {
+	It("should discard records", func() {
+		Expect(NopStore{}.Record(context.Background(), Record{Name: "nginx"})).To(Succeed())
+		records, err := NopStore{}.Query(context.Background(), Query{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(BeEmpty())
+	})
+})
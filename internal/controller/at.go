@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// reconcileAt performs the one-time restart of Spec.At once it is due and
+// marks obj Completed when it is done. A completed object is left alone.
+func (r *AutoRestartPodReconciler) reconcileAt(ctx context.Context, obj *stablev1.AutoRestartPod) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	if meta.IsStatusConditionTrue(obj.Status.Conditions, stablev1.ConditionCompleted) {
+		return ctrl.Result{}, nil
+	}
+	if err := validateTarget(&obj.Spec); err != nil {
+		log.Error(err, "Invalid restart target")
+		return ctrl.Result{}, err
+	}
+
+	now := r.now()
+	at := obj.Spec.At.Time
+	if obj.Status.ActiveRun != nil {
+		scheduled := obj.Status.ActiveRun.ScheduledTime.Time
+		wait, err := r.continueRun(ctx, obj, now)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if wait > 0 {
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+		// A triggered restart that finished does not complete the object
+		if !scheduled.Equal(at) {
+			return ctrl.Result{RequeueAfter: max(at.Sub(now), 0)}, nil
+		}
+		return ctrl.Result{}, r.complete(ctx, obj)
+	}
+
+	// Like scheduled restarts, the restart runs up to restartWindow early
+	if wait := at.Sub(now); wait >= restartWindow {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+	log.Info("Performing one-time restart", "at", at.Format(time.RFC3339))
+	if err := r.claimRun(ctx, obj, now, at); err != nil {
+		return ctrl.Result{}, err
+	}
+	pending, err := r.restart(ctx, obj, now, at)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if pending {
+		return ctrl.Result{RequeueAfter: runRequeueInterval}, nil
+	}
+	return ctrl.Result{}, r.complete(ctx, obj)
+}
+
+// complete marks the one-time restart of obj as performed.
+func (r *AutoRestartPodReconciler) complete(ctx context.Context, obj *stablev1.AutoRestartPod) error {
+	meta.SetStatusCondition(&obj.Status.Conditions, metav1.Condition{
+		Type:    stablev1.ConditionCompleted,
+		Status:  metav1.ConditionTrue,
+		Reason:  "RestartPerformed",
+		Message: fmt.Sprintf("the one-time restart at %s was performed", obj.Spec.At.Format(time.RFC3339)),
+	})
+	if err := r.Status().Update(ctx, obj); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to update AutoRestartPod status")
+		return err
+	}
+	return nil
+}
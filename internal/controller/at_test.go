@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("One-time restarts", func() {
+	var (
+		ctx       context.Context
+		fakeClock *testingclock.FakePassiveClock
+		obj       *stablev1.AutoRestartPod
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fakeClock = testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 0, 0, 0, time.UTC))
+		obj = newTestAutoRestartPod("once", "")
+		obj.Spec.At = &metav1.Time{Time: time.Date(2025, 5, 26, 10, 30, 0, 0, time.UTC)}
+	})
+
+	It("should restart once at the given time and never again", func() {
+		pod := newTestPod("nginx-0")
+		c := newFakeClient(obj, pod)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		result, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(30 * time.Minute))
+		Expect(podExists(c, pod)).To(BeTrue())
+
+		fakeClock.SetTime(obj.Spec.At.Add(-10 * time.Second))
+		result, err = r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(podExists(c, pod)).To(BeFalse())
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, stablev1.ConditionCompleted)).To(BeTrue())
+		Expect(updated.Status.LastRestartTime.Time).To(BeTemporally("==", fakeClock.Now()))
+
+		// A completed object does not restart its pods again
+		replacement := newTestPod("nginx-1")
+		Expect(c.Create(ctx, replacement)).To(Succeed())
+		fakeClock.SetTime(obj.Spec.At.AddDate(0, 0, 1))
+		result, err = r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(podExists(c, replacement)).To(BeTrue())
+	})
+
+	It("should only complete once every pass of the restart is done", func() {
+		obj.Spec.MaxPodsPerRestart = ptr.To[int32](1)
+		c := newFakeClient(append(newAgedPods(2, fakeClock.Now()), obj)...)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		fakeClock.SetTime(obj.Spec.At.Time)
+		result, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(runRequeueInterval))
+		Expect(remainingPods(c)).To(ConsistOf("nginx-1"))
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(meta.FindStatusCondition(updated.Status.Conditions, stablev1.ConditionCompleted)).To(BeNil())
+
+		fakeClock.SetTime(fakeClock.Now().Add(runRequeueInterval))
+		_, err = r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(BeEmpty())
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, stablev1.ConditionCompleted)).To(BeTrue())
+	})
+})
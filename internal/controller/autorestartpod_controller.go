@@ -18,28 +18,125 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/robfig/cron/v3"
+	"go.opentelemetry.io/otel/trace"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+	"github.com/crazyfrankie/autorestart-operator/internal/audit"
+	"github.com/crazyfrankie/autorestart-operator/internal/podfields"
+	"github.com/crazyfrankie/autorestart-operator/internal/podmatch"
 )
 
 // AutoRestartPodReconciler reconciles a AutoRestartPod object
 type AutoRestartPodReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// APIReader reads from the API server what the manager's cache should
+	// not hold, such as pod metadata next to the cached pods. The Client is
+	// used when nil.
+	APIReader client.Reader
+
+	// Clock provides the current time. The real clock is used when nil.
+	Clock clock.PassiveClock
+
+	// DefaultTimeZone is the timezone of schedules that do not specify one.
+	// The clock's own location is used when nil.
+	DefaultTimeZone *time.Location
+
+	// Drainer deregisters pods before deletion when Spec.PreDrain is set.
+	// An HTTPEndpointDrainer is used when nil.
+	Drainer EndpointDrainer
+
+	// Notifier delivers Spec.NotificationWebhook notifications. An
+	// HTTPRestartNotifier is used when nil.
+	Notifier RestartNotifier
+
+	// Querier evaluates the Spec.SLAGate query. A PrometheusQuerier is used
+	// when nil.
+	Querier MetricQuerier
+
+	// Audit records every restart. Auditing is disabled when nil.
+	Audit audit.Store
+
+	// Breaker pauses restarts for every object after a burst of failures.
+	// The breaker is disabled when nil.
+	Breaker *CircuitBreaker
+
+	// TracerProvider provides the tracer of the spans recorded for every
+	// reconcile. The global tracer provider is used when nil.
+	TracerProvider trace.TracerProvider
+
+	// Recorder publishes events about restarts and problems with an
+	// object. No events are published when nil.
+	Recorder record.EventRecorder
+
+	// MirrorRecorder, when set, publishes a copy of every event about an
+	// object into one namespace, so restarts across namespaces can be
+	// followed in one place. See NewMirrorRecorder.
+	MirrorRecorder record.EventRecorder
+
+	// MaxConcurrentReconciles is the number of objects reconciled in
+	// parallel. Objects are reconciled one at a time when zero.
+	MaxConcurrentReconciles int
+
+	// DeleteConcurrency is how many pods of a restart pass are deleted in
+	// parallel. Pods are deleted one at a time when zero.
+	DeleteConcurrency int
+
+	// MaxRequeueInterval caps how long an object waits for its next
+	// reconcile, so far-off restarts are re-evaluated every so often. The
+	// wait is not capped when zero.
+	MaxRequeueInterval time.Duration
+
+	// locks serializes the reconciles of each object.
+	locks objectLocks
+
+	// retries counts the transient reconcile failures of each object.
+	retries retryCounts
+
+	// matchers caches the compiled Spec.MatchExpression of every object.
+	matchers podmatch.Cache
+
+	// started holds the UIDs of the objects restarted for Spec.RestartOnStart
+	// since this process started.
+	started sync.Map
 }
 
 // +kubebuilder:rbac:groups=stable.crazyfrank.com,resources=autorestartpods,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=stable.crazyfrank.com,resources=autorestartpods/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=stable.crazyfrank.com,resources=autorestartpods/finalizers,verbs=update
+// +kubebuilder:rbac:groups=stable.crazyfrank.com,resources=autorestartpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods/eviction,verbs=create
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=apps,resources=replicasets;daemonsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps;secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state,
@@ -50,6 +147,24 @@ type AutoRestartPodReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
 func (r *AutoRestartPodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// Reconciles of the same object may overlap when triggered from outside
+	// the controller's work queue
+	defer r.locks.lock(req.NamespacedName)()
+
+	ctx, span := r.tracer().Start(ctx, "Reconcile", trace.WithAttributes(objectKey.String(req.String())))
+	result, err := r.reconcile(ctx, req)
+	endSpan(span, err)
+	result, err = r.handleError(ctx, req.NamespacedName, result, err)
+	// Reconciling early never restarts early, the restart window still applies
+	if r.MaxRequeueInterval > 0 && result.RequeueAfter > r.MaxRequeueInterval {
+		result.RequeueAfter = r.MaxRequeueInterval
+	}
+	return result, err
+}
+
+// reconcile reconciles the AutoRestartPod named by req within the span of
+// Reconcile.
+func (r *AutoRestartPodReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 
 	// Fetch the AutoRestartPod instance
@@ -61,36 +176,267 @@ func (r *AutoRestartPodReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, err
 	}
 
+	// A deleted object only finishes what it already started, with the
+	// defaults of its policy. A run of an object whose policy is gone
+	// cannot be finished the way it started and is dropped instead.
+	if !obj.DeletionTimestamp.IsZero() {
+		if err := r.applyPolicy(ctx, obj); apierrors.IsNotFound(err) {
+			if err := r.dropRun(ctx, obj, err.Error()); err != nil {
+				return ctrl.Result{}, err
+			}
+		} else if err != nil {
+			return ctrl.Result{}, err
+		}
+		return r.finalize(ctx, obj)
+	}
+	if controllerutil.AddFinalizer(obj, restartFinalizer) {
+		if err := r.Update(ctx, obj); err != nil {
+			log.Error(err, "Failed to add finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Defaults of the referenced AutoRestartPolicy fill every unset field
+	if err := r.applyPolicy(ctx, obj); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	result, err := r.reconcileSchedule(ctx, obj)
+	if statusErr := r.updateReadiness(ctx, obj, err); statusErr != nil {
+		log.Error(statusErr, "Failed to update Ready condition")
+		if err == nil {
+			return ctrl.Result{}, statusErr
+		}
+	}
+	return result, err
+}
+
+// reconcileSchedule handles the top-level schedule and every restart rule of
+// obj and requeues for whichever is due first.
+func (r *AutoRestartPodReconciler) reconcileSchedule(ctx context.Context, obj *stablev1.AutoRestartPod) (ctrl.Result, error) {
+	// Deployments resumed for an earlier restart are checked until they
+	// can be paused again
+	rollingOut, err := r.repauseWorkloads(ctx, obj)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// A suspended object restarts nothing until a spec change resumes it
+	if err := r.updateSuspended(ctx, obj); err != nil {
+		return ctrl.Result{}, err
+	}
+	if obj.Spec.Suspend {
+		logf.FromContext(ctx).Info("Restarts are suspended")
+		if rollingOut {
+			return ctrl.Result{RequeueAfter: runRequeueInterval}, nil
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Past Spec.ActiveDeadline nothing is restarted anymore, once a restart
+	// in progress is finished
+	deadline, hasDeadline := activeDeadlineRemaining(obj, r.now())
+	if hasDeadline && deadline == 0 && obj.Status.ActiveRun == nil {
+		if err := r.expire(ctx, obj); err != nil {
+			return ctrl.Result{}, err
+		}
+		if rollingOut {
+			return ctrl.Result{RequeueAfter: runRequeueInterval}, nil
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Until Spec.PauseUntil has passed restarts are deferred as well
+	if wait := pauseRemaining(obj, r.now()); wait > 0 {
+		logf.FromContext(ctx).Info("Restarts are paused", "pauseUntil", obj.Spec.PauseUntil.Format(time.RFC3339))
+		if rollingOut {
+			wait = min(wait, runRequeueInterval)
+		}
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	// So does the first reconcile after the controller started
+	if r.startupDue(obj) {
+		pending, err := r.startupRestart(ctx, obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if pending {
+			return ctrl.Result{RequeueAfter: runRequeueInterval}, nil
+		}
+	}
+
+	// A changed restart trigger restarts right away, whatever the schedule
+	if triggered(obj) {
+		pending, err := r.triggerRestart(ctx, obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if pending {
+			return ctrl.Result{RequeueAfter: runRequeueInterval}, nil
+		}
+	}
+
+	// So does a rotation of the Secret restarted on change
+	if obj.Status.ActiveRun == nil {
+		rotated, err := r.secretRotated(ctx, obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if rotated {
+			pending, err := r.restartNow(ctx, obj)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if pending {
+				return ctrl.Result{RequeueAfter: runRequeueInterval}, nil
+			}
+		}
+	}
+
+	var result ctrl.Result
+	switch {
+	case obj.Spec.At != nil:
+		if result, err = r.reconcileAt(ctx, obj); err != nil {
+			return ctrl.Result{}, err
+		}
+	case hasRecurringSchedule(obj):
+		if result, err = r.reconcileDefaultSchedule(ctx, obj); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if len(obj.Spec.Restarts) > 0 {
+		wait, err := r.reconcileRules(ctx, obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if result.RequeueAfter == 0 || wait < result.RequeueAfter {
+			result.RequeueAfter = wait
+		}
+	}
+	if rollingOut && (result.RequeueAfter == 0 || runRequeueInterval < result.RequeueAfter) {
+		result.RequeueAfter = runRequeueInterval
+	}
+	// The object is marked Completed right when its deadline passes
+	if hasDeadline && deadline > 0 && (result.RequeueAfter == 0 || deadline < result.RequeueAfter) {
+		result.RequeueAfter = deadline
+	}
+	return result, nil
+}
+
+// reconcileDefaultSchedule restarts the pods selected by the top-level
+// fields of obj when its schedule is due and returns when the controller
+// should look at obj again.
+func (r *AutoRestartPodReconciler) reconcileDefaultSchedule(ctx context.Context,
+	obj *stablev1.AutoRestartPod) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Exactly one way of targeting pods must be configured
+	if err := validateTarget(&obj.Spec); err != nil {
+		log.Error(err, "Invalid restart target")
+		return ctrl.Result{}, err
+	}
+
 	// Parse the cron schedule expression from the AutoRestartPod spec
 	// This supports both standard 5-field cron format and 6-field format with seconds
-	schedule, err := parseCronSchedule(obj.Spec.Schedule)
+	// An environment label may select one of the alternate schedules
+	// A weekly pattern stands in for the cron expression
+	expression := effectiveSchedule(obj)
+	schedule, err := topLevelSchedule(obj)
 	if err != nil {
-		log.Error(err, "Failed to parse cron schedule", "schedule", obj.Spec.Schedule)
-		return ctrl.Result{}, err
+		log.Error(err, "Failed to parse cron schedule", "schedule", expression)
+		return ctrl.Result{}, &scheduleParseError{err: err}
 	}
+	if obj.Spec.AlignToMidnight {
+		schedule = alignToMidnight(schedule)
+	}
+	schedule = withJitter(obj, schedule)
 
 	// Get the current time, respecting the specified timezone if provided
-	var now time.Time
-	if obj.Spec.TimeZone != "" {
-		// Load the requested timezone
-		loc, err := time.LoadLocation(obj.Spec.TimeZone)
+	now, err := r.nowIn(obj.Spec.TimeZone)
+	if err != nil {
+		log.Error(err, "Failed to parse timezone", "timezone", obj.Spec.TimeZone)
+		return ctrl.Result{}, err
+	}
+
+	// A restart that was split over several passes is finished before anything else
+	if obj.Status.ActiveRun != nil {
+		wait, err := r.continueRun(ctx, obj, now)
 		if err != nil {
-			log.Error(err, "Failed to parse timezone", "timezone", obj.Spec.TimeZone)
 			return ctrl.Result{}, err
 		}
-		now = time.Now().In(loc)
-	} else {
-		// Use UTC time if no timezone is specified
-		now = time.Now()
+		if wait > 0 {
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+		return ctrl.Result{RequeueAfter: schedule.Next(now).Sub(now)}, nil
 	}
 
-	// Calculate the next scheduled run time based on the cron expression
+	// Objects annotated for debugging log what this reconcile decided
+	decision := newScheduleDecision(obj)
+	defer decision.log(ctx)
+
+	// Changes of the objects restarted on change count from the first reconcile
+	if err := r.observeChanges(ctx, obj); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Calculate the next scheduled run time based on the cron expression.
+	// Schedules with seconds are woken up right at their fire times, which
+	// count as due for a second after
+	window := fireWindow(obj)
 	nextRun := schedule.Next(now)
+	if window == 0 {
+		nextRun = schedule.Next(now.Add(-fireGranularity))
+	}
+	if err := r.updateTimeZoneCondition(ctx, obj, nextRun); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.updateScheduleStatus(ctx, obj, schedule, now); err != nil {
+		return ctrl.Result{}, err
+	}
 
 	// Special handling for e2e testing and immediate execution
 	// If the next run time is within the next minute, we should consider it as needing an immediate restart
 	// This helps with e2e testing where we set schedules very close to the current time
-	needsRestart := !nextRun.After(now) || nextRun.Sub(now) < time.Minute
+	needsRestart := !nextRun.After(now) || nextRun.Sub(now) < window
+
+	// Pod events can trigger several reconciles inside the same window; only
+	// the first one restarts for a given fire time
+	if needsRestart && restartedFor(obj, nextRun, window) {
+		log.Info("Restart for this fire time already performed", "nextRunTime", nextRun.Format(time.RFC3339))
+		decision.observe(ctx, r, obj, now, nextRun, needsRestart)
+		decision.skip("AlreadyRestarted")
+		return ctrl.Result{RequeueAfter: tickRequeue(schedule, nextRun, now, window)}, nil
+	}
+
+	// Fire times that passed while the controller was not running are
+	// caught up with a single restart for the latest of them, unless the
+	// next one is due anyway. A due fire time that already passed is not
+	// one of them.
+	missedBefore := now
+	if needsRestart && !nextRun.After(now) {
+		missedBefore = nextRun.Add(-time.Nanosecond)
+	}
+	if missed, latest := missedSchedules(obj, schedule, missedBefore); missed > 0 {
+		obj.Status.MissedSchedules = missed
+		switch {
+		case needsRestart:
+			log.Info("Missed fire times are caught up by the due one", "missed", missed)
+		case pastStartingDeadline(obj, latest, now):
+			log.Info("Not catching up missed fire times past the starting deadline",
+				"missed", missed, "latestFireTime", latest.Format(time.RFC3339))
+			decision.skip("PastStartingDeadline")
+			obj.Status.LastScheduleTime = &metav1.Time{Time: latest}
+			if err := r.Status().Update(ctx, obj); err != nil {
+				log.Error(err, "Failed to update AutoRestartPod status")
+				return ctrl.Result{}, err
+			}
+		default:
+			log.Info("Catching up missed fire times", "missed", missed, "latestFireTime", latest.Format(time.RFC3339))
+			nextRun = latest
+			needsRestart = true
+		}
+	}
 
 	// Log important time information for debugging
 	log.Info("Time calculations",
@@ -98,46 +444,112 @@ func (r *AutoRestartPodReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		"nextRunTime", nextRun.Format(time.RFC3339),
 		"timeDifference", nextRun.Sub(now).String(),
 		"needsRestart", needsRestart)
+	decision.observe(ctx, r, obj, now, nextRun, needsRestart)
 
 	if needsRestart {
-		// Update the LastRestartTime status field to record this restart event
-		obj.Status.LastRestartTime = &metav1.Time{Time: now}
-		if err := r.Status().Update(ctx, obj); err != nil {
-			log.Error(err, "Failed to update AutoRestartPod status")
+		// Refuse to restart again while the minimum interval since the last
+		// restart has not elapsed, and wake up once the cooldown is over
+		if remaining := cooldownRemaining(obj, now); remaining > 0 {
+			log.Info("Skipping restart during cooldown",
+				"minInterval", obj.Spec.MinInterval.Duration.String(),
+				"remaining", remaining.String())
+			decision.skip("Cooldown")
+			return ctrl.Result{RequeueAfter: remaining}, nil
+		}
+
+		// Ticks on a skip date pass without restarting
+		if onSkipDate(obj, nextRun) {
+			log.Info("Skipping tick on a skip date", "nextRunTime", nextRun.Format(time.RFC3339))
+			return r.skipTick(ctx, obj, decision, "SkipDate", nextRun, tickRequeue(schedule, nextRun, now, window))
+		}
+
+		// Ticks outside every time window pass without restarting
+		inWindow, err := inTimeWindow(obj, nextRun)
+		if err != nil {
+			log.Error(err, "Invalid time window")
 			return ctrl.Result{}, err
 		}
+		if !inWindow {
+			log.Info("Skipping tick outside the time windows", "nextRunTime", nextRun.Format(time.RFC3339))
+			return r.skipTick(ctx, obj, decision, "OutsideTimeWindows", nextRun, tickRequeue(schedule, nextRun, now, window))
+		}
 
-		// Get all pods that match the selector specified in the AutoRestartPod
-		podList := &corev1.PodList{}
-		selector, _ := metav1.LabelSelectorAsSelector(&obj.Spec.Selector)
-		if err = r.List(ctx, podList, client.InNamespace(req.Namespace),
-			client.MatchingLabelsSelector{Selector: selector}); err != nil {
-			log.Error(err, "Failed to list pods", "selector", selector.String())
+		// A restart probability lets ticks pass without restarting
+		restarts, err := tickRestarts(obj, nextRun)
+		if err != nil {
+			log.Error(err, "Invalid restart probability")
 			return ctrl.Result{}, err
 		}
+		if !restarts {
+			log.Info("Skipping tick by restart probability",
+				"restartProbability", obj.Spec.RestartProbability,
+				"nextRunTime", nextRun.Format(time.RFC3339))
+			return r.skipTick(ctx, obj, decision, "RestartProbability", nextRun, tickRequeue(schedule, nextRun, now, window))
+		}
 
-		// Delete each matching pod to trigger a restart
-		// Kubernetes will automatically recreate these pods if they're managed by controllers like Deployment, ReplicaSet, etc.
-		for _, pod := range podList.Items {
-			if err := r.Delete(ctx, &pod); err != nil {
-				log.Error(err, "Failed to delete pod", "pod", pod.Name)
-			} else {
-				log.Info("Restarted pod", "pod", pod.Name)
-			}
+		// Refuse to fire once the restart budget of the trailing window is
+		// used up, no matter what the schedule says
+		wait := budgetRemaining(obj, now)
+		setRateLimited(obj, now, wait)
+		if wait > 0 {
+			log.Info("Skipping restart, restart budget exhausted",
+				"maxRestartsPerWindow", *obj.Spec.MaxRestartsPerWindow,
+				"window", obj.Spec.Window.Duration.String())
+			return r.skipTick(ctx, obj, decision, "BudgetExhausted", nextRun, tickRequeue(schedule, nextRun, now, window))
+		}
+
+		// Ticks pass without restarting while the objects restarted on
+		// change stay the same
+		changed, err := r.changedSinceRestart(ctx, obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !changed {
+			log.Info("Skipping tick, the objects restarted on change are unchanged",
+				"nextRunTime", nextRun.Format(time.RFC3339))
+			return r.skipTick(ctx, obj, decision, "Unchanged", nextRun, tickRequeue(schedule, nextRun, now, window))
+		}
+		// Ticks pass without restarting while too few of the pods are Ready
+		healthy, err := r.updateHealth(ctx, obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !healthy {
+			log.Info("Skipping tick, too few pods are Ready",
+				"minHealthyFraction", obj.Spec.MinHealthyFraction,
+				"nextRunTime", nextRun.Format(time.RFC3339))
+			return r.skipTick(ctx, obj, decision, "TooFewReady", nextRun, tickRequeue(schedule, nextRun, now, window))
+		}
+		recordRestart(obj, now)
+
+		// The fire is claimed before any pod is restarted
+		if err := r.claimRun(ctx, obj, now, nextRun); err != nil {
+			return ctrl.Result{}, err
+		}
+		decision.fire()
+		pending, err := r.restart(ctx, obj, now, nextRun)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if pending {
+			return ctrl.Result{RequeueAfter: runRequeueInterval}, nil
 		}
 
 		// Recalculate the next run time after this execution
 		nextRun = schedule.Next(now)
 	} else {
+		decision.skip("NotDue")
 		// If this is the first reconciliation and no restart is needed yet,
-		// initialize the LastRestartTime field to ensure it's not nil
-		// This helps pass unit tests and provides a starting point for tracking
-		if obj.Status.LastRestartTime == nil {
-			obj.Status.LastRestartTime = &metav1.Time{Time: now}
+		// mark the object initialized, which drift is tracked from until the
+		// first restart; LastRestartTime is only set by restarts
+		if obj.Status.LastRestartTime == nil && obj.Status.Initialized == nil {
+			obj.Status.Initialized = &metav1.Time{Time: now}
 			if err := r.Status().Update(ctx, obj); err != nil {
-				log.Error(err, "Failed to initialize LastRestartTime status")
+				log.Error(err, "Failed to initialize AutoRestartPod status")
 				return ctrl.Result{}, err
 			}
+		} else if err := r.updateDrift(ctx, obj, schedule, now); err != nil {
+			return ctrl.Result{}, err
 		}
 	}
 
@@ -147,6 +559,125 @@ func (r *AutoRestartPodReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	return ctrl.Result{RequeueAfter: nextRun.Sub(now)}, nil
 }
 
+// skipTick lets the fire time nextRun pass without restarting for reason.
+// The tick counts as scheduled so that it is not taken again, and obj is
+// looked at again after requeue.
+func (r *AutoRestartPodReconciler) skipTick(ctx context.Context, obj *stablev1.AutoRestartPod,
+	decision *scheduleDecision, reason string, nextRun time.Time, requeue time.Duration) (ctrl.Result, error) {
+	decision.skip(reason)
+	obj.Status.LastScheduleTime = &metav1.Time{Time: nextRun}
+	if err := r.Status().Update(ctx, obj); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to update AutoRestartPod status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: requeue}, nil
+}
+
+// continueRun performs the next pass of Status.ActiveRun, or checks on the
+// replacements it awaits, and returns how long to wait before looking at the
+// run again. A zero wait means the run is finished.
+func (r *AutoRestartPodReconciler) continueRun(ctx context.Context, obj *stablev1.AutoRestartPod,
+	now time.Time) (time.Duration, error) {
+	// The next run is only scheduled once the restarted pods are back
+	if obj.Status.ActiveRun.AwaitingReplacements {
+		return r.awaitReplacements(ctx, obj, now)
+	}
+	if runTimedOut(obj, now) {
+		return 0, r.stopTimedOutRun(ctx, obj, now)
+	}
+
+	// Pod events wake the controller up early; keep the passes apart
+	if last := obj.Status.LastRestartTime; last != nil {
+		if wait := last.Add(runRequeueInterval).Sub(now); wait > 0 {
+			return wait, nil
+		}
+	}
+	pending, err := r.restart(ctx, obj, now, obj.Status.ActiveRun.ScheduledTime.Time)
+	if err != nil {
+		return 0, err
+	}
+	if pending {
+		return runRequeueInterval, nil
+	}
+	return 0, nil
+}
+
+// restartWindow is how long before a fire time a restart is already performed.
+const restartWindow = time.Minute
+
+// fireWindow returns how long before a fire time of the top-level schedule
+// of obj its restart is performed. Schedules with seconds may fire more than
+// once inside restartWindow and are restarted at their exact fire times.
+func fireWindow(obj *stablev1.AutoRestartPod) time.Duration {
+	if scheduleFormat(obj) == stablev1.SecondsScheduleFormat {
+		return 0
+	}
+	return restartWindow
+}
+
+// fireGranularity is the resolution of fire times. Cron expressions and
+// jitter fire on whole seconds, so fire times are compared at it to keep
+// the sub-second part of a time from telling the same fire apart.
+const fireGranularity = time.Second
+
+// restartedFor reports whether the controller already reacted to the fire
+// time nextRun, or the last restart happened inside its window, which
+// starts window before it.
+func restartedFor(obj *stablev1.AutoRestartPod, nextRun time.Time, window time.Duration) bool {
+	fire := nextRun.Truncate(fireGranularity)
+	if scheduled := obj.Status.LastScheduleTime; scheduled != nil && !scheduled.Truncate(fireGranularity).Before(fire) {
+		return true
+	}
+	last := obj.Status.LastRestartTime
+	return last != nil && !last.Time.Before(nextRun.Add(-window))
+}
+
+// apiReader returns the APIReader, or the Client when none is configured.
+func (r *AutoRestartPodReconciler) apiReader() client.Reader {
+	if r.APIReader == nil {
+		return r.Client
+	}
+	return r.APIReader
+}
+
+// now returns the current time from the configured clock.
+func (r *AutoRestartPodReconciler) now() time.Time {
+	if r.Clock == nil {
+		return time.Now()
+	}
+	return r.Clock.Now()
+}
+
+// nowIn returns the current time in the named timezone, or in
+// DefaultTimeZone when timeZone is empty.
+func (r *AutoRestartPodReconciler) nowIn(timeZone string) (time.Time, error) {
+	if timeZone == "" {
+		if r.DefaultTimeZone != nil {
+			return r.now().In(r.DefaultTimeZone), nil
+		}
+		return r.now(), nil
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return r.now().In(loc), nil
+}
+
+// cooldownRemaining returns how long the AutoRestartPod must still wait before
+// another restart is allowed by Spec.MinInterval. It returns zero when no
+// cooldown is configured, no restart has happened yet, or the cooldown is over.
+func cooldownRemaining(obj *stablev1.AutoRestartPod, now time.Time) time.Duration {
+	if obj.Spec.MinInterval == nil || obj.Status.LastRestartTime == nil {
+		return 0
+	}
+	remaining := obj.Status.LastRestartTime.Add(obj.Spec.MinInterval.Duration).Sub(now)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
 // parseCronSchedule parses cron expressions in various formats.
 // It supports two different cron formats:
 // 1. Standard 5-field cron format: minute hour day month weekday (e.g., "*/5 * * * *")
@@ -154,25 +685,153 @@ func (r *AutoRestartPodReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 // The function first attempts to parse using the standard 5-field format.
 // If that fails, it falls back to the extended 6-field format.
 // This provides flexibility for users who may be familiar with different cron formats.
+// Cron expressions fire once for every matching wall clock time, also
+// across DST changes; see wallClockSchedule.
 func parseCronSchedule(schedule string) (cron.Schedule, error) {
+	sch, _, err := parseCronScheduleFormat(schedule)
+	return sch, err
+}
+
+// parseCronScheduleFormat parses schedule like parseCronSchedule and also
+// returns which format it was read in. Descriptors like "@daily" are read
+// by the standard parser; "@every" intervals fire on fixed multiples of the
+// interval, see everySchedule, and are read with seconds unless the
+// interval is in whole minutes.
+func parseCronScheduleFormat(schedule string) (cron.Schedule, stablev1.ScheduleFormat, error) {
 	// First try with standard 5-field cron format
 	standardParser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
 	if sch, err := standardParser.Parse(schedule); err == nil {
-		return sch, nil
+		if every, ok := sch.(cron.ConstantDelaySchedule); ok {
+			format := stablev1.StandardScheduleFormat
+			if every.Delay%time.Minute != 0 {
+				format = stablev1.SecondsScheduleFormat
+			}
+			return everySchedule{interval: every.Delay}, format, nil
+		}
+		return newWallClockSchedule(sch), stablev1.StandardScheduleFormat, nil
+	} else if strings.HasPrefix(schedule, "@") {
+		// Descriptors mean the same to the parser with seconds
+		return nil, "", err
 	}
 
 	// Then try with 6-field format that includes seconds
 	parser := cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
-	return parser.Parse(schedule)
+	sch, err := parser.Parse(schedule)
+	if err != nil {
+		return nil, "", err
+	}
+	return newWallClockSchedule(sch), stablev1.SecondsScheduleFormat, nil
+}
+
+// requiredKinds are the kinds the controller reads or writes, whichever
+// strategy an AutoRestartPod chooses.
+var requiredKinds = []schema.GroupVersionKind{
+	stablev1.GroupVersion.WithKind("AutoRestartPod"),
+	stablev1.GroupVersion.WithKind("AutoRestartPolicy"),
+	corev1.SchemeGroupVersion.WithKind("Pod"),
+	corev1.SchemeGroupVersion.WithKind("Node"),
+	corev1.SchemeGroupVersion.WithKind("Event"),
+	appsv1.SchemeGroupVersion.WithKind("Deployment"),
+	appsv1.SchemeGroupVersion.WithKind("StatefulSet"),
+	appsv1.SchemeGroupVersion.WithKind("ReplicaSet"),
+}
+
+// checkScheme returns an error naming every required kind scheme does not
+// register.
+func checkScheme(scheme *runtime.Scheme) error {
+	var missing []string
+	for _, gvk := range requiredKinds {
+		if !scheme.Recognizes(gvk) {
+			missing = append(missing, gvk.GroupVersion().String()+" "+gvk.Kind)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("scheme lacks types required by the AutoRestartPod controller: %s",
+			strings.Join(missing, ", "))
+	}
+	return nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 // This function configures how the controller is built and registered with the manager.
 // It specifies that this controller should manage AutoRestartPod resources and
 // assigns a unique name to the controller for metrics and logging purposes.
+// Pod events are mapped back to the AutoRestartPods selecting the pod so that
+// in-progress restarts react to replacements instead of polling, and policy
+// events to the AutoRestartPods referencing the policy.
+// The manager's scheme is checked up front so that a missing type fails the
+// setup rather than every reconcile.
 func (r *AutoRestartPodReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := checkScheme(mgr.GetScheme()); err != nil {
+		return err
+	}
+	// Spec.FieldSelector is answered from these indexes of the pod cache
+	if err := podfields.Register(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		return err
+	}
+	if err := registerPodOwnerIndex(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&stablev1.AutoRestartPod{}).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.podToRequests)).
+		Watches(&stablev1.AutoRestartPolicy{}, handler.EnqueueRequestsFromMapFunc(r.policyToRequests)).
+		// Secret data is never cached, a rotation is noticed from the change
+		// of its metadata and the Secret read when hashing it
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.secretToRequests), builder.OnlyMetadata).
 		Named("autorestartpod").
+		WithOptions(r.controllerOptions()).
 		Complete(r)
 }
+
+// controllerOptions returns the options of the controller running r.
+func (r *AutoRestartPodReconciler) controllerOptions() controller.Options {
+	return controller.Options{
+		MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+		RateLimiter:             terminalRateLimiter(),
+	}
+}
+
+// podToRequests returns a reconcile request for every AutoRestartPod
+// restarting pods in the pod's namespace whose selector or target workload
+// matches the pod.
+func (r *AutoRestartPodReconciler) podToRequests(ctx context.Context, pod client.Object) []reconcile.Request {
+	log := logf.FromContext(ctx)
+
+	// Objects of other namespaces may list the pod's in Spec.Namespaces
+	list := &stablev1.AutoRestartPodList{}
+	if err := r.List(ctx, list); err != nil {
+		log.Error(err, "Failed to list AutoRestartPods for pod", "pod", pod.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range list.Items {
+		obj := &list.Items[i]
+		if !slices.Contains(podNamespaces(obj), pod.GetNamespace()) {
+			continue
+		}
+		var selector labels.Selector
+		switch {
+		case obj.Spec.TargetRef != nil:
+			_, resolved, err := r.resolveTargetRef(ctx, obj)
+			if err != nil {
+				continue
+			}
+			selector = resolved
+		case obj.Spec.Selector != nil:
+			resolved, err := metav1.LabelSelectorAsSelector(obj.Spec.Selector)
+			if err != nil {
+				continue
+			}
+			selector = resolved
+		default:
+			continue
+		}
+
+		if selector.Matches(labels.Set(pod.GetLabels())) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+		}
+	}
+	return requests
+}
@@ -18,16 +18,35 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	testingclock "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+	"github.com/crazyfrankie/autorestart-operator/internal/audit"
 )
 
 var _ = Describe("AutoRestartPod Controller", func() {
@@ -53,7 +72,7 @@ var _ = Describe("AutoRestartPod Controller", func() {
 					},
 					Spec: stablev1.AutoRestartPodSpec{
 						Schedule: "*/5 * * * *",
-						Selector: metav1.LabelSelector{
+						Selector: &metav1.LabelSelector{
 							MatchLabels: map[string]string{
 								"app": "nginx",
 							},
@@ -90,8 +109,8 @@ var _ = Describe("AutoRestartPod Controller", func() {
 			err = k8sClient.Get(ctx, typeNamespacedName, updatedResource)
 			Expect(err).NotTo(HaveOccurred())
 
-			// Check if LastRestartTime was set, which indicates successful reconciliation
-			Expect(updatedResource.Status.LastRestartTime).NotTo(BeNil(), "LastRestartTime should be set after reconciliation")
+			// Check if Initialized was set, which indicates successful reconciliation
+			Expect(updatedResource.Status.Initialized).NotTo(BeNil(), "Initialized should be set after reconciliation")
 
 			// Verify the requeue time is set for the next scheduled run
 			// Note: This test is simplified, in a real test you might mock time.Now() to control timing
@@ -117,4 +136,332 @@ var _ = Describe("AutoRestartPod Controller", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
+
+	Context("When no restart is due on the first reconcile", func() {
+		It("should only mark the resource initialized until a real restart", func() {
+			ctx := context.Background()
+			obj := newTestAutoRestartPod("initialized", "*/5 * * * *")
+			pod := newTestPod("nginx-0")
+
+			// 10:02:00 is well before the 10:05 fire time
+			fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 2, 0, 0, time.UTC))
+			c := newFakeClient(obj, pod)
+			r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+			for _, at := range []time.Time{fakeClock.Now(), fakeClock.Now().Add(time.Minute)} {
+				fakeClock.SetTime(at)
+				_, err := r.Reconcile(ctx, requestFor(obj))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(podExists(c, pod)).To(BeTrue())
+
+				updated := &stablev1.AutoRestartPod{}
+				Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+				Expect(updated.Status.LastRestartTime).To(BeNil(), "no pods were restarted yet")
+				Expect(updated.Status.Initialized.Time).To(BeTemporally("==", time.Date(2025, 5, 26, 10, 2, 0, 0, time.UTC)))
+			}
+
+			fakeClock.SetTime(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+			_, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, pod)).To(BeFalse())
+
+			updated := &stablev1.AutoRestartPod{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+			Expect(updated.Status.LastRestartTime.Time).To(BeTemporally("==", fakeClock.Now()))
+			Expect(updated.Status.Initialized.Time).To(BeTemporally("==", time.Date(2025, 5, 26, 10, 2, 0, 0, time.UTC)))
+		})
+	})
+
+	Context("When a minimum interval is configured", func() {
+		It("should only restart once within the cooldown", func() {
+			ctx := context.Background()
+			obj := newTestAutoRestartPod("cooldown", "*/5 * * * *")
+			obj.Spec.MinInterval = &metav1.Duration{Duration: 10 * time.Minute}
+			pod := newTestPod("nginx-0")
+
+			// 10:04:30 is within a minute of the 10:05 fire time, so the
+			// restart is eligible
+			fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+			c := newFakeClient(obj, pod)
+			r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+			_, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, pod)).To(BeFalse(), "the first eligible restart should proceed")
+
+			updated := &stablev1.AutoRestartPod{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+			Expect(updated.Status.LastRestartTime.Time).To(BeTemporally("==", fakeClock.Now()))
+
+			// The replacement pod shows up and the 10:10 fire becomes eligible,
+			// but the 10 minute cooldown has not elapsed yet
+			Expect(c.Create(ctx, newTestPod("nginx-0"))).To(Succeed())
+			fakeClock.SetTime(time.Date(2025, 5, 26, 10, 9, 30, 0, time.UTC))
+
+			result, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, pod)).To(BeTrue(), "the second restart should be refused during the cooldown")
+			Expect(result.RequeueAfter).To(Equal(5 * time.Minute))
+		})
+	})
+
+	Context("When a maximum requeue interval is configured", func() {
+		It("should requeue a far-off restart after the interval without restarting", func() {
+			ctx := context.Background()
+			obj := newTestAutoRestartPod("yearly", "0 0 1 1 *")
+			pod := newTestPod("nginx-0")
+			c := newFakeClient(obj, pod)
+			r := newTestReconciler(c, time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+			r.MaxRequeueInterval = time.Hour
+
+			result, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Hour))
+			Expect(podExists(c, pod)).To(BeTrue())
+
+			// A restart due sooner than the interval is not delayed
+			r.MaxRequeueInterval = 24 * 365 * time.Hour
+			result, err = r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 24*time.Hour))
+			Expect(result.RequeueAfter).To(BeNumerically("<", r.MaxRequeueInterval))
+		})
+	})
+
+	Context("When an audit store is configured", func() {
+		It("should record the restart with its context", func() {
+			ctx := context.Background()
+			obj := newTestAutoRestartPod("audited", "*/5 * * * *")
+			store, err := audit.NewFileStore(filepath.Join(GinkgoT().TempDir(), "audit.log"))
+			Expect(err).NotTo(HaveOccurred())
+
+			c := newFakeClient(obj, newTestPod("nginx-0"))
+			r := newTestReconciler(c, time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+			r.Audit = store
+			_, err = r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+
+			records, err := store.Query(ctx, audit.Query{Name: "audited"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(1))
+			Expect(records[0].RestartedPods).To(Equal([]string{"nginx-0"}))
+			Expect(records[0].Selector).To(Equal("app=nginx"))
+			Expect(records[0].ScheduledTime).To(BeTemporally("==", time.Date(2025, 5, 26, 10, 5, 0, 0, time.UTC)))
+		})
+	})
+
+	Context("When matching pods change", func() {
+		var (
+			ctx   context.Context
+			r     *AutoRestartPodReconciler
+			queue workqueue.TypedRateLimitingInterface[reconcile.Request]
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			nginx := newTestAutoRestartPod("nginx-restart", "0 3 * * *")
+			other := newTestAutoRestartPod("redis-restart", "0 3 * * *")
+			other.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "redis"}}
+			targeted := newTestAutoRestartPod("deploy-restart", "0 3 * * *")
+			targeted.Spec.Selector = nil
+			targeted.Spec.TargetRef = &stablev1.TargetReference{Kind: "Deployment", Name: "nginx"}
+
+			c := newFakeClient(nginx, other, targeted, newTestDeployment("nginx"))
+			r = &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme()}
+			queue = workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+			DeferCleanup(queue.ShutDown)
+		})
+
+		It("should map a pod to the AutoRestartPods selecting it", func() {
+			requests := r.podToRequests(ctx, newTestPod("nginx-0"))
+			Expect(requests).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "nginx-restart"}},
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "deploy-restart"}},
+			))
+		})
+
+		It("should enqueue the owning AutoRestartPods on pod create and update", func() {
+			eventHandler := handler.EnqueueRequestsFromMapFunc(r.podToRequests)
+			pod := newTestPod("nginx-0")
+
+			eventHandler.Create(ctx, event.CreateEvent{Object: pod}, queue)
+			Expect(queue.Len()).To(Equal(2))
+
+			updated := pod.DeepCopy()
+			updated.Labels = map[string]string{"app": "redis"}
+			eventHandler.Update(ctx, event.UpdateEvent{ObjectOld: pod, ObjectNew: updated}, queue)
+			Expect(queue.Len()).To(Equal(3), "the redis AutoRestartPod is enqueued in addition")
+		})
+
+		It("should ignore pods in other namespaces", func() {
+			pod := newTestPod("nginx-0")
+			pod.Namespace = "other"
+			Expect(r.podToRequests(ctx, pod)).To(BeEmpty())
+		})
+	})
+
+	Context("When reconciled repeatedly inside a restart window", func() {
+		It("should restart only once for the same fire time", func() {
+			ctx := context.Background()
+			obj := newTestAutoRestartPod("once", "*/5 * * * *")
+			fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+			c := newFakeClient(obj, newTestPod("nginx-0"))
+			r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+			_, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, newTestPod("nginx-0"))).To(BeFalse())
+
+			// A pod event for the replacement re-triggers the reconcile
+			Expect(c.Create(ctx, newTestPod("nginx-1"))).To(Succeed())
+			fakeClock.SetTime(fakeClock.Now().Add(10 * time.Second))
+			_, err = r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, newTestPod("nginx-1"))).To(BeTrue())
+		})
+
+		It("should restart once however often a watch storm reconciles", func() {
+			ctx := context.Background()
+			obj := newTestAutoRestartPod("storm", "*/5 * * * *")
+			fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 1, 0, time.UTC))
+			var deletes int
+			c := newFakeClientBuilder(obj).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Delete: func(ctx context.Context, c client.WithWatch, o client.Object, opts ...client.DeleteOption) error {
+						deletes++
+						return c.Delete(ctx, o, opts...)
+					},
+				}).Build()
+			r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+			// Every reconcile of the window finds a fresh replacement pod
+			for i := range 10 {
+				Expect(c.Create(ctx, newTestPod(fmt.Sprintf("nginx-%d", i)))).To(Succeed())
+				_, err := r.Reconcile(ctx, requestFor(obj))
+				Expect(err).NotTo(HaveOccurred())
+				fakeClock.SetTime(fakeClock.Now().Add(5*time.Second + 300*time.Millisecond))
+			}
+			Expect(deletes).To(Equal(1))
+		})
+	})
+
+	Context("When the selector does not parse", func() {
+		It("should report the error and delete nothing", func() {
+			ctx := context.Background()
+			obj := newTestAutoRestartPod("invalid-selector", "*/5 * * * *")
+			obj.Spec.Selector.MatchExpressions = []metav1.LabelSelectorRequirement{
+				{Key: "tier", Operator: "Near", Values: []string{"web"}},
+			}
+			pod := newTestPod("nginx-0")
+			c := newFakeClient(obj, pod)
+			recorder := record.NewFakeRecorder(10)
+			r := newTestReconciler(c, time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+			r.Recorder = recorder
+
+			_, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).To(MatchError(ContainSubstring("invalid selector")))
+			Expect(podExists(c, pod)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(HavePrefix("Warning InvalidSelector")))
+
+			updated := &stablev1.AutoRestartPod{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+			Expect(meta.IsStatusConditionFalse(updated.Status.Conditions, stablev1.ConditionReady)).To(BeTrue())
+		})
+	})
+
+	Context("When the manager's scheme lacks required types", func() {
+		// newManager returns a manager using s that never contacts an API server.
+		newManager := func(s *runtime.Scheme) ctrl.Manager {
+			mgr, err := ctrl.NewManager(&rest.Config{Host: "http://127.0.0.1:0"}, ctrl.Options{
+				Scheme:                 s,
+				Metrics:                metricsserver.Options{BindAddress: "0"},
+				HealthProbeBindAddress: "0",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			return mgr
+		}
+
+		It("should fail setup naming every missing type", func() {
+			incomplete := runtime.NewScheme()
+			Expect(stablev1.AddToScheme(incomplete)).To(Succeed())
+			Expect(corev1.AddToScheme(incomplete)).To(Succeed())
+
+			r := &AutoRestartPodReconciler{}
+			err := r.SetupWithManager(newManager(incomplete))
+			Expect(err).To(MatchError(ContainSubstring("scheme lacks types required by the AutoRestartPod controller")))
+			Expect(err).To(MatchError(ContainSubstring("apps/v1 Deployment, apps/v1 StatefulSet, apps/v1 ReplicaSet")))
+			Expect(err).NotTo(MatchError(ContainSubstring("Pod,")))
+		})
+
+		It("should accept a scheme with every required type", func() {
+			Expect(checkScheme(scheme.Scheme)).To(Succeed())
+
+			coreOnly := runtime.NewScheme()
+			Expect(corev1.AddToScheme(coreOnly)).To(Succeed())
+			Expect(checkScheme(coreOnly)).To(MatchError(ContainSubstring("stable.crazyfrank.com/v1 AutoRestartPod")))
+		})
+	})
 })
+
+// newFakeClient returns a fake client seeded with objs which serves the
+// AutoRestartPod status subresource.
+func newFakeClient(objs ...client.Object) client.Client {
+	return newFakeClientBuilder(objs...).Build()
+}
+
+// newTestReconciler returns a reconciler of c whose clock stands still at now.
+func newTestReconciler(c client.Client, now time.Time) *AutoRestartPodReconciler {
+	return &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: testingclock.NewFakePassiveClock(now)}
+}
+
+// newFakeClientBuilder returns a fake client builder seeded with objs so tests
+// can add interceptors before building.
+func newFakeClientBuilder(objs ...client.Object) *fake.ClientBuilder {
+	return fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&stablev1.AutoRestartPod{})
+}
+
+// newTestAutoRestartPod returns an AutoRestartPod in the default namespace
+// selecting pods labeled app=nginx.
+func newTestAutoRestartPod(name, schedule string) *stablev1.AutoRestartPod {
+	return &stablev1.AutoRestartPod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: stablev1.AutoRestartPodSpec{
+			Schedule: schedule,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "nginx"},
+			},
+		},
+	}
+}
+
+// newTestPod returns a pod labeled app=nginx in the default namespace.
+func newTestPod(name string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{"app": "nginx"},
+		},
+	}
+}
+
+// requestFor returns the reconcile request for obj.
+func requestFor(obj client.Object) reconcile.Request {
+	return reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)}
+}
+
+// podExists reports whether pod is still present in c.
+func podExists(c client.Client, pod *corev1.Pod) bool {
+	err := c.Get(context.Background(), client.ObjectKeyFromObject(pod), &corev1.Pod{})
+	if errors.IsNotFound(err) {
+		return false
+	}
+	Expect(err).NotTo(HaveOccurred())
+	return true
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// transientBackoff and maxTransientBackoff bound the wait before a
+	// reconcile that failed with a transient error is retried.
+	transientBackoff    = time.Second
+	maxTransientBackoff = time.Minute
+
+	// terminalBackoff and maxTerminalBackoff bound the wait before a
+	// reconcile that failed for good is retried, e.g. for an invalid spec.
+	terminalBackoff    = 5 * time.Second
+	maxTerminalBackoff = 15 * time.Minute
+)
+
+// isTransient reports whether err is expected to go away by itself, like
+// timeouts, conflicting updates and an overloaded API server.
+func isTransient(err error) bool {
+	return apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err) || apierrors.IsConflict(err) ||
+		apierrors.IsTooManyRequests(err) || apierrors.IsServiceUnavailable(err) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// handleError decides how the reconcile of key that returned result and err
+// is retried. Transient errors are retried after a capped exponential
+// backoff of their own; other errors are returned, so the controller retries
+// them after the longer backoff of terminalRateLimiter.
+func (r *AutoRestartPodReconciler) handleError(ctx context.Context, key types.NamespacedName,
+	result ctrl.Result, err error) (ctrl.Result, error) {
+	if err == nil || !isTransient(err) {
+		r.retries.reset(key)
+		return result, err
+	}
+
+	wait := transientBackoff << min(r.retries.next(key)-1, 16)
+	wait = min(wait, maxTransientBackoff)
+	logf.FromContext(ctx).Info("Retrying after transient error", "error", err.Error(), "retryAfter", wait.String())
+	return ctrl.Result{RequeueAfter: wait}, nil
+}
+
+// terminalRateLimiter returns the rate limiter retrying the reconciles that
+// failed with an error that is not transient.
+func terminalRateLimiter() workqueue.TypedRateLimiter[reconcile.Request] {
+	return workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](terminalBackoff, maxTerminalBackoff)
+}
+
+// retryCounts counts the transient failures in a row of each object. The
+// zero value is ready to use.
+type retryCounts struct {
+	mu     sync.Mutex
+	counts map[types.NamespacedName]int
+}
+
+// next counts another failure of key and returns how many there were in a row.
+func (c *retryCounts) next(key types.NamespacedName) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = map[types.NamespacedName]int{}
+	}
+	c.counts[key]++
+	return c.counts[key]
+}
+
+// reset forgets the failures of key.
+func (c *retryCounts) reset(key types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.counts, key)
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Error backoff", func() {
+	It("should tell transient errors apart", func() {
+		pods := schema.GroupResource{Resource: "pods"}
+		Expect(isTransient(apierrors.NewTimeoutError("slow", 1))).To(BeTrue())
+		Expect(isTransient(apierrors.NewConflict(pods, "nginx", errors.New("modified")))).To(BeTrue())
+		Expect(isTransient(apierrors.NewTooManyRequests("busy", 1))).To(BeTrue())
+		Expect(isTransient(apierrors.NewServiceUnavailable("down"))).To(BeTrue())
+		Expect(isTransient(context.DeadlineExceeded)).To(BeTrue())
+
+		Expect(isTransient(apierrors.NewForbidden(pods, "nginx", errors.New("denied")))).To(BeFalse())
+		Expect(isTransient(errors.New("invalid schedule"))).To(BeFalse())
+	})
+
+	It("should back off exponentially up to the cap and start over after a success", func() {
+		r := &AutoRestartPodReconciler{}
+		key := client.ObjectKey{Namespace: "default", Name: "flaky"}
+		busy := apierrors.NewTooManyRequests("busy", 1)
+
+		var waits []time.Duration
+		for range 8 {
+			result, err := r.handleError(context.Background(), key, ctrl.Result{}, busy)
+			Expect(err).NotTo(HaveOccurred())
+			waits = append(waits, result.RequeueAfter)
+		}
+		Expect(waits).To(Equal([]time.Duration{
+			time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second,
+			16 * time.Second, 32 * time.Second, time.Minute, time.Minute,
+		}))
+
+		result, err := r.handleError(context.Background(), key, ctrl.Result{RequeueAfter: time.Hour}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(time.Hour))
+		result, _ = r.handleError(context.Background(), key, ctrl.Result{}, busy)
+		Expect(result.RequeueAfter).To(Equal(time.Second))
+	})
+
+	It("should retry a transient list error until the restart goes through", func() {
+		ctx := context.Background()
+		obj := newTestAutoRestartPod("flaky", "*/5 * * * *")
+		pod := newTestPod("nginx-0")
+		failures := 2
+		c := newFakeClientBuilder(obj, pod).
+			WithInterceptorFuncs(interceptor.Funcs{
+				List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+					if _, ok := list.(*corev1.PodList); ok && failures > 0 {
+						failures--
+						return apierrors.NewTimeoutError("the list timed out", 1)
+					}
+					return c.List(ctx, list, opts...)
+				},
+			}).Build()
+		r := newTestReconciler(c, time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+
+		for _, wait := range []time.Duration{time.Second, 2 * time.Second} {
+			result, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(wait))
+			Expect(podExists(c, pod)).To(BeTrue())
+		}
+
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, pod)).To(BeFalse())
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		// Transient errors do not degrade the object
+		Expect(getCondition(updated, stablev1.ConditionDegraded).Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("should report a permanent error in the Degraded condition", func() {
+		ctx := context.Background()
+		obj := newTestAutoRestartPod("broken", "not a schedule")
+		c := newFakeClient(obj)
+		r := newTestReconciler(c, time.Date(2025, 5, 26, 10, 1, 0, 0, time.UTC))
+
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).To(HaveOccurred())
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		degraded := getCondition(updated, stablev1.ConditionDegraded)
+		Expect(degraded).NotTo(BeNil())
+		Expect(degraded.Status).To(Equal(metav1.ConditionTrue))
+		Expect(degraded.Reason).To(Equal("ReconcileError"))
+
+		updated.Spec.Schedule = "*/5 * * * *"
+		Expect(c.Update(ctx, updated)).To(Succeed())
+		_, err = r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		degraded = getCondition(updated, stablev1.ConditionDegraded)
+		Expect(degraded.Status).To(Equal(metav1.ConditionFalse))
+		Expect(degraded.Reason).To(Equal("Reconciled"))
+	})
+
+	It("should retry terminal errors after a longer backoff", func() {
+		limiter := terminalRateLimiter()
+		req := requestFor(newTestAutoRestartPod("broken", ""))
+		Expect(limiter.When(req)).To(Equal(terminalBackoff))
+		Expect(limiter.When(req)).To(Equal(2 * terminalBackoff))
+	})
+})
@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitBreaker stops destructive actions across every AutoRestartPod when
+// too many of them fail within a sliding window, e.g. while the API server is
+// degraded. It closes again on its own once the failures age out of the window.
+// It is safe for concurrent use.
+type CircuitBreaker struct {
+	threshold int
+	window    time.Duration
+
+	mu       sync.Mutex
+	failures []time.Time
+}
+
+// NewCircuitBreaker returns a breaker that opens once threshold failures were
+// recorded within window.
+func NewCircuitBreaker(threshold int, window time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{threshold: threshold, window: window}
+}
+
+// RecordFailure registers a failed destructive action at now.
+func (b *CircuitBreaker) RecordFailure(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.prune(now)
+	b.failures = append(b.failures, now)
+}
+
+// Open reports whether destructive actions are currently paused.
+func (b *CircuitBreaker) Open(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.prune(now)
+	return len(b.failures) >= b.threshold
+}
+
+// prune forgets the failures that happened before the window ending at now.
+func (b *CircuitBreaker) prune(now time.Time) {
+	cutoff := now.Add(-b.window)
+	kept := b.failures[:0]
+	for _, t := range b.failures {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	b.failures = kept
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("CircuitBreaker", func() {
+	base := time.Date(2025, 5, 26, 10, 0, 0, 0, time.UTC)
+
+	It("should trip on a burst of failures and reset once they age out", func() {
+		breaker := NewCircuitBreaker(3, time.Minute)
+		breaker.RecordFailure(base)
+		breaker.RecordFailure(base.Add(10 * time.Second))
+		Expect(breaker.Open(base.Add(10 * time.Second))).To(BeFalse())
+
+		breaker.RecordFailure(base.Add(20 * time.Second))
+		Expect(breaker.Open(base.Add(20 * time.Second))).To(BeTrue())
+
+		// The first failure leaves the window and the breaker closes
+		Expect(breaker.Open(base.Add(61 * time.Second))).To(BeFalse())
+	})
+
+	It("should pause restarts of other objects while open", func() {
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		breaker := NewCircuitBreaker(2, time.Minute)
+
+		failing := newTestAutoRestartPod("failing", "*/5 * * * *")
+		healthy := newTestAutoRestartPod("healthy", "*/5 * * * *")
+		healthy.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+		web := newTestPod("web-0")
+		web.Labels = map[string]string{"app": "web"}
+
+		failDeletes := true
+		c := newFakeClientBuilder(failing, healthy, newTestPod("nginx-0"), newTestPod("nginx-1"), web).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(ctx context.Context, cl client.WithWatch, o client.Object, opts ...client.DeleteOption) error {
+					if failDeletes {
+						return errors.New("etcdserver: request timed out")
+					}
+					return cl.Delete(ctx, o, opts...)
+				},
+			}).
+			Build()
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock, Breaker: breaker}
+
+		By("tripping the breaker with two failed deletions")
+		_, err := r.Reconcile(context.Background(), requestFor(failing))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(breaker.Open(fakeClock.Now())).To(BeTrue())
+
+		By("deferring the restart of another object")
+		failDeletes = false
+		result, err := r.Reconcile(context.Background(), requestFor(healthy))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(runRequeueInterval))
+		Expect(podExists(c, web)).To(BeTrue())
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(healthy), updated)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, stablev1.ConditionCircuitOpen)).To(BeTrue())
+		Expect(updated.Status.ActiveRun).NotTo(BeNil())
+
+		By("resuming the deferred restart once the failures subside")
+		fakeClock.SetTime(fakeClock.Now().Add(2 * time.Minute))
+		_, err = r.Reconcile(context.Background(), requestFor(healthy))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, web)).To(BeFalse())
+
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(healthy), updated)).To(Succeed())
+		Expect(meta.IsStatusConditionFalse(updated.Status.Conditions, stablev1.ConditionCircuitOpen)).To(BeTrue())
+		Expect(updated.Status.ActiveRun).To(BeNil())
+	})
+})
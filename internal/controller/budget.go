@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// restartsInWindow returns the restarts recorded in Status.RestartHistory
+// that fall within the trailing Spec.Window at now.
+func restartsInWindow(obj *stablev1.AutoRestartPod, now time.Time) []metav1.Time {
+	if obj.Spec.Window == nil {
+		return nil
+	}
+	cutoff := now.Add(-obj.Spec.Window.Duration)
+	var recent []metav1.Time
+	for _, t := range obj.Status.RestartHistory {
+		if t.Time.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	return recent
+}
+
+// budgetRemaining returns how long until the restart budget of obj allows
+// another restart, or zero when it allows one now or no budget is configured.
+func budgetRemaining(obj *stablev1.AutoRestartPod, now time.Time) time.Duration {
+	limit := obj.Spec.MaxRestartsPerWindow
+	if limit == nil || obj.Spec.Window == nil {
+		return 0
+	}
+	recent := restartsInWindow(obj, now)
+	if len(recent) < int(*limit) {
+		return 0
+	}
+	// The budget frees up when the oldest restart leaves the window
+	oldest := recent[0].Time
+	for _, t := range recent[1:] {
+		if t.Time.Before(oldest) {
+			oldest = t.Time
+		}
+	}
+	return oldest.Add(obj.Spec.Window.Duration).Sub(now)
+}
+
+// recordRestart adds a restart fired at now to Status.RestartHistory and
+// drops the restarts that left the window. The history is cleared when no
+// budget is configured.
+func recordRestart(obj *stablev1.AutoRestartPod, now time.Time) {
+	if obj.Spec.MaxRestartsPerWindow == nil || obj.Spec.Window == nil {
+		obj.Status.RestartHistory = nil
+		return
+	}
+	obj.Status.RestartHistory = append(restartsInWindow(obj, now), metav1.Time{Time: now})
+}
+
+// setRateLimited records in the RateLimited condition whether the last due
+// restart was skipped because the budget was exhausted until wait passes.
+func setRateLimited(obj *stablev1.AutoRestartPod, now time.Time, wait time.Duration) {
+	if wait > 0 {
+		meta.SetStatusCondition(&obj.Status.Conditions, metav1.Condition{
+			Type:   stablev1.ConditionRateLimited,
+			Status: metav1.ConditionTrue,
+			Reason: "BudgetExhausted",
+			Message: fmt.Sprintf("%d restarts were fired within %s, the next one is allowed at %s",
+				*obj.Spec.MaxRestartsPerWindow, obj.Spec.Window.Duration, now.Add(wait).Format(time.RFC3339)),
+		})
+		return
+	}
+	if meta.FindStatusCondition(obj.Status.Conditions, stablev1.ConditionRateLimited) != nil {
+		meta.SetStatusCondition(&obj.Status.Conditions, metav1.Condition{
+			Type:    stablev1.ConditionRateLimited,
+			Status:  metav1.ConditionFalse,
+			Reason:  "WithinBudget",
+			Message: "restarts are within the budget",
+		})
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Restart budget", func() {
+	It("should stop restarting once the budget is used up and resume after the window rolls", func() {
+		ctx := context.Background()
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		obj := newTestAutoRestartPod("budget", "*/5 * * * *")
+		obj.Spec.MaxRestartsPerWindow = ptr.To[int32](2)
+		obj.Spec.Window = &metav1.Duration{Duration: 15 * time.Minute}
+		c := newFakeClient(obj)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		updated := &stablev1.AutoRestartPod{}
+		// Fire times 10:05, 10:10 and 10:20 restart; 10:15 would be the third
+		// restart within 15 minutes
+		for i, restarts := range []bool{true, true, false, true} {
+			pod := newTestPod(fmt.Sprintf("nginx-%d", i))
+			Expect(c.Create(ctx, pod)).To(Succeed())
+
+			_, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, pod)).To(Equal(!restarts), "fire time %d", i)
+
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, stablev1.ConditionRateLimited)).To(Equal(!restarts))
+			if !restarts {
+				Expect(c.Delete(ctx, pod)).To(Succeed())
+			}
+			fakeClock.SetTime(fakeClock.Now().Add(5 * time.Minute))
+		}
+
+		// Restarts that left the window are dropped from the history
+		Expect(updated.Status.RestartHistory).To(HaveLen(2))
+		Expect(updated.Status.RestartHistory[0].Time).To(BeTemporally("==", time.Date(2025, 5, 26, 10, 9, 30, 0, time.UTC)))
+	})
+
+	It("should report when the budget allows the next restart", func() {
+		now := time.Date(2025, 5, 26, 10, 14, 30, 0, time.UTC)
+		obj := newTestAutoRestartPod("budget", "*/5 * * * *")
+		obj.Spec.MaxRestartsPerWindow = ptr.To[int32](2)
+		obj.Spec.Window = &metav1.Duration{Duration: 15 * time.Minute}
+		obj.Status.RestartHistory = []metav1.Time{
+			{Time: now.Add(-5 * time.Minute)},
+			{Time: now.Add(-10 * time.Minute)},
+		}
+		Expect(budgetRemaining(obj, now)).To(Equal(5 * time.Minute))
+
+		obj.Spec.MaxRestartsPerWindow = ptr.To[int32](3)
+		Expect(budgetRemaining(obj, now)).To(BeZero())
+	})
+
+	It("should not keep a history without a budget", func() {
+		obj := newTestAutoRestartPod("unlimited", "*/5 * * * *")
+		obj.Status.RestartHistory = []metav1.Time{{Time: time.Now()}}
+		recordRestart(obj, time.Now())
+		Expect(obj.Status.RestartHistory).To(BeEmpty())
+		Expect(budgetRemaining(obj, time.Now())).To(BeZero())
+	})
+})
@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// maxMissedSchedules bounds how many missed fire times are counted, so that
+// a frequent schedule missed for long does not stall the reconcile.
+const maxMissedSchedules = 1000
+
+// missedSchedules counts the fire times of schedule up to now that passed
+// after the last one obj reacted to, and returns the latest of them. Fire
+// times while obj was suspended or paused are not missed, and nothing is
+// missed before obj reacted to a first fire time.
+func missedSchedules(obj *stablev1.AutoRestartPod, schedule cron.Schedule, now time.Time) (missed int64, latest time.Time) {
+	if obj.Status.LastScheduleTime == nil {
+		return 0, time.Time{}
+	}
+	from := obj.Status.LastScheduleTime.Time
+	if condition := getCondition(obj, stablev1.ConditionSuspended); condition != nil &&
+		condition.Status == metav1.ConditionFalse && condition.LastTransitionTime.After(from) {
+		from = condition.LastTransitionTime.Time
+	}
+	if until := obj.Spec.PauseUntil; until != nil && until.After(from) {
+		from = until.Add(-time.Nanosecond)
+	}
+
+	for fire := schedule.Next(from.In(now.Location())); !fire.IsZero() && !fire.After(now); fire = schedule.Next(fire) {
+		if missed == maxMissedSchedules {
+			return missed, latestFire(schedule, fire, now)
+		}
+		missed++
+		latest = fire
+	}
+	return missed, latest
+}
+
+// latestFire returns the latest fire time of schedule up to now, given the
+// fire time after that is not after now. It looks back from now over
+// doubling spans rather than walking every fire time from after.
+func latestFire(schedule cron.Schedule, after, now time.Time) time.Time {
+	for back := restartWindow; now.Add(-back).After(after); back *= 2 {
+		if fire := schedule.Next(now.Add(-back)); !fire.After(now) {
+			after = fire
+			break
+		}
+	}
+	for fire := schedule.Next(after); !fire.IsZero() && !fire.After(now); fire = schedule.Next(fire) {
+		after = fire
+	}
+	return after
+}
+
+// pastStartingDeadline reports whether the missed fire time fire is too
+// late at now to be caught up under Spec.StartingDeadlineSeconds.
+func pastStartingDeadline(obj *stablev1.AutoRestartPod, fire, now time.Time) bool {
+	deadline := obj.Spec.StartingDeadlineSeconds
+	return deadline != nil && now.Sub(fire) > time.Duration(*deadline)*time.Second
+}
+
+// tickRequeue returns how long after now to look at obj again once the tick
+// at fire passed without a restart: right after its restart window, which
+// starts window before fire, or at the next fire time when fire was a missed
+// one caught up late.
+func tickRequeue(schedule cron.Schedule, fire, now time.Time, window time.Duration) time.Duration {
+	if wait := fire.Sub(now) + window; wait > 0 {
+		return wait
+	}
+	return schedule.Next(now).Sub(now)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Missed schedules", func() {
+	var (
+		ctx context.Context
+		now time.Time
+		obj *stablev1.AutoRestartPod
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		now = time.Date(2025, 5, 26, 10, 30, 0, 0, time.UTC)
+		obj = newTestAutoRestartPod("catch-up", "0 * * * *")
+		// The controller last reacted to 05:00 and was down since
+		obj.Status.LastRestartTime = &metav1.Time{Time: time.Date(2025, 5, 26, 5, 0, 0, 0, time.UTC)}
+		obj.Status.LastScheduleTime = &metav1.Time{Time: time.Date(2025, 5, 26, 5, 0, 0, 0, time.UTC)}
+	})
+
+	It("should count the missed fire times and catch them up with a single restart", func() {
+		c := newFakeClient(obj, newTestPod("nginx-0"), newTestPod("nginx-1"))
+		r := newTestReconciler(c, now)
+
+		result, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(BeEmpty())
+		Expect(result.RequeueAfter).To(Equal(30 * time.Minute))
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.MissedSchedules).To(Equal(int64(5)))
+		Expect(updated.Status.LastScheduleTime.UTC()).To(Equal(time.Date(2025, 5, 26, 10, 0, 0, 0, time.UTC)))
+		Expect(updated.Status.LastRestartTime.UTC()).To(Equal(now))
+
+		// The missed fire times are not caught up again
+		Expect(c.Create(ctx, newTestPod("nginx-2"))).To(Succeed())
+		_, err = r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ConsistOf("nginx-2"))
+	})
+
+	It("should not catch up missed fire times past the starting deadline", func() {
+		obj.Spec.StartingDeadlineSeconds = ptr.To[int64](600)
+		c := newFakeClient(obj, newTestPod("nginx-0"))
+		r := newTestReconciler(c, now)
+
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ConsistOf("nginx-0"))
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.MissedSchedules).To(Equal(int64(5)))
+		Expect(updated.Status.LastScheduleTime.UTC()).To(Equal(time.Date(2025, 5, 26, 10, 0, 0, 0, time.UTC)))
+	})
+
+	It("should catch up a missed fire time within the starting deadline", func() {
+		obj.Spec.StartingDeadlineSeconds = ptr.To[int64](3600)
+		c := newFakeClient(obj, newTestPod("nginx-0"))
+		r := newTestReconciler(c, now)
+
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(BeEmpty())
+	})
+
+	It("should not count fire times while the object was paused", func() {
+		obj.Spec.PauseUntil = &metav1.Time{Time: time.Date(2025, 5, 26, 8, 30, 0, 0, time.UTC)}
+		schedule, err := parseCronSchedule(obj.Spec.Schedule)
+		Expect(err).NotTo(HaveOccurred())
+
+		missed, latest := missedSchedules(obj, schedule, now)
+		Expect(missed).To(Equal(int64(2)))
+		Expect(latest).To(Equal(time.Date(2025, 5, 26, 10, 0, 0, 0, time.UTC)))
+	})
+
+	It("should miss nothing before the first fire time was reacted to", func() {
+		obj.Status.LastScheduleTime = nil
+		schedule, err := parseCronSchedule(obj.Spec.Schedule)
+		Expect(err).NotTo(HaveOccurred())
+
+		missed, _ := missedSchedules(obj, schedule, now)
+		Expect(missed).To(BeZero())
+	})
+
+	It("should find the latest fire time beyond the counted ones", func() {
+		obj.Spec.Schedule = "* * * * * *"
+		schedule, err := parseCronSchedule(obj.Spec.Schedule)
+		Expect(err).NotTo(HaveOccurred())
+
+		missed, latest := missedSchedules(obj, schedule, now)
+		Expect(missed).To(Equal(int64(maxMissedSchedules)))
+		Expect(latest).To(Equal(now))
+	})
+})
@@ -0,0 +1,48 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"time"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// tickRestarts reports whether the tick of the schedule at tick restarts
+// pods under Spec.RestartProbability. The roll is seeded by the object's UID
+// and the tick, so every reconcile of the same tick reaches the same outcome.
+func tickRestarts(obj *stablev1.AutoRestartPod, tick time.Time) (bool, error) {
+	if obj.Spec.RestartProbability == "" {
+		return true, nil
+	}
+	probability, err := strconv.ParseFloat(obj.Spec.RestartProbability, 64)
+	if err != nil || probability < 0 || probability > 1 {
+		return false, fmt.Errorf("invalid restartProbability %q, must be between 0 and 1", obj.Spec.RestartProbability)
+	}
+	return tickRoll(obj, tick) < probability, nil
+}
+
+// tickRoll returns a number in [0, 1) that is fixed for obj and tick.
+func tickRoll(obj *stablev1.AutoRestartPod, tick time.Time) float64 {
+	sum := sha256.Sum256(binary.BigEndian.AppendUint64([]byte(obj.UID), uint64(tick.Unix())))
+	// The top 53 bits fill the mantissa of a float64 evenly
+	return float64(binary.BigEndian.Uint64(sum[:8])>>11) / (1 << 53)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Restart probability", func() {
+	var obj *stablev1.AutoRestartPod
+
+	BeforeEach(func() {
+		obj = newTestAutoRestartPod("chance", "*/5 * * * *")
+		obj.UID = "5c3f9e0a-1d2b-4c6e-8f7a-9b0c1d2e3f40"
+	})
+
+	// restartsPerTick reconciles obj at ten consecutive ticks and reports
+	// whether each of them restarted its pod.
+	restartsPerTick := func() []bool {
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		c := newFakeClient(obj)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		var restarted []bool
+		for i := range 10 {
+			pod := newTestPod(fmt.Sprintf("nginx-%d", i))
+			Expect(c.Create(context.Background(), pod)).To(Succeed())
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			restarted = append(restarted, !podExists(c, pod))
+			fakeClock.SetTime(fakeClock.Now().Add(5 * time.Minute))
+		}
+		return restarted
+	}
+
+	It("should never restart with probability 0", func() {
+		obj.Spec.RestartProbability = "0"
+		Expect(restartsPerTick()).To(HaveEach(BeFalse()))
+	})
+
+	It("should restart at every tick with probability 1", func() {
+		obj.Spec.RestartProbability = "1"
+		Expect(restartsPerTick()).To(HaveEach(BeTrue()))
+	})
+
+	It("should restart at every tick without a probability", func() {
+		Expect(restartsPerTick()).To(HaveEach(BeTrue()))
+	})
+
+	It("should record a skipped tick as handled", func() {
+		obj.Spec.RestartProbability = "0"
+		c := newFakeClient(obj, newTestPod("nginx-0"))
+		r := newTestReconciler(c, time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.LastScheduleTime.Time).To(BeTemporally("==", time.Date(2025, 5, 26, 10, 5, 0, 0, time.UTC)))
+		Expect(updated.Status.LastRestartTime).To(BeNil())
+	})
+
+	It("should reach the same outcome for the same tick", func() {
+		obj.Spec.RestartProbability = "0.5"
+		tick := time.Date(2025, 5, 26, 10, 5, 0, 0, time.UTC)
+		first, err := tickRestarts(obj, tick)
+		Expect(err).NotTo(HaveOccurred())
+		for range 5 {
+			Expect(tickRestarts(obj, tick)).To(Equal(first))
+		}
+		Expect(restartsPerTick()).To(Equal(restartsPerTick()))
+	})
+
+	It("should let some ticks pass at a probability in between", func() {
+		obj.Spec.RestartProbability = "0.5"
+		restarted := 0
+		tick := time.Date(2025, 5, 26, 0, 0, 0, 0, time.UTC)
+		for range 1000 {
+			if ok, _ := tickRestarts(obj, tick); ok {
+				restarted++
+			}
+			tick = tick.Add(5 * time.Minute)
+		}
+		Expect(restarted).To(BeNumerically("~", 500, 100))
+	})
+
+	It("should reject a probability outside of 0 and 1", func() {
+		obj.Spec.RestartProbability = "1.5"
+		_, err := tickRestarts(obj, time.Now())
+		Expect(err).To(MatchError(ContainSubstring("restartProbability")))
+	})
+})
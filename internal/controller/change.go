@@ -0,0 +1,133 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// observeChanges records the state of the objects of Spec.RestartOnChangeOf
+// the first time obj is reconciled, so that the first tick only restarts
+// when they changed since.
+func (r *AutoRestartPodReconciler) observeChanges(ctx context.Context, obj *stablev1.AutoRestartPod) error {
+	if len(obj.Spec.RestartOnChangeOf) == 0 || obj.Status.ObservedChangeHash != "" {
+		return nil
+	}
+	log := logf.FromContext(ctx)
+
+	hash, err := r.changeHash(ctx, obj)
+	if err != nil {
+		log.Error(err, "Failed to hash the objects restarted on change")
+		return err
+	}
+	obj.Status.ObservedChangeHash = hash
+	if err := r.Status().Update(ctx, obj); err != nil {
+		log.Error(err, "Failed to update AutoRestartPod status")
+		return err
+	}
+	return nil
+}
+
+// changedSinceRestart reports whether the objects of Spec.RestartOnChangeOf
+// changed since the last restart and records their new state in status.
+// Without such objects every tick counts as a change.
+func (r *AutoRestartPodReconciler) changedSinceRestart(ctx context.Context, obj *stablev1.AutoRestartPod) (bool, error) {
+	if len(obj.Spec.RestartOnChangeOf) == 0 {
+		return true, nil
+	}
+	hash, err := r.changeHash(ctx, obj)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to hash the objects restarted on change")
+		return false, err
+	}
+	if hash == obj.Status.ObservedChangeHash {
+		return false, nil
+	}
+	obj.Status.ObservedChangeHash = hash
+	return true, nil
+}
+
+// changeHash returns a hash of the objects of Spec.RestartOnChangeOf. A
+// missing object hashes differently from every existing one.
+func (r *AutoRestartPodReconciler) changeHash(ctx context.Context, obj *stablev1.AutoRestartPod) (string, error) {
+	h := sha256.New()
+	for _, ref := range obj.Spec.RestartOnChangeOf {
+		content, err := r.changeContent(ctx, obj, ref)
+		if err != nil {
+			return "", err
+		}
+		data, err := json.Marshal(content)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s/%s\n%s\n", ref.Kind, ref.Name, data)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// changeContent returns the part of the object ref whose changes cause a
+// restart, or nil when the object does not exist.
+func (r *AutoRestartPodReconciler) changeContent(ctx context.Context, obj *stablev1.AutoRestartPod,
+	ref stablev1.ObjectReference) (any, error) {
+	var object client.Object
+	var reader client.Reader = r.Client
+	switch ref.Kind {
+	case "ConfigMap":
+		object = &corev1.ConfigMap{}
+	case "Secret":
+		// Only the metadata of Secrets is cached, see SetupWithManager
+		object = &corev1.Secret{}
+		reader = r.apiReader()
+	default:
+		workload, err := newWorkload(ref.Kind)
+		if err != nil {
+			return nil, err
+		}
+		object = workload
+	}
+	if err := reader.Get(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: ref.Name}, object); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	switch o := object.(type) {
+	case *corev1.ConfigMap:
+		return []any{o.Data, o.BinaryData}, nil
+	case *corev1.Secret:
+		return o.Data, nil
+	}
+	// The restarts of the controller itself do not count as changes
+	template := podTemplate(object).DeepCopy()
+	delete(template.Annotations, restartedAtAnnotation)
+	delete(template.Annotations, restartAnnotationKey(obj))
+	delete(template.Annotations, restartCountAnnotation)
+	return template, nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Restart on change", func() {
+	var (
+		ctx       context.Context
+		fakeClock *testingclock.FakePassiveClock
+		obj       *stablev1.AutoRestartPod
+		config    *corev1.ConfigMap
+		c         client.Client
+		r         *AutoRestartPodReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fakeClock = testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 1, 0, 0, time.UTC))
+		obj = newTestAutoRestartPod("on-change", "*/5 * * * *")
+		obj.Spec.RestartOnChangeOf = []stablev1.ObjectReference{{Kind: "ConfigMap", Name: "nginx-config"}}
+		config = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx-config", Namespace: "default"},
+			Data:       map[string]string{"workers": "4"},
+		}
+		c = newFakeClient(obj, config)
+		r = &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		// The first reconcile records the ConfigMap as it is
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	// tick creates a pod and reconciles obj shortly before the fire time
+	// at minute, and reports whether the pod was restarted
+	tick := func(minute int) bool {
+		pod := newTestPod("nginx")
+		Expect(c.Create(ctx, pod)).To(Succeed())
+		fakeClock.SetTime(time.Date(2025, 5, 26, 10, minute-1, 30, 0, time.UTC))
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		restarted := !podExists(c, pod)
+		if !restarted {
+			Expect(c.Delete(ctx, pod)).To(Succeed())
+		}
+		return restarted
+	}
+
+	It("should only restart once the ConfigMap changed", func() {
+		Expect(tick(5)).To(BeFalse(), "unchanged since the first reconcile")
+
+		config.Data["workers"] = "8"
+		Expect(c.Update(ctx, config)).To(Succeed())
+		Expect(tick(10)).To(BeTrue(), "changed")
+		Expect(tick(15)).To(BeFalse(), "unchanged since the restart")
+
+		// A deleted ConfigMap is a change as well
+		Expect(c.Delete(ctx, config)).To(Succeed())
+		Expect(tick(20)).To(BeTrue(), "deleted")
+	})
+
+	It("should skip the tick without consuming it twice", func() {
+		Expect(tick(5)).To(BeFalse())
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.LastScheduleTime.Time).To(BeTemporally("==", time.Date(2025, 5, 26, 10, 5, 0, 0, time.UTC)))
+		Expect(updated.Status.ObservedChangeHash).NotTo(BeEmpty())
+	})
+
+	It("should ignore the rollout restarts of a watched workload", func() {
+		deploy := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "nginx", Image: "nginx:1.27"}},
+				}},
+			},
+		}
+		Expect(c.Create(ctx, deploy)).To(Succeed())
+		obj.Spec.RestartOnChangeOf = []stablev1.ObjectReference{{Kind: "Deployment", Name: "nginx"}}
+		before, err := r.changeHash(ctx, obj)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.rolloutRestart(ctx, obj, deploy, fakeClock.Now())).To(Succeed())
+		Expect(r.changeHash(ctx, obj)).To(Equal(before))
+
+		deploy.Spec.Template.Spec.Containers[0].Image = "nginx:1.28"
+		Expect(c.Update(ctx, deploy)).To(Succeed())
+		Expect(r.changeHash(ctx, obj)).NotTo(Equal(before))
+	})
+})
@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Concurrent reconciles", func() {
+	const objects = 10
+
+	// peakReconciles runs the controller of r over many objects, holding
+	// every reconcile until release is closed, and returns the highest
+	// number of reconciles that ran at once.
+	peakReconciles := func(r *AutoRestartPodReconciler, want int32) int32 {
+		var list []client.Object
+		for i := range objects {
+			list = append(list, newTestAutoRestartPod(fmt.Sprintf("concurrent-%d", i), "0 3 * * *"))
+		}
+		c := newFakeClient(list...)
+		r.Client, r.Scheme = c, c.Scheme()
+
+		var active, peak atomic.Int32
+		release := make(chan struct{})
+		opts := r.controllerOptions()
+		opts.SkipNameValidation = ptr.To(true)
+		opts.Reconciler = reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+			n := active.Add(1)
+			defer active.Add(-1)
+			for {
+				if p := peak.Load(); n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			<-release
+			return r.Reconcile(ctx, req)
+		})
+		ctrl, err := controller.NewUnmanaged("autorestartpod", opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ctrl.Watch(source.Func(func(_ context.Context,
+			q workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+			for _, obj := range list {
+				q.Add(requestFor(obj.(*stablev1.AutoRestartPod)))
+			}
+			return nil
+		}))).To(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			defer GinkgoRecover()
+			Expect(ctrl.Start(ctx)).To(Succeed())
+		}()
+
+		Eventually(active.Load).Should(Equal(want))
+		// No further reconcile starts while the running ones are held
+		Consistently(active.Load, 100*time.Millisecond).Should(Equal(want))
+		close(release)
+		Eventually(active.Load).Should(BeZero())
+		return peak.Load()
+	}
+
+	It("should reconcile up to MaxConcurrentReconciles objects at once", func() {
+		r := &AutoRestartPodReconciler{MaxConcurrentReconciles: 4}
+		Expect(peakReconciles(r, 4)).To(BeEquivalentTo(4))
+	})
+
+	It("should reconcile one object at a time by default", func() {
+		Expect(peakReconciles(&AutoRestartPodReconciler{}, 1)).To(BeEquivalentTo(1))
+	})
+})
@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// degradedThreshold is how many restart passes in a row must fail before an
+// object is reported Degraded.
+const degradedThreshold = 3
+
+// setCondition sets the condition conditionType of obj and reports whether
+// it changed. The transition time only moves when the status does.
+func setCondition(obj *stablev1.AutoRestartPod, conditionType string, status metav1.ConditionStatus,
+	reason, message string) bool {
+	return meta.SetStatusCondition(&obj.Status.Conditions, metav1.Condition{
+		Type:    conditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// getCondition returns the condition conditionType of obj, or nil when it
+// has not been reported.
+func getCondition(obj *stablev1.AutoRestartPod, conditionType string) *metav1.Condition {
+	return meta.FindStatusCondition(obj.Status.Conditions, conditionType)
+}
+
+// setRunConditions reports the active run of obj, if any, in the Progressing
+// and Available conditions after a restart pass.
+func setRunConditions(obj *stablev1.AutoRestartPod) {
+	run := obj.Status.ActiveRun
+	if run == nil {
+		setCondition(obj, stablev1.ConditionProgressing, metav1.ConditionFalse,
+			"RestartFinished", "no restart is in progress")
+		return
+	}
+	setCondition(obj, stablev1.ConditionProgressing, metav1.ConditionTrue, "Restarting",
+		fmt.Sprintf("restarted %d pods for the restart scheduled at %s", run.RestartedPods,
+			run.ScheduledTime.UTC().Format(time.RFC3339)))
+	if run.AwaitingReplacements {
+		setCondition(obj, stablev1.ConditionAvailable, metav1.ConditionFalse,
+			"AwaitingReplacements", fmt.Sprintf("waiting for %d restarted pods to be replaced", run.RestartedPods))
+	}
+}
+
+// setReplacementAvailability reports in the Available condition how many
+// of the restarted pods were replaced, and how many replacements are Ready,
+// once the controller stopped waiting for them.
+func setReplacementAvailability(obj *stablev1.AutoRestartPod, restarted, replaced, ready int32) {
+	switch {
+	case replaced < restarted:
+		setCondition(obj, stablev1.ConditionAvailable, metav1.ConditionFalse, "ReplacementsMissing",
+			fmt.Sprintf("%d of %d restarted pods were replaced within replacementWait", replaced, restarted))
+	case ready < restarted:
+		setCondition(obj, stablev1.ConditionAvailable, metav1.ConditionFalse, "ReplacementsNotReady",
+			fmt.Sprintf("%d of %d replacement pods are Ready", ready, restarted))
+	default:
+		setCondition(obj, stablev1.ConditionAvailable, metav1.ConditionTrue, "ReplacementsReady",
+			fmt.Sprintf("%d replacement pods are Ready", ready))
+	}
+}
+
+// recordPassOutcome counts the restart passes that failed in a row and
+// reports in the Degraded condition whether too many did.
+func recordPassOutcome(obj *stablev1.AutoRestartPod, restarted, failed int) {
+	switch {
+	case failed > 0:
+		obj.Status.ConsecutiveFailures++
+	case restarted > 0:
+		obj.Status.ConsecutiveFailures = 0
+	}
+
+	if obj.Status.ConsecutiveFailures >= degradedThreshold {
+		setCondition(obj, stablev1.ConditionDegraded, metav1.ConditionTrue, "RepeatedFailures",
+			fmt.Sprintf("the last %d restart passes failed, %d pods could not be restarted in the latest",
+				obj.Status.ConsecutiveFailures, failed))
+		return
+	}
+	if getCondition(obj, stablev1.ConditionDegraded) != nil || restarted > 0 {
+		setCondition(obj, stablev1.ConditionDegraded, metav1.ConditionFalse, "RestartsSucceeding",
+			"recent restarts succeeded")
+	}
+}
+
+// isPodReady reports whether the Ready condition of pod is True.
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Restart conditions", func() {
+	var fakeClock *testingclock.FakePassiveClock
+
+	BeforeEach(func() {
+		fakeClock = testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+	})
+
+	// conditionOf returns the status and reason of the condition
+	// conditionType of obj as stored by c.
+	conditionOf := func(c client.Client, obj *stablev1.AutoRestartPod, conditionType string) string {
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		condition := getCondition(updated, conditionType)
+		if condition == nil {
+			return ""
+		}
+		return fmt.Sprintf("%s/%s", condition.Status, condition.Reason)
+	}
+
+	It("should only move the transition time when the status changes", func() {
+		obj := newTestAutoRestartPod("conditions", "*/5 * * * *")
+		Expect(getCondition(obj, stablev1.ConditionProgressing)).To(BeNil())
+
+		Expect(setCondition(obj, stablev1.ConditionProgressing, metav1.ConditionTrue, "Restarting", "first")).To(BeTrue())
+		transition := getCondition(obj, stablev1.ConditionProgressing).LastTransitionTime
+
+		Expect(setCondition(obj, stablev1.ConditionProgressing, metav1.ConditionTrue, "Restarting", "first")).To(BeFalse())
+		Expect(setCondition(obj, stablev1.ConditionProgressing, metav1.ConditionTrue, "Restarting", "second")).To(BeTrue())
+		condition := getCondition(obj, stablev1.ConditionProgressing)
+		Expect(condition.Message).To(Equal("second"))
+		Expect(condition.LastTransitionTime).To(Equal(transition))
+	})
+
+	It("should progress through a successful restart until the replacements are Ready", func() {
+		obj := newTestAutoRestartPod("conditions", "*/5 * * * *")
+		obj.Spec.ReplacementWait = &metav1.Duration{Duration: 2 * time.Minute}
+		c := newFakeClient(append(newAgedPods(2, fakeClock.Now()), obj)...)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conditionOf(c, obj, stablev1.ConditionProgressing)).To(Equal("True/Restarting"))
+		Expect(conditionOf(c, obj, stablev1.ConditionAvailable)).To(Equal("False/AwaitingReplacements"))
+		Expect(conditionOf(c, obj, stablev1.ConditionDegraded)).To(Equal("False/RestartsSucceeding"))
+
+		fakeClock.SetTime(fakeClock.Now().Add(30 * time.Second))
+		for _, name := range []string{"nginx-a", "nginx-b"} {
+			replacement := newTestPod(name)
+			replacement.CreationTimestamp = metav1.NewTime(fakeClock.Now())
+			replacement.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+			Expect(c.Create(context.Background(), replacement)).To(Succeed())
+		}
+		_, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conditionOf(c, obj, stablev1.ConditionProgressing)).To(Equal("False/RestartFinished"))
+		Expect(conditionOf(c, obj, stablev1.ConditionAvailable)).To(Equal("True/ReplacementsReady"))
+	})
+
+	It("should report replacements that are not Ready", func() {
+		obj := newTestAutoRestartPod("conditions", "*/5 * * * *")
+		obj.Spec.ReplacementWait = &metav1.Duration{Duration: 2 * time.Minute}
+		c := newFakeClient(append(newAgedPods(1, fakeClock.Now()), obj)...)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+
+		fakeClock.SetTime(fakeClock.Now().Add(30 * time.Second))
+		replacement := newTestPod("nginx-a")
+		replacement.CreationTimestamp = metav1.NewTime(fakeClock.Now())
+		Expect(c.Create(context.Background(), replacement)).To(Succeed())
+		_, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conditionOf(c, obj, stablev1.ConditionAvailable)).To(Equal("False/ReplacementsNotReady"))
+	})
+
+	It("should leave availability unknown without a replacement wait", func() {
+		obj := newTestAutoRestartPod("conditions", "*/5 * * * *")
+		c := newFakeClient(obj, newTestPod("nginx-0"))
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conditionOf(c, obj, stablev1.ConditionProgressing)).To(Equal("False/RestartFinished"))
+		Expect(conditionOf(c, obj, stablev1.ConditionAvailable)).To(Equal("Unknown/NotAwaited"))
+	})
+
+	It("should become Degraded after repeated failures and recover after a success", func() {
+		obj := newTestAutoRestartPod("conditions", "*/5 * * * *")
+		failing := true
+		c := newFakeClientBuilder(obj).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(ctx context.Context, c client.WithWatch, o client.Object, opts ...client.DeleteOption) error {
+					if failing {
+						return errors.New("delete refused")
+					}
+					return c.Delete(ctx, o, opts...)
+				},
+			}).Build()
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		// One fire time every five minutes, each failing to delete the pod
+		Expect(c.Create(context.Background(), newTestPod("nginx-0"))).To(Succeed())
+		for i := range degradedThreshold {
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			if i < degradedThreshold-1 {
+				Expect(conditionOf(c, obj, stablev1.ConditionDegraded)).To(BeEmpty())
+			}
+			fakeClock.SetTime(fakeClock.Now().Add(5 * time.Minute))
+		}
+		Expect(conditionOf(c, obj, stablev1.ConditionDegraded)).To(Equal("True/RepeatedFailures"))
+
+		failing = false
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conditionOf(c, obj, stablev1.ConditionDegraded)).To(Equal("False/RestartsSucceeding"))
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.ConsecutiveFailures).To(BeZero())
+	})
+})
@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// daemonSetBatch narrows the pods of each DaemonSet down to those on the
+// first nodes allowed by daemonSetNodesPerPass, in the order of pods. Pods
+// of other owners are all kept. More is reported when pods on other nodes
+// are left for later passes.
+func (r *AutoRestartPodReconciler) daemonSetBatch(ctx context.Context, obj *stablev1.AutoRestartPod,
+	pods []corev1.Pod) (batch []corev1.Pod, more bool, err error) {
+	// The nodes of each DaemonSet in the order their first pod comes up
+	nodes := map[string][]string{}
+	for _, pod := range pods {
+		owner := metav1.GetControllerOf(&pod)
+		if owner == nil || owner.Kind != "DaemonSet" {
+			continue
+		}
+		known := nodes[owner.Name]
+		if !slices.Contains(known, pod.Spec.NodeName) {
+			nodes[owner.Name] = append(known, pod.Spec.NodeName)
+		}
+	}
+	if len(nodes) == 0 {
+		return pods, false, nil
+	}
+
+	selected := map[string]map[string]bool{}
+	for name, dsNodes := range nodes {
+		limit, err := r.daemonSetNodesPerPass(ctx, obj, name)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(dsNodes) > limit {
+			dsNodes = dsNodes[:limit]
+			more = true
+		}
+		selected[name] = map[string]bool{}
+		for _, node := range dsNodes {
+			selected[name][node] = true
+		}
+		logf.FromContext(ctx).Info("Restarting DaemonSet pods node by node", "daemonSet", name,
+			"nodes", dsNodes, "nodesLeft", len(nodes[name])-len(dsNodes))
+	}
+
+	for _, pod := range pods {
+		owner := metav1.GetControllerOf(&pod)
+		if owner == nil || owner.Kind != "DaemonSet" || selected[owner.Name][pod.Spec.NodeName] {
+			batch = append(batch, pod)
+		}
+	}
+	return batch, more, nil
+}
+
+// daemonSetNodesPerPass returns on how many nodes the pods of the named
+// DaemonSet are restarted in one pass.
+func (r *AutoRestartPodReconciler) daemonSetNodesPerPass(ctx context.Context, obj *stablev1.AutoRestartPod,
+	name string) (int, error) {
+	limit := 1
+	if perPass := obj.Spec.DaemonSetNodesPerPass; perPass != nil {
+		limit = int(*perPass)
+	}
+	if !obj.Spec.HonorDaemonSetUpdateStrategy {
+		return limit, nil
+	}
+
+	ds := &appsv1.DaemonSet{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: name}, ds); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to get DaemonSet", "daemonSet", name)
+		return 0, err
+	}
+	update := ds.Spec.UpdateStrategy
+	if update.Type != appsv1.RollingUpdateDaemonSetStrategyType || update.RollingUpdate == nil ||
+		update.RollingUpdate.MaxUnavailable == nil {
+		return limit, nil
+	}
+	// Like the DaemonSet controller, percentages of the nodes round up
+	maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(update.RollingUpdate.MaxUnavailable,
+		int(ds.Status.DesiredNumberScheduled), true)
+	if err != nil {
+		return 0, err
+	}
+	// A DaemonSet surging instead allows no unavailable pods
+	return max(maxUnavailable, 1), nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("DaemonSet pods", func() {
+	var (
+		fakeClock *testingclock.FakePassiveClock
+		obj       *stablev1.AutoRestartPod
+		ds        *appsv1.DaemonSet
+		objs      []client.Object
+	)
+
+	BeforeEach(func() {
+		fakeClock = testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		obj = newTestAutoRestartPod("agents", "*/5 * * * *")
+		ds = &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default", UID: "ds-agent"},
+			Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 3},
+		}
+		rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "nginx-abc", Namespace: "default", UID: "rs-nginx"}}
+		objs = []client.Object{obj, ds, rs, newOwnedPod("nginx-abc-1", "nginx", rs, "ReplicaSet")}
+		for _, node := range []string{"node-1", "node-2", "node-3"} {
+			pod := newOwnedPod("agent-"+node, "nginx", ds, "DaemonSet")
+			pod.Spec.NodeName = node
+			objs = append(objs, pod)
+		}
+	})
+
+	// passes reconciles obj until its run is finished and returns the pods
+	// deleted by every pass
+	passes := func() [][]string {
+		c := newFakeClient(objs...)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		var deleted [][]string
+		remaining := remainingPods(c)
+		for range 5 {
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			var pass []string
+			left := remainingPods(c)
+			for _, name := range remaining {
+				if !slices.Contains(left, name) {
+					pass = append(pass, name)
+				}
+			}
+			deleted = append(deleted, pass)
+			remaining = left
+
+			updated := &stablev1.AutoRestartPod{}
+			Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+			if updated.Status.ActiveRun == nil {
+				break
+			}
+			fakeClock.SetTime(fakeClock.Now().Add(runRequeueInterval))
+		}
+		return deleted
+	}
+
+	It("should restart one node at a time", func() {
+		Expect(passes()).To(Equal([][]string{
+			{"agent-node-1", "nginx-abc-1"},
+			{"agent-node-2"},
+			{"agent-node-3"},
+		}))
+	})
+
+	It("should restart the configured number of nodes per pass", func() {
+		obj.Spec.DaemonSetNodesPerPass = ptr.To[int32](2)
+		Expect(passes()).To(Equal([][]string{
+			{"agent-node-1", "agent-node-2", "nginx-abc-1"},
+			{"agent-node-3"},
+		}))
+	})
+
+	It("should honor the maxUnavailable of the DaemonSet", func() {
+		obj.Spec.HonorDaemonSetUpdateStrategy = true
+		ds.Spec.UpdateStrategy = appsv1.DaemonSetUpdateStrategy{
+			Type: appsv1.RollingUpdateDaemonSetStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateDaemonSet{
+				MaxUnavailable: ptr.To(intstr.FromString("50%")),
+			},
+		}
+		// 50% of 3 nodes rounds up to 2
+		Expect(passes()).To(Equal([][]string{
+			{"agent-node-1", "agent-node-2", "nginx-abc-1"},
+			{"agent-node-3"},
+		}))
+	})
+
+	It("should fall back to the configured nodes for an OnDelete DaemonSet", func() {
+		obj.Spec.HonorDaemonSetUpdateStrategy = true
+		ds.Spec.UpdateStrategy = appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType}
+		Expect(passes()).To(HaveLen(3))
+	})
+})
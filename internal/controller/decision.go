@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// debugAnnotation set to "true" on an AutoRestartPod logs what each
+// reconcile of its top-level schedule decided as a single record.
+const debugAnnotation = "autorestart.crazyfrank.com/debug"
+
+// scheduleDecision is what a reconcile of the top-level schedule decided,
+// gathered from the steps of reconcileDefaultSchedule. Its methods are no-ops
+// on a nil decision, which is what objects without debugAnnotation get.
+type scheduleDecision struct {
+	Format       stablev1.ScheduleFormat `json:"format"`
+	Now          string                  `json:"now"`
+	NextRun      string                  `json:"nextRun"`
+	NeedsRestart bool                    `json:"needsRestart"`
+	Fired        bool                    `json:"fired"`
+	MatchedPods  int                     `json:"matchedPods"`
+	Missed       int64                   `json:"missed,omitempty"`
+	SkipReasons  []string                `json:"skipReasons,omitempty"`
+}
+
+// newScheduleDecision returns the decision record of obj, or nil when obj is
+// not annotated with debugAnnotation.
+func newScheduleDecision(obj *stablev1.AutoRestartPod) *scheduleDecision {
+	if obj.Annotations[debugAnnotation] != "true" {
+		return nil
+	}
+	return &scheduleDecision{}
+}
+
+// observe records the fire time nextRun computed at now and the pods of obj
+// matched at now.
+func (d *scheduleDecision) observe(ctx context.Context, r *AutoRestartPodReconciler, obj *stablev1.AutoRestartPod,
+	now, nextRun time.Time, needsRestart bool) {
+	if d == nil {
+		return
+	}
+	d.Format = scheduleFormat(obj)
+	d.Now = now.Format(time.RFC3339)
+	d.NextRun = nextRun.Format(time.RFC3339)
+	d.NeedsRestart = needsRestart
+	d.Missed = obj.Status.MissedSchedules
+
+	// Counting the pods is not worth failing the reconcile over
+	_, selector, err := r.podSelector(ctx, obj)
+	if err == nil {
+		var pods []corev1.Pod
+		pods, _, err = r.candidatePods(ctx, obj, selector, now)
+		d.MatchedPods = len(pods)
+	}
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to count the matched pods of the schedule decision")
+	}
+}
+
+// skip records why the reconcile did not restart.
+func (d *scheduleDecision) skip(reason string) {
+	if d != nil {
+		d.SkipReasons = append(d.SkipReasons, reason)
+	}
+}
+
+// fire records that the reconcile restarted for its fire time.
+func (d *scheduleDecision) fire() {
+	if d != nil {
+		d.Fired = true
+	}
+}
+
+// log logs the decision as a single record.
+func (d *scheduleDecision) log(ctx context.Context) {
+	if d != nil {
+		logf.FromContext(ctx).Info("Schedule decision", "decision", d)
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Schedule decision", func() {
+	// reconcileDecisions reconciles obj with two matching pods at now and
+	// returns the schedule decisions it logged.
+	reconcileDecisions := func(obj *stablev1.AutoRestartPod, now time.Time) []scheduleDecision {
+		var decisions []scheduleDecision
+		logger := funcr.NewJSON(func(line string) {
+			var record struct {
+				Msg      string            `json:"msg"`
+				Decision *scheduleDecision `json:"decision"`
+			}
+			Expect(json.Unmarshal([]byte(line), &record)).To(Succeed())
+			if record.Msg == "Schedule decision" {
+				decisions = append(decisions, *record.Decision)
+			}
+		}, funcr.Options{})
+		ctx := logf.IntoContext(context.Background(), logger)
+
+		c := newFakeClient(obj, newTestPod("nginx-0"), newTestPod("nginx-1"))
+		r := newTestReconciler(c, now)
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		return decisions
+	}
+
+	It("should log a fired restart as one record", func() {
+		obj := newTestAutoRestartPod("debug", "*/5 * * * *")
+		obj.Annotations = map[string]string{debugAnnotation: "true"}
+
+		Expect(reconcileDecisions(obj, time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))).To(Equal([]scheduleDecision{{
+			Format:       stablev1.StandardScheduleFormat,
+			Now:          "2025-05-26T10:04:30Z",
+			NextRun:      "2025-05-26T10:05:00Z",
+			NeedsRestart: true,
+			Fired:        true,
+			MatchedPods:  2,
+		}}))
+	})
+
+	It("should log why a tick was skipped", func() {
+		obj := newTestAutoRestartPod("debug", "*/5 * * * *")
+		obj.Annotations = map[string]string{debugAnnotation: "true"}
+		obj.Spec.SkipDates = []string{"2025-05-26"}
+
+		Expect(reconcileDecisions(obj, time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))).To(Equal([]scheduleDecision{{
+			Format:       stablev1.StandardScheduleFormat,
+			Now:          "2025-05-26T10:04:30Z",
+			NextRun:      "2025-05-26T10:05:00Z",
+			NeedsRestart: true,
+			MatchedPods:  2,
+			SkipReasons:  []string{"SkipDate"},
+		}}))
+	})
+
+	It("should log a fire time that is not due yet", func() {
+		obj := newTestAutoRestartPod("debug", "30 */5 * * * *")
+		obj.Annotations = map[string]string{debugAnnotation: "true"}
+
+		Expect(reconcileDecisions(obj, time.Date(2025, 5, 26, 10, 2, 0, 0, time.UTC))).To(Equal([]scheduleDecision{{
+			Format:      stablev1.SecondsScheduleFormat,
+			Now:         "2025-05-26T10:02:00Z",
+			NextRun:     "2025-05-26T10:05:30Z",
+			MatchedPods: 2,
+			SkipReasons: []string{"NotDue"},
+		}}))
+	})
+
+	It("should not log a decision without the annotation", func() {
+		obj := newTestAutoRestartPod("quiet", "*/5 * * * *")
+		Expect(reconcileDecisions(obj, time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))).To(BeEmpty())
+	})
+})
@@ -0,0 +1,144 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// EndpointDrainer removes a pod's endpoint from an external load balancer or
+// service mesh so that it stops receiving new connections before deletion.
+type EndpointDrainer interface {
+	Deregister(ctx context.Context, url string, pod *corev1.Pod) error
+}
+
+// DeregisterRequest is the JSON body POSTed by the HTTP drainer.
+type DeregisterRequest struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	PodIP     string `json:"podIP,omitempty"`
+}
+
+// HTTPEndpointDrainer deregisters pods by POSTing a DeregisterRequest to the
+// configured URL.
+type HTTPEndpointDrainer struct {
+	Client *http.Client
+}
+
+// defaultDeregisterTimeout bounds a deregistration call.
+const defaultDeregisterTimeout = 10 * time.Second
+
+// Deregister implements EndpointDrainer.
+func (d *HTTPEndpointDrainer) Deregister(ctx context.Context, url string, pod *corev1.Pod) error {
+	body, err := json.Marshal(DeregisterRequest{
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		PodIP:     pod.Status.PodIP,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClientOrDefault(d.Client, defaultDeregisterTimeout).Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if !successful(resp) {
+		return fmt.Errorf("deregistering pod %s/%s: unexpected status %s", pod.Namespace, pod.Name, resp.Status)
+	}
+	return nil
+}
+
+// podDrainedAtAnnotation records on a pod when it was deregistered, so that
+// a later pass deletes it once Spec.PreDrain.DrainPeriod has passed.
+const podDrainedAtAnnotation = "autorestart.crazyfrank.com/drained-at"
+
+// drainPod deregisters pod from the endpoint configured in spec.
+func (r *AutoRestartPodReconciler) drainPod(ctx context.Context, spec *stablev1.PreDrainSpec, pod *corev1.Pod) error {
+	drainer := r.Drainer
+	if drainer == nil {
+		drainer = &HTTPEndpointDrainer{}
+	}
+	return drainer.Deregister(ctx, spec.URL, pod)
+}
+
+// drainPeriod returns Spec.PreDrain.DrainPeriod, zero when pods are deleted
+// right after their deregistration.
+func drainPeriod(obj *stablev1.AutoRestartPod) time.Duration {
+	if obj.Spec.PreDrain == nil || obj.Spec.PreDrain.DrainPeriod == nil {
+		return 0
+	}
+	return max(obj.Spec.PreDrain.DrainPeriod.Duration, 0)
+}
+
+// drainPods deregisters the pods not drained yet and marks them with
+// podDrainedAtAnnotation instead of waiting for the drain period. It returns
+// the pods drained for at least period, which may be deleted now, and the
+// pods whose deregistration failed, which are kept. draining reports pods
+// left to drain, for a following pass to delete.
+func (r *AutoRestartPodReconciler) drainPods(ctx context.Context, obj *stablev1.AutoRestartPod, pods []corev1.Pod,
+	now time.Time, period time.Duration) (ready []corev1.Pod, failed podResults, draining bool) {
+	log := logf.FromContext(ctx)
+
+	for i := range pods {
+		pod := &pods[i]
+		if drainedAt, err := time.Parse(time.RFC3339, pod.Annotations[podDrainedAtAnnotation]); err == nil {
+			if now.Sub(drainedAt) >= period {
+				ready = append(ready, *pod)
+			} else {
+				draining = true
+			}
+			continue
+		}
+
+		if err := r.drainPod(ctx, obj.Spec.PreDrain, pod); err != nil {
+			log.Error(err, "Failed to drain pod, skipping deletion", "pod", pod.Name)
+			failed.add(pod.Name, err)
+			continue
+		}
+		patch := client.MergeFrom(pod.DeepCopy())
+		metav1.SetMetaDataAnnotation(&pod.ObjectMeta, podDrainedAtAnnotation, now.UTC().Format(time.RFC3339))
+		if err := r.Patch(ctx, pod, patch); err != nil {
+			log.Error(err, "Failed to mark pod as draining", "pod", pod.Name)
+			failed.add(pod.Name, err)
+			continue
+		}
+		log.Info("Draining pod", "pod", pod.Name, "drainPeriod", period.String())
+		draining = true
+	}
+	return ready, failed, draining
+}
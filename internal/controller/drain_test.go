@@ -0,0 +1,179 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// recordingDrainer appends every deregistration to a shared event log.
+type recordingDrainer struct {
+	events *[]string
+	err    error
+}
+
+func (d *recordingDrainer) Deregister(_ context.Context, _ string, pod *corev1.Pod) error {
+	*d.events = append(*d.events, "deregister "+pod.Name)
+	return d.err
+}
+
+var _ = Describe("Pre-drain notification", func() {
+	var (
+		events []string
+		obj    *stablev1.AutoRestartPod
+		c      client.Client
+		r      *AutoRestartPodReconciler
+	)
+
+	BeforeEach(func() {
+		events = nil
+		obj = newTestAutoRestartPod("predrain", "*/5 * * * *")
+		obj.Spec.PreDrain = &stablev1.PreDrainSpec{URL: "http://lb.example/deregister"}
+		c = newFakeClientBuilder(obj, newTestPod("nginx-0"), newTestPod("nginx-1")).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(ctx context.Context, cl client.WithWatch, o client.Object, opts ...client.DeleteOption) error {
+					events = append(events, "delete "+o.GetName())
+					return cl.Delete(ctx, o, opts...)
+				},
+			}).
+			Build()
+		r = newTestReconciler(c, time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+	})
+
+	It("should deregister each pod before deleting it", func() {
+		r.Drainer = &recordingDrainer{events: &events}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(Equal([]string{
+			"deregister nginx-0", "delete nginx-0",
+			"deregister nginx-1", "delete nginx-1",
+		}))
+	})
+
+	It("should keep pods whose deregistration fails", func() {
+		r.Drainer = &recordingDrainer{events: &events, err: errors.New("lb unavailable")}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(Equal([]string{"deregister nginx-0", "deregister nginx-1"}))
+		Expect(podExists(c, newTestPod("nginx-0"))).To(BeTrue())
+	})
+
+	It("should delete drained pods on a later pass instead of waiting for them", func() {
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		r.Clock = fakeClock
+		r.Drainer = &recordingDrainer{events: &events}
+		obj.Spec.PreDrain.DrainPeriod = &metav1.Duration{Duration: time.Minute}
+		Expect(c.Update(context.Background(), obj)).To(Succeed())
+
+		started := time.Now()
+		result, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(time.Since(started)).To(BeNumerically("<", 10*time.Second))
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(events).To(Equal([]string{"deregister nginx-0", "deregister nginx-1"}))
+		pod := &corev1.Pod{}
+		Expect(c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "nginx-0"}, pod)).To(Succeed())
+		Expect(pod.Annotations).To(HaveKeyWithValue(podDrainedAtAnnotation, "2025-05-26T10:04:30Z"))
+
+		// Still draining half way through the period
+		fakeClock.SetTime(fakeClock.Now().Add(30 * time.Second))
+		_, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(HaveLen(2))
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.ActiveRun).NotTo(BeNil())
+
+		fakeClock.SetTime(fakeClock.Now().Add(30 * time.Second))
+		_, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(Equal([]string{
+			"deregister nginx-0", "deregister nginx-1", "delete nginx-0", "delete nginx-1",
+		}))
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.ActiveRun).To(BeNil())
+	})
+
+	It("should drain the pods of a rule before deleting them", func() {
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		r.Clock = fakeClock
+		r.Drainer = &recordingDrainer{events: &events}
+		obj.Spec.Selector = nil
+		obj.Spec.Schedule = ""
+		obj.Spec.Restarts = []stablev1.RestartRule{{
+			Name:     "nginx",
+			Schedule: "5 * * * *",
+			Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}},
+		}}
+		obj.Spec.PreDrain.DrainPeriod = &metav1.Duration{Duration: time.Minute}
+		Expect(c.Update(context.Background(), obj)).To(Succeed())
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(Equal([]string{"deregister nginx-0", "deregister nginx-1"}))
+
+		fakeClock.SetTime(fakeClock.Now().Add(time.Minute))
+		_, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(Equal([]string{
+			"deregister nginx-0", "deregister nginx-1", "delete nginx-0", "delete nginx-1",
+		}))
+	})
+
+	It("should POST the pod identity with the HTTP drainer", func() {
+		var received DeregisterRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			Expect(req.Method).To(Equal(http.MethodPost))
+			Expect(json.NewDecoder(req.Body).Decode(&received)).To(Succeed())
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		pod := newTestPod("nginx-0")
+		pod.Status.PodIP = "10.0.0.7"
+		drainer := &HTTPEndpointDrainer{Client: server.Client()}
+		Expect(drainer.Deregister(context.Background(), server.URL, pod)).To(Succeed())
+		Expect(received).To(Equal(DeregisterRequest{Namespace: "default", Name: "nginx-0", PodIP: "10.0.0.7"}))
+	})
+
+	It("should report a non-2xx response as an error", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		drainer := &HTTPEndpointDrainer{Client: server.Client()}
+		Expect(drainer.Deregister(context.Background(), server.URL, newTestPod("nginx-0"))).NotTo(Succeed())
+	})
+})
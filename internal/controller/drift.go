@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// driftTolerance is how far actual restarts may be off the schedule before
+// they count as drift. It leaves room for slow reconciles and for the
+// replacements of restarted pods to be created.
+const driftTolerance = 5 * time.Minute
+
+// updateDrift compares the last restart with what the schedule expected and
+// records the result in the ScheduleDrift condition.
+func (r *AutoRestartPodReconciler) updateDrift(ctx context.Context, obj *stablev1.AutoRestartPod,
+	schedule cron.Schedule, now time.Time) error {
+	log := logf.FromContext(ctx)
+
+	condition := metav1.Condition{
+		Type:    stablev1.ConditionScheduleDrift,
+		Status:  metav1.ConditionFalse,
+		Reason:  "OnSchedule",
+		Message: "restarts follow the schedule",
+	}
+	reason, message, err := r.detectDrift(ctx, obj, schedule, now)
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		log.Info("Restarts drifted from the schedule", "reason", reason, "details", message)
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = reason
+		condition.Message = message
+	}
+
+	if !meta.SetStatusCondition(&obj.Status.Conditions, condition) {
+		return nil
+	}
+	if err := r.Status().Update(ctx, obj); err != nil {
+		log.Error(err, "Failed to update AutoRestartPod status")
+		return err
+	}
+	return nil
+}
+
+// detectDrift returns why the restarts of obj no longer match the schedule,
+// or an empty reason when they do. A fire time after the last restart that
+// has long passed was missed; a pod created well after the last restart was
+// restarted externally. Before the first restart, the time obj was
+// initialized stands in for the last restart.
+func (r *AutoRestartPodReconciler) detectDrift(ctx context.Context, obj *stablev1.AutoRestartPod,
+	schedule cron.Schedule, now time.Time) (reason, message string, err error) {
+	since, event := obj.Status.LastRestartTime, "the last restart"
+	if since == nil {
+		since, event = obj.Status.Initialized, "the first reconcile"
+	}
+	last := since.Time
+
+	// The fire time following the last one reacted to is expected next.
+	// Without one, the last restart ran up to restartWindow ahead of its
+	// fire time.
+	expected := schedule.Next(last.Add(restartWindow))
+	if scheduled := obj.Status.LastScheduleTime; scheduled != nil {
+		expected = schedule.Next(scheduled.Time)
+	}
+	// Fire times during a pause are not missed
+	if until := obj.Spec.PauseUntil; until != nil && expected.Before(until.Time) {
+		expected = schedule.Next(until.Add(-time.Nanosecond))
+	}
+	if expected.Add(driftTolerance).Before(now) {
+		return "MissedRestart", fmt.Sprintf("no restart for the fire time %s, %s was at %s",
+			expected.Format(time.RFC3339), event, last.Format(time.RFC3339)), nil
+	}
+
+	_, selector, err := r.podSelector(ctx, obj)
+	if err != nil {
+		return "", "", err
+	}
+	pods, err := r.listPods(ctx, obj, client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list pods", "selector", selector.String())
+		return "", "", err
+	}
+	running, _ := withoutTerminating(pods)
+	for _, pod := range running {
+		if created := pod.CreationTimestamp.Time; created.After(last.Add(driftTolerance)) {
+			return "ExternalRestart", fmt.Sprintf("pod %s was created at %s, after %s at %s",
+				pod.Name, created.Format(time.RFC3339), event, last.Format(time.RFC3339)), nil
+		}
+	}
+	return "", "", nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Schedule drift", func() {
+	var (
+		now time.Time
+		obj *stablev1.AutoRestartPod
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC)
+		obj = newTestAutoRestartPod("drift", "0 3 * * *")
+	})
+
+	// reconcileDrift reconciles obj with pods created at the given times and
+	// returns its ScheduleDrift condition.
+	reconcileDrift := func(created ...time.Time) *metav1.Condition {
+		objs := []client.Object{obj}
+		for i, t := range created {
+			pod := newTestPod("nginx-" + string(rune('a'+i)))
+			pod.CreationTimestamp = metav1.NewTime(t)
+			objs = append(objs, pod)
+		}
+		c := newFakeClient(objs...)
+		r := newTestReconciler(c, now)
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		return meta.FindStatusCondition(updated.Status.Conditions, stablev1.ConditionScheduleDrift)
+	}
+
+	It("should report no drift when pods were replaced by the last restart", func() {
+		obj.Status.LastRestartTime = &metav1.Time{Time: time.Date(2025, 5, 26, 2, 59, 40, 0, time.UTC)}
+
+		condition := reconcileDrift(time.Date(2025, 5, 26, 3, 0, 10, 0, time.UTC))
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("should flag pods restarted externally", func() {
+		obj.Status.LastRestartTime = &metav1.Time{Time: time.Date(2025, 5, 26, 2, 59, 40, 0, time.UTC)}
+
+		condition := reconcileDrift(
+			time.Date(2025, 5, 26, 3, 0, 10, 0, time.UTC),
+			time.Date(2025, 5, 26, 9, 12, 0, 0, time.UTC),
+		)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal("ExternalRestart"))
+		Expect(condition.Message).To(ContainSubstring("pod nginx-b was created at 2025-05-26T09:12:00Z"))
+	})
+
+	It("should flag a missed fire time", func() {
+		obj.Status.LastRestartTime = &metav1.Time{Time: time.Date(2025, 5, 25, 2, 59, 40, 0, time.UTC)}
+
+		condition := reconcileDrift(time.Date(2025, 5, 25, 3, 0, 10, 0, time.UTC))
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal("MissedRestart"))
+		Expect(condition.Message).To(ContainSubstring("fire time 2025-05-26T03:00:00Z"))
+	})
+
+	It("should track drift from the first reconcile before any restart", func() {
+		obj.Status.Initialized = &metav1.Time{Time: time.Date(2025, 5, 25, 12, 0, 0, 0, time.UTC)}
+
+		condition := reconcileDrift(time.Date(2025, 5, 25, 9, 0, 0, 0, time.UTC))
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal("MissedRestart"))
+		Expect(condition.Message).To(ContainSubstring("the first reconcile was at 2025-05-25T12:00:00Z"))
+	})
+
+	It("should not flag a fire time that was deliberately skipped", func() {
+		// The 03:00 tick of today was skipped, e.g. by the restart probability
+		obj.Status.LastRestartTime = &metav1.Time{Time: time.Date(2025, 5, 25, 2, 59, 40, 0, time.UTC)}
+		obj.Status.LastScheduleTime = &metav1.Time{Time: time.Date(2025, 5, 26, 3, 0, 0, 0, time.UTC)}
+
+		condition := reconcileDrift(time.Date(2025, 5, 25, 3, 0, 10, 0, time.UTC))
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("should not check for drift before the first restart", func() {
+		Expect(reconcileDrift(time.Date(2025, 5, 26, 9, 12, 0, 0, time.UTC))).To(BeNil())
+	})
+})
@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// dryRun reports pods as the pods the restart of pass scheduled for
+// scheduledTime would restart, along with how they differ from the previous
+// dry run, and records the tick as handled without restarting anything.
+func (r *AutoRestartPodReconciler) dryRun(ctx context.Context, obj *stablev1.AutoRestartPod,
+	pass restartPass, pods []corev1.Pod, now, scheduledTime time.Time) error {
+	log := logf.FromContext(ctx)
+
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	slices.Sort(names)
+	var previous []string
+	if last := obj.Status.LastDryRun; last != nil {
+		previous = last.Pods
+	}
+	added, removed := diffNames(previous, names)
+
+	log.Info("Dry run, not restarting pods", "rule", pass.ruleName(), "pods", names, "added", added, "removed", removed)
+	r.event(ctx, obj, corev1.EventTypeNormal, "DryRun",
+		pass.describe(fmt.Sprintf("would restart %d pods, %d added and %d removed since the last dry run",
+			len(names), len(added), len(removed))))
+
+	obj.Status.LastDryRun = &stablev1.DryRunResult{
+		Time:    metav1.Time{Time: now},
+		Pods:    names,
+		Added:   added,
+		Removed: removed,
+	}
+	if pass.rule == nil {
+		obj.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+	}
+	obj.Status.ActiveRun = nil
+	setRunConditions(obj)
+	if err := r.Status().Update(ctx, obj); err != nil {
+		log.Error(err, "Failed to update AutoRestartPod status")
+		return err
+	}
+	return nil
+}
+
+// diffNames returns the names of the sorted current that are not in the
+// sorted previous, and those of previous no longer in current.
+func diffNames(previous, current []string) (added, removed []string) {
+	for _, name := range current {
+		if _, found := slices.BinarySearch(previous, name); !found {
+			added = append(added, name)
+		}
+	}
+	for _, name := range previous {
+		if _, found := slices.BinarySearch(current, name); !found {
+			removed = append(removed, name)
+		}
+	}
+	return added, removed
+}
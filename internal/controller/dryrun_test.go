@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/record"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Dry run", func() {
+	It("should diff the names against the previous set", func() {
+		added, removed := diffNames([]string{"a", "b", "c"}, []string{"b", "c", "d"})
+		Expect(added).To(Equal([]string{"d"}))
+		Expect(removed).To(Equal([]string{"a"}))
+
+		added, removed = diffNames(nil, []string{"a"})
+		Expect(added).To(Equal([]string{"a"}))
+		Expect(removed).To(BeEmpty())
+	})
+
+	It("should report how the pods to restart change between dry runs", func() {
+		ctx := context.Background()
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		obj := newTestAutoRestartPod("dry", "*/5 * * * *")
+		obj.Spec.DryRun = true
+		c := newFakeClient(obj, newTestPod("nginx-a"), newTestPod("nginx-b"))
+		recorder := record.NewFakeRecorder(10)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock, Recorder: recorder}
+
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ConsistOf("nginx-a", "nginx-b"))
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.LastDryRun.Pods).To(Equal([]string{"nginx-a", "nginx-b"}))
+		Expect(updated.Status.LastDryRun.Added).To(Equal([]string{"nginx-a", "nginx-b"}))
+		Expect(updated.Status.LastDryRun.Removed).To(BeEmpty())
+		Expect(updated.Status.LastRestartTime).To(BeNil())
+		Expect(recorder.Events).To(Receive(Equal("Normal DryRun would restart 2 pods, 2 added and 0 removed since the last dry run")))
+
+		// The tick is handled, another reconcile in its window does nothing
+		_, err = r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).NotTo(Receive())
+
+		Expect(c.Delete(ctx, newTestPod("nginx-a"))).To(Succeed())
+		Expect(c.Create(ctx, newTestPod("nginx-c"))).To(Succeed())
+		fakeClock.SetTime(time.Date(2025, 5, 26, 10, 9, 30, 0, time.UTC))
+		_, err = r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.LastDryRun.Pods).To(Equal([]string{"nginx-b", "nginx-c"}))
+		Expect(updated.Status.LastDryRun.Added).To(Equal([]string{"nginx-c"}))
+		Expect(updated.Status.LastDryRun.Removed).To(Equal([]string{"nginx-a"}))
+		Expect(recorder.Events).To(Receive(Equal("Normal DryRun would restart 2 pods, 1 added and 1 removed since the last dry run")))
+		Expect(remainingPods(c)).To(ConsistOf("nginx-b", "nginx-c"))
+	})
+})
@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// wallClockSchedule fires a cron expression once for every matching wall
+// clock time of its location, which cron.SpecSchedule does not do across
+// DST changes: it skips wall clock times that do not exist when clocks
+// spring forward and fires twice at those that repeat when they fall back.
+//
+// A wall clock time skipped by a spring forward fires at the first instant
+// after the gap instead, so a "0 2 * * *" schedule still restarts on that
+// day. A wall clock time that repeats when clocks fall back only fires at
+// its first occurrence.
+type wallClockSchedule struct {
+	// spec is evaluated in UTC against wall clock times, which have no gaps
+	// or repeats
+	spec *cron.SpecSchedule
+	// location is the location of the expression's CRON_TZ prefix, nil to
+	// use the location of the time passed to Next
+	location *time.Location
+}
+
+// newWallClockSchedule returns schedule firing at wall clock times when it
+// is a cron expression; other schedules are returned unchanged.
+func newWallClockSchedule(schedule cron.Schedule) cron.Schedule {
+	spec, ok := schedule.(*cron.SpecSchedule)
+	if !ok {
+		return schedule
+	}
+	s := wallClockSchedule{spec: &cron.SpecSchedule{}}
+	*s.spec = *spec
+	s.spec.Location = time.UTC
+	if spec.Location != time.Local {
+		s.location = spec.Location
+	}
+	return s
+}
+
+// Next implements cron.Schedule.
+func (s wallClockSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	if s.location != nil {
+		loc = s.location
+		t = t.In(loc)
+	}
+	wall := wallClock(t)
+	// Right after clocks fell back, the wall clock times up to the one
+	// shown before falling back have fired already
+	if start, back := fellBack(t); back > 0 {
+		if shown := wallClock(start.Add(-time.Nanosecond)); shown.After(wall) {
+			wall = shown
+		}
+	}
+	wall = s.spec.Next(wall)
+	if wall.IsZero() {
+		return wall
+	}
+	return instantOf(wall, loc)
+}
+
+// wallClock returns the wall clock time of t as a time in UTC.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// fellBack returns when the zone of t started and how far clocks were set
+// back then, zero when they were not.
+func fellBack(t time.Time) (time.Time, time.Duration) {
+	start, _ := t.ZoneBounds()
+	if start.IsZero() {
+		return start, 0
+	}
+	_, offset := t.Zone()
+	_, before := start.Add(-time.Nanosecond).Zone()
+	return start, time.Duration(max(before-offset, 0)) * time.Second
+}
+
+// instantOf returns when the wall clock shows wall in loc: the first instant
+// after the gap when clocks sprang forward over wall, and its first
+// occurrence when clocks fell back over it.
+func instantOf(wall time.Time, loc *time.Location) time.Time {
+	t := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(),
+		wall.Nanosecond(), loc)
+	start, end := t.ZoneBounds()
+	switch shown := wallClock(t); {
+	case shown.Before(wall):
+		// t is right before the gap
+		return end
+	case shown.After(wall):
+		// t is right after the gap
+		return start
+	}
+	if _, back := fellBack(t); back > 0 {
+		if earlier := t.Add(-back); wallClock(earlier).Equal(wall) {
+			return earlier
+		}
+	}
+	return t
+}
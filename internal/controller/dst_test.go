@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("DST changes", func() {
+	var newYork *time.Location
+
+	BeforeEach(func() {
+		var err error
+		newYork, err = time.LoadLocation("America/New_York")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	// fires returns the next n fire times of expression after from in UTC
+	fires := func(expression string, from time.Time, n int) []time.Time {
+		schedule, err := parseCronSchedule(expression)
+		Expect(err).NotTo(HaveOccurred())
+		var fires []time.Time
+		for _, fire := range NextFireTimes(schedule, from, n) {
+			fires = append(fires, fire.UTC())
+		}
+		return fires
+	}
+
+	It("should fire right after the gap when clocks spring forward over the fire time", func() {
+		// Clocks sprang forward from 02:00 to 03:00 on 2025-03-09
+		Expect(fires("0 2 * * *", time.Date(2025, 3, 8, 12, 0, 0, 0, newYork), 3)).To(Equal([]time.Time{
+			time.Date(2025, 3, 9, 7, 0, 0, 0, time.UTC),
+			time.Date(2025, 3, 10, 6, 0, 0, 0, time.UTC),
+			time.Date(2025, 3, 11, 6, 0, 0, 0, time.UTC),
+		}))
+	})
+
+	It("should fire once for several fire times in the gap", func() {
+		Expect(fires("0,30 2 * * *", time.Date(2025, 3, 9, 1, 0, 0, 0, newYork), 2)).To(Equal([]time.Time{
+			time.Date(2025, 3, 9, 7, 0, 0, 0, time.UTC),
+			time.Date(2025, 3, 10, 6, 0, 0, 0, time.UTC),
+		}))
+	})
+
+	It("should fire once at a fire time that repeats when clocks fall back", func() {
+		// Clocks fell back from 02:00 to 01:00 on 2025-11-02
+		Expect(fires("30 1 * * *", time.Date(2025, 11, 1, 12, 0, 0, 0, newYork), 2)).To(Equal([]time.Time{
+			time.Date(2025, 11, 2, 5, 30, 0, 0, time.UTC),
+			time.Date(2025, 11, 3, 6, 30, 0, 0, time.UTC),
+		}))
+		// Looking again during the repeated hour does not fire again
+		Expect(fires("30 1 * * *", time.Date(2025, 11, 2, 6, 10, 0, 0, time.UTC).In(newYork), 1)).To(Equal([]time.Time{
+			time.Date(2025, 11, 3, 6, 30, 0, 0, time.UTC),
+		}))
+	})
+
+	It("should fire once a day at 02:00 when clocks fall back", func() {
+		Expect(fires("0 2 * * *", time.Date(2025, 11, 1, 12, 0, 0, 0, newYork), 2)).To(Equal([]time.Time{
+			time.Date(2025, 11, 2, 7, 0, 0, 0, time.UTC),
+			time.Date(2025, 11, 3, 7, 0, 0, 0, time.UTC),
+		}))
+	})
+
+	It("should use the timezone of a CRON_TZ prefix", func() {
+		Expect(fires("CRON_TZ=America/New_York 0 2 * * *", time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC), 1)).
+			To(Equal([]time.Time{time.Date(2025, 3, 9, 7, 0, 0, 0, time.UTC)}))
+	})
+
+	It("should restart exactly once on the day clocks spring forward", func() {
+		ctx := context.Background()
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 3, 9, 6, 59, 30, 0, time.UTC))
+		obj := newTestAutoRestartPod("dst", "0 2 * * *")
+		obj.Spec.TimeZone = "America/New_York"
+		pod := newTestPod("nginx")
+		c := newFakeClient(obj, pod)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, pod)).To(BeFalse())
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		restarted := updated.Status.LastRestartTime
+
+		// The next fire time is 02:00 on the next day, now in EDT
+		fakeClock.SetTime(time.Date(2025, 3, 9, 7, 0, 30, 0, time.UTC))
+		result, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(22*time.Hour + 59*time.Minute + 30*time.Second))
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.LastRestartTime).To(Equal(restarted))
+	})
+})
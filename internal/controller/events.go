@@ -0,0 +1,180 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// EventSource is the component events about AutoRestartPods are reported by.
+const EventSource = "autorestartpod-controller"
+
+// event publishes an event about obj when a Recorder is configured and
+// mirrors it through the MirrorRecorder when one is set.
+func (r *AutoRestartPodReconciler) event(ctx context.Context, obj *stablev1.AutoRestartPod,
+	eventType, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(obj, eventType, reason, message)
+	}
+	if r.MirrorRecorder != nil {
+		r.MirrorRecorder.Event(obj, eventType, reason, message)
+	}
+}
+
+// warn publishes a warning event about obj.
+func (r *AutoRestartPodReconciler) warn(ctx context.Context, obj *stablev1.AutoRestartPod, reason, message string) {
+	r.event(ctx, obj, corev1.EventTypeWarning, reason, message)
+}
+
+// receipt publishes an event on the workload a restart acted on, so its
+// owners see the restart in `kubectl describe` without knowing about obj.
+func (r *AutoRestartPodReconciler) receipt(workload runtime.Object, obj *stablev1.AutoRestartPod, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(workload, corev1.EventTypeNormal, "RestartedByAutoRestartPod",
+			fmt.Sprintf("%s by AutoRestartPod %s/%s", message, obj.Namespace, obj.Name))
+	}
+}
+
+// podReceipt publishes a receipt for a deleted pod on the object controlling
+// it. Pods of a Deployment get theirs on the Deployment rather than on the
+// intermediate ReplicaSet; pods without a controller get none.
+func (r *AutoRestartPodReconciler) podReceipt(ctx context.Context, obj *stablev1.AutoRestartPod, pod *corev1.Pod) {
+	if r.Recorder == nil {
+		return
+	}
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return
+	}
+	if owner.Kind == "ReplicaSet" {
+		rs := &appsv1.ReplicaSet{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: owner.Name}, rs); err != nil {
+			logf.FromContext(ctx).Error(err, "Failed to resolve pod owner", "pod", pod.Name)
+		} else if rsOwner := metav1.GetControllerOf(rs); rsOwner != nil && rsOwner.Kind == "Deployment" {
+			owner = rsOwner
+		}
+	}
+	ref := &corev1.ObjectReference{
+		APIVersion: owner.APIVersion,
+		Kind:       owner.Kind,
+		Namespace:  pod.Namespace,
+		Name:       owner.Name,
+		UID:        owner.UID,
+	}
+	r.receipt(ref, obj, fmt.Sprintf("pod %s restarted", pod.Name))
+}
+
+// mirrorRecorder publishes the events about objects outside namespace into
+// namespace.
+type mirrorRecorder struct {
+	record.EventRecorder
+	namespace string
+}
+
+// NewMirrorRecorder returns a recorder publishing a copy of the events it is
+// given into namespace, with the aggregation and spam filtering of any other
+// recorder. Events about objects in namespace itself are dropped, they are
+// published there already. The recorder stops when ctx is done.
+func NewMirrorRecorder(ctx context.Context, events typedcorev1.EventsGetter, scheme *runtime.Scheme,
+	namespace string) record.EventRecorder {
+	instance, err := os.Hostname()
+	if err != nil {
+		instance = EventSource
+	}
+	broadcaster := record.NewBroadcaster(record.WithContext(ctx))
+	broadcaster.StartRecordingToSink(&mirrorSink{
+		sink:      &typedcorev1.EventSinkImpl{Interface: events.Events("")},
+		namespace: namespace,
+		instance:  instance,
+	})
+	return &mirrorRecorder{
+		EventRecorder: broadcaster.NewRecorder(scheme, corev1.EventSource{Component: EventSource}),
+		namespace:     namespace,
+	}
+}
+
+// Event publishes an event about object unless it is in the namespace.
+func (m *mirrorRecorder) Event(object runtime.Object, eventType, reason, message string) {
+	if accessor, err := meta.Accessor(object); err == nil && accessor.GetNamespace() == m.namespace {
+		return
+	}
+	m.EventRecorder.Event(object, eventType, reason, message)
+}
+
+// Eventf is Event with a formatted message.
+func (m *mirrorRecorder) Eventf(object runtime.Object, eventType, reason, messageFmt string, args ...any) {
+	m.Event(object, eventType, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf is Eventf with annotations on the event.
+func (m *mirrorRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string,
+	eventType, reason, messageFmt string, args ...any) {
+	if accessor, err := meta.Accessor(object); err == nil && accessor.GetNamespace() == m.namespace {
+		return
+	}
+	m.EventRecorder.AnnotatedEventf(object, annotations, eventType, reason, messageFmt, args...)
+}
+
+// mirrorSink writes events into namespace instead of the namespace of their
+// object. The copies still refer to the object; only events with an event
+// time and reporting controller may refer to an object in another namespace.
+type mirrorSink struct {
+	sink      record.EventSink
+	namespace string
+	instance  string
+}
+
+// mirror returns the copy of event written into the namespace.
+func (s *mirrorSink) mirror(event *corev1.Event) *corev1.Event {
+	event = event.DeepCopy()
+	event.Namespace = s.namespace
+	if event.EventTime.IsZero() {
+		event.EventTime = metav1.NewMicroTime(event.FirstTimestamp.Time)
+	}
+	if event.Action == "" {
+		event.Action = event.Reason
+	}
+	event.ReportingController = EventSource
+	event.ReportingInstance = s.instance
+	return event
+}
+
+func (s *mirrorSink) Create(event *corev1.Event) (*corev1.Event, error) {
+	return s.sink.Create(s.mirror(event))
+}
+
+func (s *mirrorSink) Update(event *corev1.Event) (*corev1.Event, error) {
+	return s.sink.Update(s.mirror(event))
+}
+
+func (s *mirrorSink) Patch(event *corev1.Event, data []byte) (*corev1.Event, error) {
+	return s.sink.Patch(s.mirror(event), data)
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/tools/reference"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// objectRecorder records every event as "Kind/name Reason message", naming
+// the object the event is about.
+type objectRecorder struct {
+	scheme *runtime.Scheme
+	events []string
+}
+
+func (o *objectRecorder) Event(object runtime.Object, _, reason, message string) {
+	ref, err := reference.GetReference(o.scheme, object)
+	Expect(err).NotTo(HaveOccurred())
+	o.events = append(o.events, fmt.Sprintf("%s/%s %s %s", ref.Kind, ref.Name, reason, message))
+}
+
+func (o *objectRecorder) Eventf(object runtime.Object, eventType, reason, messageFmt string, args ...any) {
+	o.Event(object, eventType, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (o *objectRecorder) AnnotatedEventf(object runtime.Object, _ map[string]string,
+	eventType, reason, messageFmt string, args ...any) {
+	o.Eventf(object, eventType, reason, messageFmt, args...)
+}
+
+var _ = Describe("Restart events", func() {
+	var (
+		fakeClock *testingclock.FakePassiveClock
+		recorder  *record.FakeRecorder
+	)
+
+	BeforeEach(func() {
+		fakeClock = testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		recorder = record.NewFakeRecorder(10)
+	})
+
+	It("should publish an event for every restart", func() {
+		obj := newTestAutoRestartPod("events", "*/5 * * * *")
+		c := newFakeClient(obj, newTestPod("nginx-0"), newTestPod("nginx-1"))
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock, Recorder: recorder}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		// The bare pods are not recreated, which is warned about first
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning WontBeRecreated pod nginx-0")))
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning WontBeRecreated pod nginx-1")))
+		Expect(recorder.Events).To(Receive(Equal("Normal Restarted restarted 2 pods")))
+	})
+
+	It("should mirror restart events into the central namespace", func() {
+		obj := newTestAutoRestartPod("events", "*/5 * * * *")
+		sts := newTestStatefulSet("nginx")
+		c := newFakeClient(obj, sts, newOwnedPod("nginx-0", "nginx", sts, "StatefulSet"))
+		mirrored := record.NewFakeRecorder(10)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock,
+			Recorder: recorder, MirrorRecorder: &mirrorRecorder{EventRecorder: mirrored, namespace: "operations"}}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal RestartedByAutoRestartPod")))
+		Expect(recorder.Events).To(Receive(Equal("Normal Restarted restarted 1 pods")))
+		// Receipts are about the workload, only events about obj are mirrored
+		Expect(mirrored.Events).To(Receive(Equal("Normal Restarted restarted 1 pods")))
+		Expect(mirrored.Events).NotTo(Receive())
+	})
+
+	It("should publish mirrored events into the central namespace with aggregation", func() {
+		obj := newTestAutoRestartPod("events", "*/5 * * * *")
+		obj.UID = "events-uid"
+		clientset := kubefake.NewClientset()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		mirror := NewMirrorRecorder(ctx, clientset.CoreV1(), newFakeClient().Scheme(), "operations")
+
+		mirror.Event(obj, corev1.EventTypeNormal, "Restarted", "restarted 1 pods")
+		mirror.Event(obj, corev1.EventTypeNormal, "Restarted", "restarted 1 pods")
+		mirroredEvents := func() ([]corev1.Event, error) {
+			eventList, err := clientset.CoreV1().Events("operations").List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return eventList.Items, nil
+		}
+		Eventually(mirroredEvents).Should(ConsistOf(HaveField("Count", int32(2))))
+
+		events, err := mirroredEvents()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events[0].Type).To(Equal(corev1.EventTypeNormal))
+		Expect(events[0].Reason).To(Equal("Restarted"))
+		Expect(events[0].Message).To(Equal("restarted 1 pods"))
+		Expect(events[0].InvolvedObject.Kind).To(Equal("AutoRestartPod"))
+		Expect(events[0].InvolvedObject.Namespace).To(Equal("default"))
+		Expect(events[0].InvolvedObject.Name).To(Equal("events"))
+		Expect(events[0].ReportingController).To(Equal(EventSource))
+		Expect(events[0].ReportingInstance).NotTo(BeEmpty())
+		Expect(events[0].Action).To(Equal("Restarted"))
+		Expect(events[0].EventTime.IsZero()).To(BeFalse())
+	})
+
+	It("should not mirror events of objects in the central namespace", func() {
+		obj := newTestAutoRestartPod("events", "*/5 * * * *")
+		obj.Namespace = "operations"
+		pod := newTestPod("nginx-0")
+		pod.Namespace = "operations"
+		c := newFakeClient(obj, pod)
+		mirrored := record.NewFakeRecorder(10)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock,
+			Recorder: recorder, MirrorRecorder: &mirrorRecorder{EventRecorder: mirrored, namespace: "operations"}}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning WontBeRecreated")))
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal Restarted")))
+		Expect(mirrored.Events).NotTo(Receive())
+	})
+
+	It("should leave a receipt on the owner of every restarted pod", func() {
+		deploy := newTestDeployment("nginx")
+		rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "nginx-abc", Namespace: "default", UID: "rs-nginx"}}
+		rs.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "apps/v1", Kind: "Deployment", Name: "nginx", UID: deploy.UID, Controller: ptr.To(true),
+		}}
+		sts := newTestStatefulSet("db")
+		obj := newTestAutoRestartPod("receipts", "*/5 * * * *")
+		obj.Spec.Selector.MatchLabels = nil
+		obj.Spec.Selector.MatchExpressions = []metav1.LabelSelectorRequirement{{
+			Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"nginx", "db"},
+		}}
+		c := newFakeClient(obj, deploy, rs, sts,
+			newOwnedPod("nginx-abc-1", "nginx", rs, "ReplicaSet"),
+			newOwnedPod("db-0", "db", sts, "StatefulSet"),
+			newTestPod("nginx-bare"))
+		recorder := &objectRecorder{scheme: c.Scheme()}
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock, Recorder: recorder}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.events).To(ConsistOf(
+			"Deployment/nginx RestartedByAutoRestartPod pod nginx-abc-1 restarted by AutoRestartPod default/receipts",
+			"StatefulSet/db RestartedByAutoRestartPod pod db-0 restarted by AutoRestartPod default/receipts",
+			"AutoRestartPod/receipts WontBeRecreated pod nginx-bare has no ReplicaSet, StatefulSet or DaemonSet owner and will not be recreated",
+			"AutoRestartPod/receipts Restarted restarted 3 pods",
+		))
+	})
+
+	It("should warn before deleting pods no owner will recreate", func() {
+		rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "nginx-abc", Namespace: "default", UID: "rs-nginx"}}
+		sts := newTestStatefulSet("db")
+		ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default", UID: "ds-agent"}}
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "default", UID: "job-migrate"}}
+		jobPod := newOwnedPod("migrate-x", "nginx", job, "Job")
+		jobPod.OwnerReferences[0].APIVersion = "batch/v1"
+		obj := newTestAutoRestartPod("recreated", "*/5 * * * *")
+		obj.Spec.Selector.MatchLabels = nil
+		obj.Spec.Selector.MatchExpressions = []metav1.LabelSelectorRequirement{{
+			Key: "app", Operator: metav1.LabelSelectorOpExists,
+		}}
+		c := newFakeClient(obj, rs, sts,
+			newOwnedPod("nginx-abc-1", "nginx", rs, "ReplicaSet"),
+			newOwnedPod("db-0", "db", sts, "StatefulSet"),
+			newOwnedPod("agent-x", "agent", ds, "DaemonSet"),
+			jobPod,
+			newTestPod("nginx-bare"))
+		recorder := &objectRecorder{scheme: c.Scheme()}
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock, Recorder: recorder}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+
+		var warned []string
+		for _, event := range recorder.events {
+			if strings.HasPrefix(event, "AutoRestartPod/recreated WontBeRecreated") {
+				warned = append(warned, event)
+			}
+		}
+		Expect(warned).To(ConsistOf(
+			HaveSuffix("pod migrate-x has no ReplicaSet, StatefulSet or DaemonSet owner and will not be recreated"),
+			HaveSuffix("pod nginx-bare has no ReplicaSet, StatefulSet or DaemonSet owner and will not be recreated"),
+		))
+		// The warned pods are deleted all the same
+		Expect(podExists(c, jobPod)).To(BeFalse())
+	})
+
+	It("should leave a receipt on rollout restarted workloads", func() {
+		deploy := newTestDeployment("nginx")
+		obj := newTestAutoRestartPod("receipts", "*/5 * * * *")
+		obj.Spec.Selector = nil
+		obj.Spec.TargetRef = &stablev1.TargetReference{Kind: "Deployment", Name: "nginx"}
+		obj.Spec.Strategy = stablev1.RolloutRestartStrategy
+		c := newFakeClient(obj, deploy, newOwnedPod("nginx-0", "nginx", deploy, "Deployment"))
+		recorder := &objectRecorder{scheme: c.Scheme()}
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock, Recorder: recorder}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.events).To(ContainElement(
+			"Deployment/nginx RestartedByAutoRestartPod rollout restarted by AutoRestartPod default/receipts"))
+	})
+})
@@ -0,0 +1,73 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// evictPod evicts pod with the delete options opts, only checking whether
+// the eviction would be allowed when dryRun is set.
+func (r *AutoRestartPodReconciler) evictPod(ctx context.Context, pod *corev1.Pod,
+	opts []client.DeleteOption, dryRun bool) error {
+	deleteOptions := (&client.DeleteOptions{}).ApplyOptions(opts).AsDeleteOptions()
+	var createOpts []client.SubResourceCreateOption
+	if dryRun {
+		deleteOptions.DryRun = []string{metav1.DryRunAll}
+		createOpts = append(createOpts, client.DryRunAll)
+	}
+	eviction := &policyv1.Eviction{
+		ObjectMeta:    metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		DeleteOptions: deleteOptions,
+	}
+	return r.SubResource("eviction").Create(ctx, pod, eviction, createOpts...)
+}
+
+// validateEvictions dry-runs the eviction of every pod and returns the pods
+// that may be evicted. The pods whose eviction would fail, most often
+// because a PodDisruptionBudget blocks it, are returned as failed results.
+func (r *AutoRestartPodReconciler) validateEvictions(ctx context.Context, obj *stablev1.AutoRestartPod,
+	pods []corev1.Pod, opts []client.DeleteOption) (allowed []corev1.Pod, blocked podResults) {
+	log := logf.FromContext(ctx)
+
+	for _, pod := range pods {
+		err := r.evictPod(ctx, &pod, opts, true)
+		if err == nil {
+			allowed = append(allowed, pod)
+			continue
+		}
+		// The API server refuses evictions that would violate a
+		// PodDisruptionBudget with Too Many Requests
+		if apierrors.IsTooManyRequests(err) {
+			err = fmt.Errorf("eviction blocked: %w", err)
+		}
+		log.Info("Skipping pod that cannot be evicted", "pod", pod.Name, "error", err.Error())
+		r.warn(ctx, obj, "EvictionBlocked", fmt.Sprintf("pod %s cannot be evicted: %v", pod.Name, err))
+		blocked.add(pod.Name, err)
+	}
+	return allowed, blocked
+}
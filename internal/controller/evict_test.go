@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Evict strategy", func() {
+	var (
+		obj               *stablev1.AutoRestartPod
+		evicted, dryRuns  []string
+		deleted           []string
+		newEvictingClient func(objs ...client.Object) client.Client
+	)
+
+	BeforeEach(func() {
+		obj = newTestAutoRestartPod("evicting", "*/5 * * * *")
+		obj.Spec.Strategy = stablev1.EvictStrategy
+		evicted, dryRuns, deleted = nil, nil, nil
+
+		// The fake client evicts by deleting and ignores dry runs; a
+		// PodDisruptionBudget protects nginx-protected
+		newEvictingClient = func(objs ...client.Object) client.Client {
+			return newFakeClientBuilder(objs...).WithInterceptorFuncs(interceptor.Funcs{
+				SubResourceCreate: func(ctx context.Context, c client.Client, subResource string, o client.Object,
+					sub client.Object, opts ...client.SubResourceCreateOption) error {
+					options := (&client.SubResourceCreateOptions{}).ApplyOptions(opts)
+					if o.GetName() == "nginx-protected" {
+						return apierrors.NewTooManyRequests(
+							"Cannot evict pod as it would violate the pod's disruption budget.", 0)
+					}
+					if len(options.DryRun) > 0 {
+						dryRuns = append(dryRuns, o.GetName())
+						return nil
+					}
+					evicted = append(evicted, o.GetName())
+					return c.SubResource(subResource).Create(ctx, o, sub, opts...)
+				},
+				Delete: func(ctx context.Context, c client.WithWatch, o client.Object, opts ...client.DeleteOption) error {
+					deleted = append(deleted, o.GetName())
+					return c.Delete(ctx, o, opts...)
+				},
+			}).Build()
+		}
+	})
+
+	reconcile := func(c client.Client) {
+		r := newTestReconciler(c, time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("should evict the pods instead of deleting them", func() {
+		c := newEvictingClient(obj, newTestPod("nginx-0"))
+		reconcile(c)
+		Expect(evicted).To(Equal([]string{"nginx-0"}))
+		Expect(deleted).To(BeEmpty())
+		Expect(podExists(c, newTestPod("nginx-0"))).To(BeFalse())
+	})
+
+	It("should report the pods whose eviction would be blocked without evicting them", func() {
+		obj.Spec.ValidateEvictionFirst = true
+		c := newEvictingClient(obj, newTestPod("nginx-0"), newTestPod("nginx-protected"))
+		reconcile(c)
+
+		Expect(dryRuns).To(Equal([]string{"nginx-0"}))
+		Expect(evicted).To(Equal([]string{"nginx-0"}))
+		Expect(podExists(c, newTestPod("nginx-protected"))).To(BeTrue())
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.LastRestartResults).To(ConsistOf(
+			stablev1.PodRestartResult{Pod: "nginx-protected", Error: "eviction blocked: " +
+				"Cannot evict pod as it would violate the pod's disruption budget."},
+			stablev1.PodRestartResult{Pod: "nginx-0", Success: true},
+		))
+	})
+})
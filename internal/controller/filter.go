@@ -0,0 +1,388 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+	"github.com/crazyfrankie/autorestart-operator/internal/podfields"
+	"github.com/crazyfrankie/autorestart-operator/internal/podmatch"
+)
+
+// podExcludeAnnotation set to "true" keeps a pod out of every restart.
+const podExcludeAnnotation = "autorestart.crazyfrank.com/exclude"
+
+// podMinIntervalAnnotation lets a pod protect itself from restarts until it
+// has been running for at least the given duration (e.g. "2h").
+const podMinIntervalAnnotation = "autorestart.crazyfrank.com/min-interval"
+
+// podNamespaces returns the namespaces obj restarts pods in: Spec.Namespaces,
+// or obj's own namespace when it lists none.
+func podNamespaces(obj *stablev1.AutoRestartPod) []string {
+	if len(obj.Spec.Namespaces) > 0 && obj.Spec.TargetRef == nil {
+		return obj.Spec.Namespaces
+	}
+	return []string{obj.Namespace}
+}
+
+// listPods lists the pods matching opts in every namespace of podNamespaces,
+// see eachNamespace for namespaces that cannot be listed.
+func (r *AutoRestartPodReconciler) listPods(ctx context.Context, obj *stablev1.AutoRestartPod,
+	opts ...client.ListOption) ([]corev1.Pod, error) {
+	var pods []corev1.Pod
+	err := r.eachNamespace(ctx, obj, func(namespace string) error {
+		podList := &corev1.PodList{}
+		if err := r.List(ctx, podList, append(opts, client.InNamespace(namespace))...); err != nil {
+			return err
+		}
+		pods = append(pods, podList.Items...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pods, nil
+}
+
+// eachNamespace calls list for every namespace of podNamespaces. When obj
+// restarts pods in several namespaces, one that fails to be listed, e.g.
+// because the controller lacks RBAC there, does not keep the pods of the
+// others from being restarted: the failures are reported in the
+// NamespacesUnavailable condition and only returned when every namespace
+// failed. Pods are then also only counted in the namespaces listed: the
+// MaxPodsPerRestart overflow check, RestartFraction, RestartSampleSize and
+// MinHealthyFraction see fewer pods than the selector matches, as they
+// would if the namespaces that failed were dropped from Spec.Namespaces.
+func (r *AutoRestartPodReconciler) eachNamespace(ctx context.Context, obj *stablev1.AutoRestartPod,
+	list func(namespace string) error) error {
+	namespaces := podNamespaces(obj)
+	var failures []string
+	var errs []error
+	for _, namespace := range namespaces {
+		if err := list(namespace); err != nil {
+			if len(namespaces) == 1 {
+				return err
+			}
+			logf.FromContext(ctx).Error(err, "Failed to list pods in namespace", "namespace", namespace)
+			failures = append(failures, fmt.Sprintf("namespace %s: %v", namespace, err))
+			errs = append(errs, fmt.Errorf("namespace %s: %w", namespace, err))
+		}
+	}
+
+	if len(failures) == 0 {
+		if getCondition(obj, stablev1.ConditionNamespacesUnavailable) != nil {
+			setCondition(obj, stablev1.ConditionNamespacesUnavailable, metav1.ConditionFalse,
+				"AllListed", "pods were listed in every namespace")
+		}
+		return nil
+	}
+	message := strings.Join(failures, "; ")
+	if setCondition(obj, stablev1.ConditionNamespacesUnavailable, metav1.ConditionTrue, "ListFailed", message) {
+		r.warn(ctx, obj, "NamespaceUnavailable", message)
+	}
+	if len(failures) == len(namespaces) {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// candidatePods lists the pods matching selector in the namespaces of obj and
+// drops the ones that must not be restarted at now: terminating pods, pods
+// outside Spec.PodPhaseFilter, Spec.MinContainerRestartCount or
+// Spec.MatchExpression, and pods in their own cooldown. It also returns how
+// many pods were left alone for terminating.
+func (r *AutoRestartPodReconciler) candidatePods(ctx context.Context, obj *stablev1.AutoRestartPod,
+	selector labels.Selector, now time.Time) (pods []corev1.Pod, terminating int, err error) {
+	ctx, span := r.tracer().Start(ctx, "ListPods")
+	defer func() {
+		span.SetAttributes(podCountKey.Int(len(pods)))
+		endSpan(span, err)
+	}()
+	log := logf.FromContext(ctx)
+
+	opts := []client.ListOption{client.MatchingLabelsSelector{Selector: selector}}
+	if obj.Spec.FieldSelector != "" {
+		fieldSelector, err := podfields.Parse(obj.Spec.FieldSelector)
+		if err != nil {
+			log.Error(err, "Invalid field selector", "fieldSelector", obj.Spec.FieldSelector)
+			r.warn(ctx, obj, "InvalidFieldSelector", err.Error())
+			return nil, 0, fmt.Errorf("invalid field selector: %w", err)
+		}
+		opts = append(opts, client.MatchingFieldsSelector{Selector: fieldSelector})
+	}
+	listed, err := r.listPods(ctx, obj, opts...)
+	if err != nil {
+		log.Error(err, "Failed to list pods", "selector", selector.String(), "fieldSelector", obj.Spec.FieldSelector)
+		return nil, 0, err
+	}
+
+	exclude, err := excludeSelector(obj)
+	if err != nil {
+		log.Error(err, "Invalid exclude selector")
+		r.warn(ctx, obj, "InvalidSelector", err.Error())
+		return nil, 0, err
+	}
+
+	// Pods that are already going away need no restart
+	pods, terminating = withoutTerminating(listed)
+	pods = withoutExcluded(ctx, pods, exclude)
+	if obj.Spec.RequireControllerOwner {
+		pods = controllerOwned(ctx, pods)
+	}
+	pods = inPhases(pods, obj.Spec.PodPhaseFilter)
+	if threshold := obj.Spec.MinContainerRestartCount; threshold != nil {
+		pods = overRestartCount(pods, *threshold)
+	}
+	if expression := obj.Spec.MatchExpression; expression != "" {
+		matcher, err := r.matchers.Get(expression)
+		if err != nil {
+			log.Error(err, "Failed to compile match expression", "matchExpression", expression)
+			return nil, 0, err
+		}
+		pods = matchingPods(ctx, matcher, pods)
+	}
+	if len(obj.Spec.NodeConditions) > 0 || len(obj.Spec.NodeSelector) > 0 {
+		if pods, err = r.onMatchingNodes(ctx, obj, pods); err != nil {
+			return nil, 0, err
+		}
+	}
+	return eligiblePods(ctx, pods, now), terminating, nil
+}
+
+// countCandidatePods counts the pods candidatePods would return for a run
+// started at runStart from their metadata alone, without fetching the full
+// pods. The metadata is listed from the API server, a cached list would
+// start a second informer for every pod in the cluster. The count is only
+// exact when no filter needs more than the metadata, see exactPodCount. It
+// also returns how many pods are terminating.
+func (r *AutoRestartPodReconciler) countCandidatePods(ctx context.Context, obj *stablev1.AutoRestartPod,
+	selector labels.Selector, now, runStart time.Time) (count, terminating int, err error) {
+	var items []metav1.PartialObjectMetadata
+	err = r.eachNamespace(ctx, obj, func(namespace string) error {
+		podList := &metav1.PartialObjectMetadataList{}
+		podList.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("PodList"))
+		if err := r.apiReader().List(ctx, podList, client.InNamespace(namespace),
+			client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return err
+		}
+		items = append(items, podList.Items...)
+		return nil
+	})
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list pod metadata", "selector", selector.String())
+		return 0, 0, err
+	}
+
+	exclude, err := excludeSelector(obj)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for i := range items {
+		pod := &items[i]
+		switch {
+		case pod.DeletionTimestamp != nil:
+			terminating++
+		case excluded(pod, exclude):
+		case obj.Spec.RequireControllerOwner && metav1.GetControllerOfNoCopy(pod) == nil:
+		case pod.CreationTimestamp.After(runStart):
+		case podCooldownRemaining(ctx, pod, now) > 0:
+		default:
+			count++
+		}
+	}
+	return count, terminating, nil
+}
+
+// exactPodCount reports whether countCandidatePods counts exactly the pods a
+// restart pass of obj chooses from. Phases, container restart counts and
+// match expressions need the full pods, as do field selectors since only
+// pods are indexed by their fields, and node conditions, node selectors and
+// topology batching pick from a subset of them.
+func exactPodCount(obj *stablev1.AutoRestartPod) bool {
+	return len(obj.Spec.PodPhaseFilter) == 0 && obj.Spec.MinContainerRestartCount == nil &&
+		obj.Spec.MatchExpression == "" && obj.Spec.FieldSelector == "" &&
+		len(obj.Spec.NodeConditions) == 0 && len(obj.Spec.NodeSelector) == 0 && obj.Spec.TopologyKey == ""
+}
+
+// eligiblePods returns the pods that may be restarted at now, dropping the
+// ones that opted out through annotations.
+func eligiblePods(ctx context.Context, pods []corev1.Pod, now time.Time) []corev1.Pod {
+	log := logf.FromContext(ctx)
+
+	eligible := make([]corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if remaining := podCooldownRemaining(ctx, &pod, now); remaining > 0 {
+			log.Info("Skipping pod during its own cooldown", "pod", pod.Name, "remaining", remaining.String())
+			continue
+		}
+		eligible = append(eligible, pod)
+	}
+	return eligible
+}
+
+// excludeSelector returns the selector of Spec.ExcludeSelector, or nil when
+// no pods are excluded by label.
+func excludeSelector(obj *stablev1.AutoRestartPod) (labels.Selector, error) {
+	if obj.Spec.ExcludeSelector == nil {
+		return nil, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(obj.Spec.ExcludeSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude selector: %w", err)
+	}
+	return selector, nil
+}
+
+// excluded reports whether pod opted out of restarts with its annotation or
+// is matched by exclude.
+func excluded(pod metav1.Object, exclude labels.Selector) bool {
+	if pod.GetAnnotations()[podExcludeAnnotation] == "true" {
+		return true
+	}
+	return exclude != nil && exclude.Matches(labels.Set(pod.GetLabels()))
+}
+
+// withoutExcluded drops the pods that are excluded from restarts.
+func withoutExcluded(ctx context.Context, pods []corev1.Pod, exclude labels.Selector) []corev1.Pod {
+	log := logf.FromContext(ctx)
+
+	kept := make([]corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if excluded(&pod, exclude) {
+			log.Info("Skipping excluded pod", "pod", pod.Name)
+			continue
+		}
+		kept = append(kept, pod)
+	}
+	return kept
+}
+
+// controllerOwned drops the pods without a controller owner reference.
+func controllerOwned(ctx context.Context, pods []corev1.Pod) []corev1.Pod {
+	log := logf.FromContext(ctx)
+
+	owned := make([]corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if metav1.GetControllerOfNoCopy(&pod) == nil {
+			log.Info("Skipping pod without a controller owner", "pod", pod.Name)
+			continue
+		}
+		owned = append(owned, pod)
+	}
+	return owned
+}
+
+// withoutTerminating drops the pods that are already being deleted, for
+// example by an overlapping restart, and returns how many were dropped.
+func withoutTerminating(pods []corev1.Pod) (running []corev1.Pod, terminating int) {
+	running = make([]corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil {
+			terminating++
+			continue
+		}
+		running = append(running, pod)
+	}
+	return running, terminating
+}
+
+// inPhases returns the pods whose phase is one of phases, or every pod when
+// phases is empty.
+func inPhases(pods []corev1.Pod, phases []corev1.PodPhase) []corev1.Pod {
+	if len(phases) == 0 {
+		return pods
+	}
+	matching := make([]corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if slices.Contains(phases, pod.Status.Phase) {
+			matching = append(matching, pod)
+		}
+	}
+	return matching
+}
+
+// overRestartCount returns the pods with a container or init container that
+// restarted more than threshold times.
+func overRestartCount(pods []corev1.Pod, threshold int32) []corev1.Pod {
+	over := func(statuses []corev1.ContainerStatus) bool {
+		return slices.ContainsFunc(statuses, func(status corev1.ContainerStatus) bool {
+			return status.RestartCount > threshold
+		})
+	}
+	matching := make([]corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if over(pod.Status.ContainerStatuses) || over(pod.Status.InitContainerStatuses) {
+			matching = append(matching, pod)
+		}
+	}
+	return matching
+}
+
+// podCooldownRemaining returns how long pod is still protected by its
+// min-interval annotation. A pod is (re)created by every restart, so its age
+// is the time since it was last restarted.
+func podCooldownRemaining(ctx context.Context, pod metav1.Object, now time.Time) time.Duration {
+	value, ok := pod.GetAnnotations()[podMinIntervalAnnotation]
+	if !ok {
+		return 0
+	}
+	minInterval, err := time.ParseDuration(value)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Ignoring invalid pod annotation",
+			"pod", pod.GetName(), "annotation", podMinIntervalAnnotation, "value", value)
+		return 0
+	}
+
+	created := pod.GetCreationTimestamp()
+	remaining := created.Add(minInterval).Sub(now)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// matchingPods returns the pods for which expression evaluates to true. Pods
+// the expression cannot be evaluated against are logged and left out.
+func matchingPods(ctx context.Context, matcher *podmatch.Matcher, pods []corev1.Pod) []corev1.Pod {
+	log := logf.FromContext(ctx)
+
+	matching := make([]corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		matched, err := matcher.Matches(&pod)
+		if err != nil {
+			log.Error(err, "Failed to evaluate match expression", "pod", pod.Name)
+			continue
+		}
+		if matched {
+			matching = append(matching, pod)
+		}
+	}
+	return matching
+}
@@ -0,0 +1,431 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+	"github.com/crazyfrankie/autorestart-operator/internal/podfields"
+)
+
+var _ = Describe("Pod filtering", func() {
+	var now time.Time
+
+	BeforeEach(func() {
+		now = time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC)
+	})
+
+	Context("with the per-pod min-interval annotation", func() {
+		It("should skip a pod restarted within its cooldown", func() {
+			protected := newTestPod("nginx-protected")
+			protected.CreationTimestamp = metav1.NewTime(now.Add(-30 * time.Minute))
+			protected.Annotations = map[string]string{podMinIntervalAnnotation: "1h"}
+
+			expired := newTestPod("nginx-expired")
+			expired.CreationTimestamp = metav1.NewTime(now.Add(-2 * time.Hour))
+			expired.Annotations = map[string]string{podMinIntervalAnnotation: "1h"}
+
+			plain := newTestPod("nginx-plain")
+			plain.CreationTimestamp = metav1.NewTime(now.Add(-time.Minute))
+
+			obj := newTestAutoRestartPod("pod-cooldown", "*/5 * * * *")
+			c := newFakeClient(obj, protected, expired, plain)
+			r := newTestReconciler(c, now)
+
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, protected)).To(BeTrue())
+			Expect(podExists(c, expired)).To(BeFalse())
+			Expect(podExists(c, plain)).To(BeFalse())
+		})
+
+		It("should ignore an unparsable annotation", func() {
+			pod := newTestPod("nginx-0")
+			pod.CreationTimestamp = metav1.NewTime(now)
+			pod.Annotations = map[string]string{podMinIntervalAnnotation: "soon"}
+			Expect(podCooldownRemaining(context.Background(), pod, now)).To(BeZero())
+		})
+	})
+
+	Context("with a match expression", func() {
+		It("should only restart pods the expression selects", func() {
+			gpu := newTestPod("nginx-gpu")
+			gpu.Spec.NodeName = "gpu-node-1"
+			cpu := newTestPod("nginx-cpu")
+			cpu.Spec.NodeName = "cpu-node-1"
+
+			obj := newTestAutoRestartPod("pod-expression", "*/5 * * * *")
+			obj.Spec.MatchExpression = "pod.spec.nodeName.startsWith('gpu-')"
+			c := newFakeClient(obj, gpu, cpu)
+			r := newTestReconciler(c, now)
+
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, gpu)).To(BeFalse())
+			Expect(podExists(c, cpu)).To(BeTrue())
+		})
+
+		It("should leave out pods the expression cannot be evaluated against", func() {
+			annotated := newTestPod("nginx-annotated")
+			annotated.Annotations = map[string]string{"restart": "true"}
+			plain := newTestPod("nginx-plain")
+
+			obj := newTestAutoRestartPod("pod-expression", "*/5 * * * *")
+			obj.Spec.MatchExpression = "pod.metadata.annotations.restart == 'true'"
+			c := newFakeClient(obj, annotated, plain)
+			r := newTestReconciler(c, now)
+
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, annotated)).To(BeFalse())
+			Expect(podExists(c, plain)).To(BeTrue())
+		})
+
+		It("should fail the reconcile for an expression that does not compile", func() {
+			obj := newTestAutoRestartPod("pod-expression", "*/5 * * * *")
+			obj.Spec.MatchExpression = "pod.spec.nodeName.startsWith("
+			c := newFakeClient(obj, newTestPod("nginx-0"))
+			r := newTestReconciler(c, now)
+
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).To(HaveOccurred())
+			Expect(podExists(c, newTestPod("nginx-0"))).To(BeTrue())
+		})
+	})
+
+	Context("with terminating pods", func() {
+		It("should only delete the pods that are still running", func() {
+			terminating := newTestPod("nginx-terminating")
+			terminating.Finalizers = []string{"example.com/hold"}
+			terminating.DeletionTimestamp = &metav1.Time{Time: now.Add(-time.Second)}
+			running := newTestPod("nginx-running")
+
+			obj := newTestAutoRestartPod("pod-terminating", "*/5 * * * *")
+			deletes := 0
+			c := newFakeClientBuilder(obj, terminating, running).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Delete: func(ctx context.Context, c client.WithWatch, o client.Object, opts ...client.DeleteOption) error {
+						deletes++
+						return c.Delete(ctx, o, opts...)
+					},
+				}).Build()
+			r := newTestReconciler(c, now)
+
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deletes).To(Equal(1))
+			Expect(podExists(c, running)).To(BeFalse())
+			Expect(podExists(c, terminating)).To(BeTrue())
+
+			updated := &stablev1.AutoRestartPod{}
+			Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+			Expect(updated.Status.TerminatingPods).To(Equal(int32(1)))
+		})
+	})
+
+	Context("with a pod phase filter", func() {
+		It("should only restart pods in the configured phases", func() {
+			running := newTestPod("nginx-running")
+			running.Status.Phase = corev1.PodRunning
+			pending := newTestPod("nginx-pending")
+			pending.Status.Phase = corev1.PodPending
+			failed := newTestPod("nginx-failed")
+			failed.Status.Phase = corev1.PodFailed
+
+			obj := newTestAutoRestartPod("pod-phases", "*/5 * * * *")
+			obj.Spec.PodPhaseFilter = []corev1.PodPhase{corev1.PodRunning, corev1.PodFailed}
+			c := newFakeClient(obj, running, pending, failed)
+			r := newTestReconciler(c, now)
+
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, running)).To(BeFalse())
+			Expect(podExists(c, pending)).To(BeTrue())
+			Expect(podExists(c, failed)).To(BeFalse())
+		})
+
+		It("should keep every pod without a filter", func() {
+			pending := newTestPod("nginx-pending")
+			pending.Status.Phase = corev1.PodPending
+			Expect(inPhases([]corev1.Pod{*pending}, nil)).To(HaveLen(1))
+		})
+	})
+
+	Context("with a container restart count threshold", func() {
+		// newRestartedPod returns a pod whose containers restarted the given numbers of times.
+		newRestartedPod := func(name string, counts ...int32) *corev1.Pod {
+			pod := newTestPod(name)
+			for i, count := range counts {
+				pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+					Name:         fmt.Sprintf("c%d", i),
+					RestartCount: count,
+				})
+			}
+			return pod
+		}
+
+		It("should only restart pods with a container over the threshold", func() {
+			healthy := newRestartedPod("nginx-healthy", 0)
+			atThreshold := newRestartedPod("nginx-at", 5, 1)
+			crashing := newRestartedPod("nginx-crashing", 1, 12)
+			initCrashing := newRestartedPod("nginx-init")
+			initCrashing.Status.InitContainerStatuses = []corev1.ContainerStatus{{Name: "init", RestartCount: 6}}
+			unstarted := newTestPod("nginx-unstarted")
+
+			obj := newTestAutoRestartPod("crash-loops", "*/5 * * * *")
+			obj.Spec.MinContainerRestartCount = ptr.To[int32](5)
+			c := newFakeClient(obj, healthy, atThreshold, crashing, initCrashing, unstarted)
+			r := newTestReconciler(c, now)
+
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, healthy)).To(BeTrue())
+			Expect(podExists(c, atThreshold)).To(BeTrue(), "the threshold itself is not exceeded")
+			Expect(podExists(c, crashing)).To(BeFalse())
+			Expect(podExists(c, initCrashing)).To(BeFalse())
+			Expect(podExists(c, unstarted)).To(BeTrue())
+		})
+
+		It("should restart pods with any restart when the threshold is zero", func() {
+			pods := []corev1.Pod{*newRestartedPod("nginx-0", 0), *newRestartedPod("nginx-1", 0, 1)}
+			Expect(overRestartCount(pods, 0)).To(ConsistOf(HaveField("Name", "nginx-1")))
+		})
+	})
+
+	Context("with a field selector", func() {
+		// newIndexedClient returns a fake client with the pod field indexes
+		// the controller registers with the manager.
+		newIndexedClient := func(objs ...client.Object) client.Client {
+			builder := newFakeClientBuilder(objs...)
+			for field, index := range podfields.Indexers() {
+				builder = builder.WithIndex(&corev1.Pod{}, field, index)
+			}
+			return builder.Build()
+		}
+
+		It("should restart the pods matching both the label and the field selector", func() {
+			obj := newTestAutoRestartPod("fields", "*/5 * * * *")
+			obj.Spec.FieldSelector = "spec.nodeName=node-1,status.phase=Running"
+			pod := func(name, app, node string, phase corev1.PodPhase) *corev1.Pod {
+				pod := newTestPod(name)
+				pod.Labels = map[string]string{"app": app}
+				pod.Spec.NodeName = node
+				pod.Status.Phase = phase
+				return pod
+			}
+			c := newIndexedClient(obj,
+				pod("match", "nginx", "node-1", corev1.PodRunning),
+				pod("other-node", "nginx", "node-2", corev1.PodRunning),
+				pod("pending", "nginx", "node-1", corev1.PodPending),
+				pod("other-app", "web", "node-1", corev1.PodRunning))
+			r := newTestReconciler(c, now)
+
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remainingPods(c)).To(ConsistOf("other-node", "pending", "other-app"))
+		})
+
+		It("should refuse a field that is not indexed", func() {
+			obj := newTestAutoRestartPod("fields", "*/5 * * * *")
+			obj.Spec.FieldSelector = "spec.hostname=web"
+			c := newIndexedClient(obj, newTestPod("nginx-0"))
+			r := newTestReconciler(c, now)
+
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).To(MatchError(ContainSubstring("invalid field selector")))
+			Expect(remainingPods(c)).To(ConsistOf("nginx-0"))
+		})
+	})
+
+	Context("with excluded pods", func() {
+		It("should keep pods excluded by annotation or by the exclude selector", func() {
+			obj := newTestAutoRestartPod("exclude", "*/5 * * * *")
+			obj.Spec.ExcludeSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"role": "leader"}}
+			annotated := newTestPod("nginx-pinned")
+			annotated.Annotations = map[string]string{podExcludeAnnotation: "true"}
+			leader := newTestPod("nginx-leader")
+			leader.Labels["role"] = "leader"
+			notPinned := newTestPod("nginx-not-pinned")
+			notPinned.Annotations = map[string]string{podExcludeAnnotation: "false"}
+			c := newFakeClient(obj, annotated, leader, notPinned, newTestPod("nginx-0"))
+			r := newTestReconciler(c, now)
+
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remainingPods(c)).To(ConsistOf("nginx-pinned", "nginx-leader"))
+		})
+
+		It("should leave excluded pods out of the metadata pre-count", func() {
+			obj := newTestAutoRestartPod("exclude", "*/5 * * * *")
+			obj.Spec.MaxPodsPerRestart = ptr.To[int32](1)
+			obj.Spec.OverflowPolicy = stablev1.RefuseOverflow
+			annotated := newTestPod("nginx-pinned")
+			annotated.Annotations = map[string]string{podExcludeAnnotation: "true"}
+			c := newFakeClient(obj, annotated, newTestPod("nginx-0"))
+			r := newTestReconciler(c, now)
+
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remainingPods(c)).To(ConsistOf("nginx-pinned"))
+		})
+	})
+
+	Context("with required controller owners", func() {
+		// owned returns a pod named name controlled by a ReplicaSet
+		owned := func(name string) *corev1.Pod {
+			pod := newTestPod(name)
+			pod.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet",
+				Name: "nginx-5d4f8", UID: "rs-uid", Controller: ptr.To(true)}}
+			return pod
+		}
+
+		It("should only restart pods with a controller owner", func() {
+			obj := newTestAutoRestartPod("owned", "*/5 * * * *")
+			obj.Spec.RequireControllerOwner = true
+			orphan := newTestPod("nginx-orphan")
+			c := newFakeClient(obj, owned("nginx-owned"), orphan)
+			r := newTestReconciler(c, now)
+
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remainingPods(c)).To(ConsistOf("nginx-orphan"))
+		})
+
+		It("should not count a pod owned without controller ref in the metadata pre-count", func() {
+			obj := newTestAutoRestartPod("owned", "*/5 * * * *")
+			obj.Spec.RequireControllerOwner = true
+			obj.Spec.MaxPodsPerRestart = ptr.To[int32](1)
+			obj.Spec.OverflowPolicy = stablev1.RefuseOverflow
+			referenced := newTestPod("nginx-referenced")
+			referenced.OwnerReferences = []metav1.OwnerReference{{APIVersion: "v1", Kind: "ConfigMap",
+				Name: "nginx", UID: "cm-uid"}}
+			c := newFakeClient(obj, owned("nginx-owned"), referenced)
+			r := newTestReconciler(c, now)
+
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remainingPods(c)).To(ConsistOf("nginx-referenced"))
+		})
+	})
+
+	Context("with listed namespaces", func() {
+		// inNamespace returns a pod named name in namespace
+		inNamespace := func(namespace, name string) *corev1.Pod {
+			pod := newTestPod(name)
+			pod.Namespace = namespace
+			return pod
+		}
+
+		It("should restart the matching pods of every listed namespace only", func() {
+			obj := newTestAutoRestartPod("namespaces", "*/5 * * * *")
+			obj.Spec.Namespaces = []string{"dev", "staging", "prod"}
+			pods := []*corev1.Pod{inNamespace("dev", "nginx-0"), inNamespace("staging", "nginx-0"),
+				inNamespace("prod", "nginx-0")}
+			excluded := inNamespace("other", "nginx-0")
+			own := newTestPod("nginx-own")
+			c := newFakeClient(obj, pods[0], pods[1], pods[2], excluded, own)
+			r := newTestReconciler(c, now)
+
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			for _, pod := range pods {
+				Expect(podExists(c, pod)).To(BeFalse(), pod.Namespace)
+			}
+			Expect(podExists(c, excluded)).To(BeTrue())
+			Expect(podExists(c, own)).To(BeTrue(), "the own namespace is only used when none are listed")
+		})
+
+		It("should restart the other namespaces when one cannot be listed", func() {
+			obj := newTestAutoRestartPod("namespaces", "*/5 * * * *")
+			obj.Spec.Namespaces = []string{"dev", "staging", "prod"}
+			pods := []*corev1.Pod{inNamespace("dev", "nginx-0"), inNamespace("staging", "nginx-0"),
+				inNamespace("prod", "nginx-0")}
+			c := newFakeClientBuilder(obj, pods[0], pods[1], pods[2]).
+				WithInterceptorFuncs(interceptor.Funcs{
+					List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+						listOpts := &client.ListOptions{}
+						listOpts.ApplyOptions(opts)
+						if _, ok := list.(*corev1.PodList); ok && listOpts.Namespace == "staging" {
+							return apierrors.NewForbidden(corev1.Resource("pods"), "", errors.New("no RBAC"))
+						}
+						return c.List(ctx, list, opts...)
+					},
+				}).Build()
+			r := newTestReconciler(c, now)
+
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, pods[0])).To(BeFalse())
+			Expect(podExists(c, pods[1])).To(BeTrue(), "the pods of staging cannot be seen")
+			Expect(podExists(c, pods[2])).To(BeFalse())
+
+			updated := &stablev1.AutoRestartPod{}
+			Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+			condition := meta.FindStatusCondition(updated.Status.Conditions, stablev1.ConditionNamespacesUnavailable)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("ListFailed"))
+			Expect(condition.Message).To(HavePrefix("namespace staging: "))
+			Expect(condition.Message).To(ContainSubstring("forbidden"))
+		})
+
+		It("should fail when no listed namespace can be listed", func() {
+			obj := newTestAutoRestartPod("namespaces", "*/5 * * * *")
+			obj.Spec.Namespaces = []string{"dev", "staging"}
+			c := newFakeClientBuilder(obj, inNamespace("dev", "nginx-0")).
+				WithInterceptorFuncs(interceptor.Funcs{
+					List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+						if _, ok := list.(*corev1.PodList); ok {
+							return apierrors.NewForbidden(corev1.Resource("pods"), "", errors.New("no RBAC"))
+						}
+						return c.List(ctx, list, opts...)
+					},
+				}).Build()
+			r := newTestReconciler(c, now)
+
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).To(MatchError(ContainSubstring("namespace dev: ")))
+			Expect(err).To(MatchError(ContainSubstring("namespace staging: ")))
+			Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		})
+
+		It("should enqueue the object for pods of a listed namespace", func() {
+			obj := newTestAutoRestartPod("namespaces", "*/5 * * * *")
+			obj.Spec.Namespaces = []string{"dev"}
+			c := newFakeClient(obj)
+			r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme()}
+
+			Expect(r.podToRequests(context.Background(), inNamespace("dev", "nginx-0"))).
+				To(ConsistOf(requestFor(obj)))
+			Expect(r.podToRequests(context.Background(), newTestPod("nginx-0"))).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// restartFinalizer keeps a deleted AutoRestartPod around until a restart that
+// was split over several passes has finished.
+const restartFinalizer = "stable.crazyfrank.com/finalizer"
+
+// finalize finishes the active run of a deleted AutoRestartPod, one pass at a
+// time, and drops its metrics and the finalizer once no run is left. A run
+// past Spec.RunTimeout, or one failing on a target that is gone or
+// forbidden, is given up instead.
+func (r *AutoRestartPodReconciler) finalize(ctx context.Context, obj *stablev1.AutoRestartPod) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(obj, restartFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	// Replacements need not be waited for, the restart itself is complete
+	if run := obj.Status.ActiveRun; run != nil && !run.AwaitingReplacements {
+		now := r.now()
+		if runTimedOut(obj, now) {
+			if err := r.stopTimedOutRun(ctx, obj, now); err != nil {
+				return ctrl.Result{}, err
+			}
+			return r.removeFinalizer(ctx, obj)
+		}
+		if last := obj.Status.LastRestartTime; last != nil {
+			if wait := last.Add(runRequeueInterval).Sub(now); wait > 0 {
+				return ctrl.Result{RequeueAfter: wait}, nil
+			}
+		}
+
+		log.Info("Finishing in-progress restart before deletion")
+		pending, err := r.restart(ctx, obj, now, obj.Status.ActiveRun.ScheduledTime.Time)
+		// A target or namespace that is gone or forbidden stays so, and
+		// blocking the deletion on it would keep the object forever
+		if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+			if err := r.dropRun(ctx, obj, err.Error()); err != nil {
+				return ctrl.Result{}, err
+			}
+			return r.removeFinalizer(ctx, obj)
+		}
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if pending {
+			return ctrl.Result{RequeueAfter: runRequeueInterval}, nil
+		}
+	}
+	return r.removeFinalizer(ctx, obj)
+}
+
+// removeFinalizer drops the metrics of a deleted obj and lets it go.
+func (r *AutoRestartPodReconciler) removeFinalizer(ctx context.Context, obj *stablev1.AutoRestartPod) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	restartLag.DeleteLabelValues(obj.Namespace, obj.Name)
+	scheduleParseErrors.DeleteLabelValues(obj.Namespace, obj.Name)
+	// The patch leaves out the fields obj inherited from its policy
+	patch := client.MergeFrom(obj.DeepCopy())
+	controllerutil.RemoveFinalizer(obj, restartFinalizer)
+	if err := r.Patch(ctx, obj, patch); err != nil {
+		log.Error(err, "Failed to remove finalizer")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// dropRun gives up the active run of a deleted obj without restarting the
+// pods it has left, for why, so that the finalizer can be removed.
+func (r *AutoRestartPodReconciler) dropRun(ctx context.Context, obj *stablev1.AutoRestartPod, why string) error {
+	if obj.Status.ActiveRun == nil {
+		return nil
+	}
+	log := logf.FromContext(ctx)
+
+	log.Info("Dropping in-progress restart before deletion", "reason", why)
+	r.warn(ctx, obj, "RestartDropped", "in-progress restart not finished before deletion: "+why)
+	obj.Status.ActiveRun = nil
+	if err := r.Status().Update(ctx, obj); err != nil {
+		log.Error(err, "Failed to update AutoRestartPod status")
+		return err
+	}
+	return nil
+}
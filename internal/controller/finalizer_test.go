@@ -0,0 +1,215 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Finalizer", func() {
+	var fakeClock *testingclock.FakePassiveClock
+
+	BeforeEach(func() {
+		fakeClock = testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+	})
+
+	It("should add the finalizer on the first reconcile", func() {
+		obj := newTestAutoRestartPod("finalized", "0 3 * * *")
+		c := newFakeClient(obj)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Finalizers).To(ContainElement(restartFinalizer))
+	})
+
+	It("should finish an in-progress restart before letting the object go", func() {
+		obj := newTestAutoRestartPod("finalized", "*/5 * * * *")
+		obj.Spec.MaxPodsPerRestart = ptr.To[int32](1)
+		c := newFakeClient(append(newAgedPods(3, fakeClock.Now()), obj)...)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		// The first pass restarts one pod and leaves the run active
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ConsistOf("nginx-1", "nginx-2"))
+
+		Expect(c.Delete(context.Background(), obj)).To(Succeed())
+
+		fakeClock.SetTime(fakeClock.Now().Add(runRequeueInterval))
+		result, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(runRequeueInterval))
+		Expect(remainingPods(c)).To(ConsistOf("nginx-2"))
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Finalizers).To(ContainElement(restartFinalizer))
+
+		fakeClock.SetTime(fakeClock.Now().Add(runRequeueInterval))
+		_, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(BeEmpty())
+
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	Context("with a policy", func() {
+		var policy *stablev1.AutoRestartPolicy
+
+		BeforeEach(func() {
+			policy = &stablev1.AutoRestartPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "newest"},
+				Spec:       stablev1.AutoRestartPolicySpec{RestartOrder: stablev1.NewestFirst},
+			}
+		})
+
+		// startRun restarts the first pod of obj and deletes obj with the
+		// run still active.
+		startRun := func(c client.Client, r *AutoRestartPodReconciler, obj *stablev1.AutoRestartPod) {
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remainingPods(c)).To(ConsistOf("nginx-0", "nginx-1"))
+			Expect(c.Delete(context.Background(), obj)).To(Succeed())
+			fakeClock.SetTime(fakeClock.Now().Add(runRequeueInterval))
+		}
+
+		It("should finish the restart with the defaults of the policy", func() {
+			obj := newTestAutoRestartPod("finalized", "*/5 * * * *")
+			obj.Spec.PolicyRef = &stablev1.PolicyReference{Name: policy.Name}
+			obj.Spec.MaxPodsPerRestart = ptr.To[int32](1)
+			c := newFakeClient(append(newAgedPods(3, fakeClock.Now()), obj, policy)...)
+			r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+			startRun(c, r, obj)
+
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remainingPods(c)).To(ConsistOf("nginx-0"), "the newest pods go first")
+
+			updated := &stablev1.AutoRestartPod{}
+			Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+			Expect(updated.Spec.RestartOrder).To(BeEmpty(), "the inherited defaults are never written")
+		})
+
+		It("should drop the restart when the policy is gone", func() {
+			obj := newTestAutoRestartPod("finalized", "*/5 * * * *")
+			obj.Spec.PolicyRef = &stablev1.PolicyReference{Name: policy.Name}
+			obj.Spec.MaxPodsPerRestart = ptr.To[int32](1)
+			c := newFakeClient(append(newAgedPods(3, fakeClock.Now()), obj, policy)...)
+			recorder := record.NewFakeRecorder(10)
+			r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock, Recorder: recorder}
+			startRun(c, r, obj)
+			Expect(c.Delete(context.Background(), policy)).To(Succeed())
+
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remainingPods(c)).To(ConsistOf("nginx-0", "nginx-1"))
+			err = c.Get(context.Background(), client.ObjectKeyFromObject(obj), &stablev1.AutoRestartPod{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			var events []string
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			Expect(events).To(ContainElement(HavePrefix("Warning RestartDropped")))
+		})
+	})
+
+	It("should let the object go when its target workload no longer exists", func() {
+		deploy := newTestDeployment("web")
+		obj := newTestAutoRestartPod("finalized", "*/5 * * * *")
+		obj.Spec.Selector = nil
+		obj.Spec.TargetRef = &stablev1.TargetReference{Kind: "Deployment", Name: "web"}
+		obj.Spec.MaxPodsPerRestart = ptr.To[int32](1)
+		pods := newAgedPods(2, fakeClock.Now())
+		for _, pod := range pods {
+			pod.SetLabels(map[string]string{"app": "web"})
+		}
+		c := newFakeClient(append(pods, obj, deploy)...)
+		recorder := record.NewFakeRecorder(10)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock, Recorder: recorder}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ConsistOf("nginx-1"))
+
+		Expect(c.Delete(context.Background(), deploy)).To(Succeed())
+		Expect(c.Delete(context.Background(), obj)).To(Succeed())
+		fakeClock.SetTime(fakeClock.Now().Add(runRequeueInterval))
+		_, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ConsistOf("nginx-1"))
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(obj), &stablev1.AutoRestartPod{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		var events []string
+		for len(recorder.Events) > 0 {
+			events = append(events, <-recorder.Events)
+		}
+		Expect(events).To(ContainElement(HavePrefix("Warning RestartDropped")))
+	})
+
+	It("should stop a run past its timeout instead of finishing it", func() {
+		obj := newTestAutoRestartPod("finalized", "*/5 * * * *")
+		obj.Spec.MaxPodsPerRestart = ptr.To[int32](1)
+		obj.Spec.RunTimeout = &metav1.Duration{Duration: 10 * time.Minute}
+		c := newFakeClient(append(newAgedPods(3, fakeClock.Now()), obj)...)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Delete(context.Background(), obj)).To(Succeed())
+
+		fakeClock.SetTime(fakeClock.Now().Add(10 * time.Minute))
+		_, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ConsistOf("nginx-1", "nginx-2"))
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(obj), &stablev1.AutoRestartPod{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should remove the finalizer right away when no restart is running", func() {
+		obj := newTestAutoRestartPod("finalized", "0 3 * * *")
+		c := newFakeClient(obj, newTestPod("nginx-0"))
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Delete(context.Background(), obj)).To(Succeed())
+
+		_, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, newTestPod("nginx-0"))).To(BeTrue())
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(obj), &stablev1.AutoRestartPod{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// fractionBatch narrows pods down to the share of Spec.RestartFraction left
+// for this fire, restartedSoFar pods of which were restarted by earlier
+// passes. Pods not yet restarted in the current cycle go first and keep
+// their order; a new cycle starts once every pod was restarted.
+func fractionBatch(obj *stablev1.AutoRestartPod, pods []corev1.Pod, restartedSoFar int) ([]corev1.Pod, error) {
+	count, err := intstr.GetScaledValueFromIntOrPercent(obj.Spec.RestartFraction, len(pods)+restartedSoFar, true)
+	if err != nil {
+		return nil, fmt.Errorf("invalid restartFraction: %w", err)
+	}
+	count = min(count-restartedSoFar, len(pods))
+	if count <= 0 {
+		return nil, nil
+	}
+
+	var cycle []string
+	fresh := make([]corev1.Pod, 0, len(pods))
+	var stale []corev1.Pod
+	for _, pod := range pods {
+		if slices.Contains(obj.Status.FractionRestartedPods, pod.Name) {
+			cycle = append(cycle, pod.Name)
+			stale = append(stale, pod)
+		} else {
+			fresh = append(fresh, pod)
+		}
+	}
+	if len(fresh) == 0 {
+		cycle, fresh, stale = nil, stale, nil
+	}
+	// Pods that are gone when a fire starts no longer count toward the
+	// cycle. Later passes of the fire do not see the pods restarted by
+	// earlier ones, so they leave the cycle alone.
+	if restartedSoFar == 0 {
+		obj.Status.FractionRestartedPods = cycle
+	}
+	return append(fresh, stale...)[:count], nil
+}
+
+// recordFractionRestarts adds the restarted pods to the current cycle of
+// Spec.RestartFraction.
+func recordFractionRestarts(obj *stablev1.AutoRestartPod, restarted []string) {
+	for _, name := range restarted {
+		if !slices.Contains(obj.Status.FractionRestartedPods, name) {
+			obj.Status.FractionRestartedPods = append(obj.Status.FractionRestartedPods, name)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Restart fraction", func() {
+	var obj *stablev1.AutoRestartPod
+
+	BeforeEach(func() {
+		obj = newTestAutoRestartPod("fraction", "*/5 * * * *")
+		fraction := intstr.FromString("25%")
+		obj.Spec.RestartFraction = &fraction
+	})
+
+	It("should cycle through the pods over successive fires", func() {
+		ctx := context.Background()
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		var pods []*corev1.Pod
+		objs := []client.Object{obj}
+		for i := range 8 {
+			pod := newTestPod(fmt.Sprintf("nginx-%d", i))
+			pods = append(pods, pod)
+			objs = append(objs, pod)
+		}
+		c := newFakeClient(objs...)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		// fire restarts the pods due at the next tick and recreates them
+		// under the same name, like a StatefulSet does
+		fire := func() []string {
+			_, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			var restarted []string
+			for _, pod := range pods {
+				if !podExists(c, pod) {
+					restarted = append(restarted, pod.Name)
+					Expect(c.Create(ctx, newTestPod(pod.Name))).To(Succeed())
+				}
+			}
+			fakeClock.SetTime(fakeClock.Now().Add(5 * time.Minute))
+			return restarted
+		}
+
+		seen := map[string]bool{}
+		for i := range 4 {
+			restarted := fire()
+			Expect(restarted).To(HaveLen(2), "fire %d", i)
+			for _, name := range restarted {
+				Expect(seen).NotTo(HaveKey(name), "fire %d", i)
+				seen[name] = true
+			}
+		}
+		Expect(seen).To(HaveLen(8))
+
+		By("starting a new cycle once every pod was restarted")
+		Expect(fire()).To(HaveLen(2))
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.FractionRestartedPods).To(HaveLen(2))
+	})
+
+	It("should leave the share of earlier passes out", func() {
+		pods := []corev1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "nginx-0"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "nginx-1"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "nginx-2"}},
+		}
+		obj.Status.FractionRestartedPods = []string{"nginx-3", "nginx-4"}
+
+		// 25% of the five pods of the fire rounds up to two, both of which
+		// earlier passes restarted
+		batch, err := fractionBatch(obj, pods, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(batch).To(BeEmpty())
+		Expect(obj.Status.FractionRestartedPods).To(ConsistOf("nginx-3", "nginx-4"))
+
+		// 50% rounds up to three, two of which earlier passes restarted
+		half := intstr.FromString("50%")
+		obj.Spec.RestartFraction = &half
+		batch, err = fractionBatch(obj, pods, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(batch).To(HaveLen(1))
+	})
+
+	It("should forget pods that are gone when a fire starts", func() {
+		pods := []corev1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "nginx-0"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "nginx-1"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "nginx-2"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "nginx-3"}},
+		}
+		obj.Status.FractionRestartedPods = []string{"nginx-0", "nginx-gone"}
+
+		batch, err := fractionBatch(obj, pods, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(batch).To(HaveLen(1))
+		Expect(batch[0].Name).To(Equal("nginx-1"))
+		Expect(obj.Status.FractionRestartedPods).To(Equal([]string{"nginx-0"}))
+	})
+})
@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// updateHealth reports whether at least Spec.MinHealthyFraction of the pods
+// obj matches are Ready, and records the outcome in the Skipped condition.
+// The condition is only reported False once a tick was skipped before.
+// Ticks without matching pods are healthy, as nothing can get worse.
+func (r *AutoRestartPodReconciler) updateHealth(ctx context.Context, obj *stablev1.AutoRestartPod) (bool, error) {
+	if obj.Spec.MinHealthyFraction == "" {
+		return true, nil
+	}
+	minHealthy, err := strconv.ParseFloat(obj.Spec.MinHealthyFraction, 64)
+	if err != nil || minHealthy < 0 || minHealthy > 1 {
+		return false, fmt.Errorf("invalid minHealthyFraction %q, must be between 0 and 1", obj.Spec.MinHealthyFraction)
+	}
+
+	_, selector, err := r.podSelector(ctx, obj)
+	if err != nil {
+		return false, err
+	}
+	pods, err := r.listPods(ctx, obj, client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list pods", "selector", selector.String())
+		return false, err
+	}
+	running, _ := withoutTerminating(pods)
+	var ready int
+	for i := range running {
+		if isPodReady(&running[i]) {
+			ready++
+		}
+	}
+
+	if len(running) == 0 || float64(ready) >= minHealthy*float64(len(running)) {
+		if getCondition(obj, stablev1.ConditionSkipped) != nil {
+			setCondition(obj, stablev1.ConditionSkipped, metav1.ConditionFalse, "Healthy",
+				fmt.Sprintf("%d of %d pods are Ready", ready, len(running)))
+		}
+		return true, nil
+	}
+	setCondition(obj, stablev1.ConditionSkipped, metav1.ConditionTrue, "Unhealthy",
+		fmt.Sprintf("only %d of %d pods are Ready, less than minHealthyFraction %s",
+			ready, len(running), obj.Spec.MinHealthyFraction))
+	return false, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Minimum healthy fraction", func() {
+	now := time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC)
+
+	// newHealthObjects returns five pods, the first ready of which are
+	// Ready, and an object requiring half of them to be Ready
+	newHealthObjects := func(ready int) []client.Object {
+		obj := newTestAutoRestartPod("health", "*/5 * * * *")
+		obj.Spec.MinHealthyFraction = "0.5"
+		objs := append(newAgedPods(5, now), obj)
+		for i := range ready {
+			pod := objs[i].(*corev1.Pod)
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		}
+		return objs
+	}
+
+	fetch := func(c client.Client) *stablev1.AutoRestartPod {
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "health"}, updated)).To(Succeed())
+		return updated
+	}
+
+	It("should skip the tick when too few pods are Ready", func() {
+		objs := newHealthObjects(1)
+		c := newFakeClient(objs...)
+		r := newTestReconciler(c, now)
+
+		result, err := r.Reconcile(context.Background(), requestFor(objs[len(objs)-1]))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(HaveLen(5))
+		Expect(result.RequeueAfter).To(Equal(30*time.Second + restartWindow))
+
+		updated := fetch(c)
+		Expect(updated.Status.LastScheduleTime.Time).To(BeTemporally("==", time.Date(2025, 5, 26, 10, 5, 0, 0, time.UTC)))
+		cond := getCondition(updated, stablev1.ConditionSkipped)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal("Unhealthy"))
+		Expect(cond.Message).To(Equal("only 1 of 5 pods are Ready, less than minHealthyFraction 0.5"))
+	})
+
+	It("should restart once enough pods are Ready", func() {
+		objs := newHealthObjects(3)
+		c := newFakeClient(objs...)
+		r := newTestReconciler(c, now)
+
+		_, err := r.Reconcile(context.Background(), requestFor(objs[len(objs)-1]))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(BeEmpty())
+		Expect(getCondition(fetch(c), stablev1.ConditionSkipped)).To(BeNil())
+	})
+})
@@ -0,0 +1,37 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"net/http"
+	"time"
+)
+
+// httpClientOrDefault returns c, or a client whose requests time out after
+// timeout when the caller has no client of its own.
+func httpClientOrDefault(c *http.Client, timeout time.Duration) *http.Client {
+	if c == nil {
+		return &http.Client{Timeout: timeout}
+	}
+	return c
+}
+
+// successful reports whether resp has a 2xx status. The webhooks called
+// while restarting treat any other status as a failure.
+func successful(resp *http.Response) bool {
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
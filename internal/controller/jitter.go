@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"hash/fnv"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// maxPrevSearch bounds the doublings of the lookback prevFire searches for
+// the previous fire time, from a second up to about four years.
+const maxPrevSearch = 28
+
+// withJitter returns the top-level schedule of obj moved by
+// Spec.IntervalJitterPercent; schedule is returned unchanged without jitter.
+func withJitter(obj *stablev1.AutoRestartPod, schedule cron.Schedule) cron.Schedule {
+	percent := obj.Spec.IntervalJitterPercent
+	if percent == nil || *percent == 0 {
+		return schedule
+	}
+	return jitteredSchedule{schedule: schedule, percent: *percent, seed: obj.Namespace + "/" + obj.Name}
+}
+
+// jitteredSchedule moves every fire time of schedule by up to percent of the
+// shorter of the intervals before and after it. With at most 50 percent a
+// fire time never passes the middle of those intervals, so the fire times
+// keep their order. The shift of a fire time only depends on seed and the
+// fire time itself.
+type jitteredSchedule struct {
+	schedule cron.Schedule
+	percent  int32
+	seed     string
+}
+
+// Next implements cron.Schedule.
+func (s jitteredSchedule) Next(t time.Time) time.Time {
+	next := s.schedule.Next(t)
+	if next.IsZero() {
+		return next
+	}
+	// The previous fire time may be moved past t and the next one before
+	// it, but the one after that is always later than t
+	after := s.schedule.Next(next)
+	if prev := s.prevFire(t); !prev.IsZero() {
+		if moved := s.move(prev, next); moved.After(t) {
+			return moved
+		}
+	}
+	if moved := s.move(next, after); moved.After(t) {
+		return moved
+	}
+	if after.IsZero() {
+		return after
+	}
+	return s.move(after, s.schedule.Next(after))
+}
+
+// move returns the fire time fire of the schedule moved by its jitter. next
+// is the fire time that follows it, zero when there is none.
+func (s jitteredSchedule) move(fire, next time.Time) time.Time {
+	var interval time.Duration
+	if !next.IsZero() {
+		interval = next.Sub(fire)
+	}
+	if prev := s.prevFire(fire.Add(-time.Nanosecond)); !prev.IsZero() {
+		if before := fire.Sub(prev); interval == 0 || before < interval {
+			interval = before
+		}
+	}
+
+	// A factor in [-1, 1) of the fire time alone keeps the shift stable
+	h := fnv.New64a()
+	h.Write([]byte(s.seed + "/" + strconv.FormatInt(fire.Unix(), 10)))
+	factor := float64(h.Sum64())/math.MaxUint64*2 - 1
+	shift := time.Duration(factor * float64(s.percent) / 100 * float64(interval))
+	return fire.Add(shift).Truncate(time.Second)
+}
+
+// prevFire returns the last fire time of the schedule at or before t, or the
+// zero time when there is none within the search range.
+func (s jitteredSchedule) prevFire(t time.Time) time.Time {
+	lookback := time.Second
+	for range maxPrevSearch {
+		fire := s.schedule.Next(t.Add(-lookback))
+		if !fire.IsZero() && !fire.After(t) {
+			// Walk forward to the last fire time that is not after t
+			for {
+				next := s.schedule.Next(fire)
+				if next.IsZero() || next.After(t) {
+					return fire
+				}
+				fire = next
+			}
+		}
+		lookback *= 2
+	}
+	return time.Time{}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Interval jitter", func() {
+	// Halfway between two fire times, so the first one is at 01:00
+	start := time.Date(2025, 5, 26, 0, 30, 0, 0, time.UTC)
+	first := time.Date(2025, 5, 26, 1, 0, 0, 0, time.UTC)
+
+	// fires returns the first count fire times of the hourly schedule of
+	// obj after start
+	fires := func(obj *stablev1.AutoRestartPod, count int) []time.Time {
+		schedule, err := parseCronSchedule(obj.Spec.Schedule)
+		Expect(err).NotTo(HaveOccurred())
+		schedule = withJitter(obj, schedule)
+
+		var times []time.Time
+		for next := schedule.Next(start); len(times) < count; next = schedule.Next(next) {
+			times = append(times, next)
+		}
+		return times
+	}
+
+	newJittered := func(name string) *stablev1.AutoRestartPod {
+		obj := newTestAutoRestartPod(name, "0 * * * *")
+		obj.Spec.IntervalJitterPercent = ptr.To[int32](10)
+		return obj
+	}
+
+	It("should keep every fire time within the jitter band", func() {
+		times := fires(newJittered("jittered"), 48)
+		var moved bool
+		for i, fire := range times {
+			base := first.Add(time.Duration(i) * time.Hour)
+			Expect(fire).To(BeTemporally("~", base, 6*time.Minute), "fire %d", i)
+			moved = moved || !fire.Equal(base)
+			if i > 0 {
+				Expect(fire.Sub(times[i-1])).To(BeNumerically(">=", 48*time.Minute))
+				Expect(fire.Sub(times[i-1])).To(BeNumerically("<=", 72*time.Minute))
+			}
+		}
+		Expect(moved).To(BeTrue())
+	})
+
+	It("should be reproducible for the same object and differ between objects", func() {
+		Expect(fires(newJittered("jittered"), 24)).To(Equal(fires(newJittered("jittered"), 24)))
+		Expect(fires(newJittered("jittered"), 24)).NotTo(Equal(fires(newJittered("other"), 24)))
+	})
+
+	It("should return the same fire time from anywhere before it", func() {
+		obj := newJittered("jittered")
+		schedule, err := parseCronSchedule(obj.Spec.Schedule)
+		Expect(err).NotTo(HaveOccurred())
+		schedule = withJitter(obj, schedule)
+
+		times := fires(obj, 12)
+		for i := 1; i < len(times); i++ {
+			for t := times[i-1]; t.Before(times[i]); t = t.Add(7 * time.Minute) {
+				Expect(schedule.Next(t)).To(Equal(times[i]), "from %s", t)
+			}
+			Expect(schedule.Next(times[i].Add(-time.Second))).To(Equal(times[i]))
+		}
+	})
+
+	It("should leave the schedule alone without jitter", func() {
+		obj := newTestAutoRestartPod("plain", "0 * * * *")
+		Expect(fires(obj, 3)).To(Equal([]time.Time{first, first.Add(time.Hour), first.Add(2 * time.Hour)}))
+		obj.Spec.IntervalJitterPercent = ptr.To[int32](0)
+		Expect(fires(obj, 1)).To(Equal([]time.Time{first}))
+	})
+
+	It("should restart at the jittered fire time", func() {
+		ctx := context.Background()
+		obj := newJittered("jittered")
+		fire := fires(obj, 1)[0]
+		pod := newTestPod("nginx")
+		c := newFakeClient(obj, pod)
+		fakeClock := testingclock.NewFakePassiveClock(fire.Add(-90 * time.Second))
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		result, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, pod)).To(BeTrue())
+		Expect(result.RequeueAfter).To(Equal(90 * time.Second))
+
+		fakeClock.SetTime(fire.Add(-30 * time.Second))
+		_, err = r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, pod)).To(BeFalse())
+	})
+})
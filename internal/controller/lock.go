@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// objectLocks serializes the reconciles of each object, so that a reconcile
+// only starts once the previous one has recorded its restart. The zero value
+// is ready to use.
+type objectLocks struct {
+	mu    sync.Mutex
+	locks map[types.NamespacedName]*objectLock
+}
+
+// objectLock is the lock of one object, dropped once nobody holds or waits
+// for it.
+type objectLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lock blocks until key is unlocked, locks it and returns the function that
+// unlocks it again.
+func (l *objectLocks) lock(key types.NamespacedName) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = map[types.NamespacedName]*objectLock{}
+	}
+	lock := l.locks[key]
+	if lock == nil {
+		lock = &objectLock{}
+		l.locks[key] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if lock.refs--; lock.refs == 0 {
+			delete(l.locks, key)
+		}
+	}
+}
+
+// len returns the number of objects locked or waited for.
+func (l *objectLocks) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.locks)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var _ = Describe("Object locks", func() {
+	It("should serialize concurrent reconciles of the same object", func() {
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		obj := newTestAutoRestartPod("locked", "*/5 * * * *")
+
+		var deletes, active, overlaps atomic.Int32
+		c := newFakeClientBuilder(obj, newTestPod("nginx-0"), newTestPod("nginx-1"), newTestPod("nginx-2")).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(ctx context.Context, cl client.WithWatch, o client.Object, opts ...client.DeleteOption) error {
+					// Deletions of one reconcile never overlap
+					if active.Add(1) > 1 {
+						overlaps.Add(1)
+					}
+					defer active.Add(-1)
+					deletes.Add(1)
+					time.Sleep(10 * time.Millisecond)
+					return cl.Delete(ctx, o, opts...)
+				},
+			}).
+			Build()
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		var wg sync.WaitGroup
+		for range 5 {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				_, err := r.Reconcile(context.Background(), requestFor(obj))
+				Expect(err).NotTo(HaveOccurred())
+			}()
+		}
+		wg.Wait()
+
+		// Later reconciles see the restart of the first one
+		Expect(overlaps.Load()).To(BeZero())
+		Expect(deletes.Load()).To(BeEquivalentTo(3))
+		Expect(r.locks.len()).To(BeZero())
+	})
+
+	It("should not block reconciles of other objects", func() {
+		var locks objectLocks
+		unlock := locks.lock(types.NamespacedName{Namespace: "default", Name: "a"})
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			locks.lock(types.NamespacedName{Namespace: "default", Name: "b"})()
+		}()
+		Eventually(done).Should(BeClosed())
+		Expect(locks.len()).To(Equal(1))
+
+		unlock()
+		Expect(locks.len()).To(BeZero())
+	})
+
+	It("should wait for the holder of the same object", func() {
+		var locks objectLocks
+		key := types.NamespacedName{Namespace: "default", Name: "a"}
+		unlock := locks.lock(key)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			locks.lock(key)()
+		}()
+		Consistently(done, 50*time.Millisecond).ShouldNot(BeClosed())
+		Expect(locks.len()).To(Equal(1))
+
+		unlock()
+		Eventually(done).Should(BeClosed())
+		Expect(locks.len()).To(BeZero())
+	})
+})
@@ -0,0 +1,46 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// restartLag is how late (positive) or early (negative) the last restart pass
+// of each AutoRestartPod ran compared to its scheduled fire time.
+var restartLag = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "autorestartpod_restart_lag_seconds",
+		Help: "Difference between the last restart pass and its scheduled time, in seconds.",
+	},
+	[]string{"namespace", "name"},
+)
+
+// scheduleParseErrors counts the reconciles of each AutoRestartPod that
+// failed because a schedule did not parse.
+var scheduleParseErrors = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "autorestartpod_parse_errors_total",
+		Help: "Number of reconciles that failed to parse a schedule.",
+	},
+	[]string{"namespace", "name"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(restartLag, scheduleParseErrors)
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// restartLagSeconds reads the restart lag gauge of obj.
+func restartLagSeconds(obj *stablev1.AutoRestartPod) float64 {
+	metric := &dto.Metric{}
+	Expect(restartLag.WithLabelValues(obj.Namespace, obj.Name).Write(metric)).To(Succeed())
+	return metric.GetGauge().GetValue()
+}
+
+var _ = Describe("Schedule lag", func() {
+	var (
+		fakeClock *testingclock.FakePassiveClock
+		obj       *stablev1.AutoRestartPod
+	)
+
+	BeforeEach(func() {
+		fakeClock = testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		obj = newTestAutoRestartPod("lagging", "*/5 * * * *")
+	})
+
+	AfterEach(func() {
+		restartLag.DeleteLabelValues(obj.Namespace, obj.Name)
+	})
+
+	It("should report a restart ahead of its fire time as negative lag", func() {
+		c := newFakeClient(obj, newTestPod("nginx-0"))
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.LastScheduleLag).NotTo(BeNil())
+		Expect(updated.Status.LastScheduleLag.Duration).To(Equal(-30 * time.Second))
+		Expect(restartLagSeconds(obj)).To(Equal(-30.0))
+	})
+
+	It("should report how late a delayed pass ran", func() {
+		scheduled := time.Date(2025, 5, 26, 10, 5, 0, 0, time.UTC)
+		obj.Status.LastRestartTime = &metav1.Time{Time: fakeClock.Now()}
+		obj.Status.ActiveRun = &stablev1.RestartRun{
+			StartTime:     metav1.Time{Time: fakeClock.Now()},
+			ScheduledTime: metav1.Time{Time: scheduled},
+		}
+		pod := newTestPod("nginx-0")
+		pod.CreationTimestamp = metav1.NewTime(fakeClock.Now().Add(-time.Hour))
+		c := newFakeClient(obj, pod)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		// The reconcile for the second pass only comes around two minutes late
+		fakeClock.SetTime(scheduled.Add(2 * time.Minute))
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, pod)).To(BeFalse())
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.LastScheduleLag.Duration).To(Equal(2 * time.Minute))
+		Expect(restartLagSeconds(obj)).To(Equal(120.0))
+	})
+})
+
+// scheduleParseErrorCount reads the schedule parse error counter of obj.
+func scheduleParseErrorCount(obj *stablev1.AutoRestartPod) float64 {
+	metric := &dto.Metric{}
+	Expect(scheduleParseErrors.WithLabelValues(obj.Namespace, obj.Name).Write(metric)).To(Succeed())
+	return metric.GetCounter().GetValue()
+}
+
+var _ = Describe("Schedule parse errors", func() {
+	var obj *stablev1.AutoRestartPod
+
+	BeforeEach(func() {
+		obj = newTestAutoRestartPod("malformed", "*/5 * * *")
+	})
+
+	AfterEach(func() {
+		scheduleParseErrors.DeleteLabelValues(obj.Namespace, obj.Name)
+	})
+
+	It("should count the failures and report them in the InvalidSchedule condition", func() {
+		ctx := context.Background()
+		c := newFakeClient(obj, newTestPod("nginx-0"))
+		r := newTestReconciler(c, time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+
+		for range 2 {
+			// The error is returned so the controller retries with its backoff
+			result, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).To(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+		}
+		Expect(scheduleParseErrorCount(obj)).To(Equal(2.0))
+		Expect(podExists(c, newTestPod("nginx-0"))).To(BeTrue())
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		invalid := getCondition(updated, stablev1.ConditionInvalidSchedule)
+		Expect(invalid).NotTo(BeNil())
+		Expect(invalid.Status).To(Equal(metav1.ConditionTrue))
+		Expect(invalid.Reason).To(Equal("ParseError"))
+		Expect(invalid.Message).To(ContainSubstring("*/5 * * *"))
+
+		updated.Spec.Schedule = "0 3 * * *"
+		Expect(c.Update(ctx, updated)).To(Succeed())
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(getCondition(updated, stablev1.ConditionInvalidSchedule).Status).To(Equal(metav1.ConditionFalse))
+		Expect(scheduleParseErrorCount(obj)).To(Equal(2.0))
+	})
+
+	It("should report a rule that does not parse", func() {
+		obj.Spec.Schedule = ""
+		obj.Spec.Restarts = []stablev1.RestartRule{{Name: "nightly", Schedule: "61 * * * *",
+			Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}}}}
+		c := newFakeClient(obj)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme()}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).To(HaveOccurred())
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(getCondition(updated, stablev1.ConditionInvalidSchedule).Message).To(HavePrefix("rule nightly: "))
+		Expect(scheduleParseErrorCount(obj)).To(Equal(1.0))
+	})
+})
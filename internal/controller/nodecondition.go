@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// onMatchingNodes narrows pods down to those whose node has one of
+// Spec.NodeConditions, when set, and the labels of Spec.NodeSelector. Every
+// node is fetched once.
+func (r *AutoRestartPodReconciler) onMatchingNodes(ctx context.Context, obj *stablev1.AutoRestartPod,
+	pods []corev1.Pod) ([]corev1.Pod, error) {
+	log := logf.FromContext(ctx)
+
+	nodeMatches := make(map[string]bool)
+	matching := make([]corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		matches, ok := nodeMatches[pod.Spec.NodeName]
+		if !ok {
+			var err error
+			matches, err = r.nodeMatches(ctx, pod.Spec.NodeName, &obj.Spec)
+			if err != nil {
+				return nil, err
+			}
+			nodeMatches[pod.Spec.NodeName] = matches
+		}
+		if !matches {
+			log.Info("Skipping pod on a node not matching the node conditions or selector",
+				"pod", pod.Name, "node", pod.Spec.NodeName)
+			continue
+		}
+		matching = append(matching, pod)
+	}
+	return matching, nil
+}
+
+// nodeMatches reports whether the named node has one of spec.NodeConditions
+// and the labels of spec.NodeSelector. A pod that is not scheduled or whose
+// node is gone matches neither.
+func (r *AutoRestartPodReconciler) nodeMatches(ctx context.Context, nodeName string,
+	spec *stablev1.AutoRestartPodSpec) (bool, error) {
+	if nodeName == "" {
+		return false, nil
+	}
+	node := &corev1.Node{}
+	if err := r.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		logf.FromContext(ctx).Error(err, "Failed to get node", "node", nodeName)
+		return false, err
+	}
+	if len(spec.NodeConditions) > 0 && !hasNodeCondition(node, spec.NodeConditions) {
+		return false, nil
+	}
+	return labels.SelectorFromSet(spec.NodeSelector).Matches(labels.Set(node.Labels)), nil
+}
+
+// hasNodeCondition reports whether node has one of conditions with its status.
+func hasNodeCondition(node *corev1.Node, conditions []stablev1.NodeConditionMatch) bool {
+	for _, match := range conditions {
+		status := match.Status
+		if status == "" {
+			status = corev1.ConditionTrue
+		}
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == match.Type && condition.Status == status {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// setDiskPressure sets the DiskPressure condition of node to status.
+func setDiskPressure(node *corev1.Node, status corev1.ConditionStatus) {
+	node.Status.Conditions = []corev1.NodeCondition{
+		{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+		{Type: corev1.NodeDiskPressure, Status: status},
+	}
+}
+
+var _ = Describe("Node conditions", func() {
+	It("should only restart pods on nodes with the condition", func() {
+		ctx := context.Background()
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		obj := newTestAutoRestartPod("pressure", "*/5 * * * *")
+		obj.Spec.NodeConditions = []stablev1.NodeConditionMatch{{Type: corev1.NodeDiskPressure}}
+
+		nodeA := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}
+		nodeB := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-b"}}
+		setDiskPressure(nodeA, corev1.ConditionTrue)
+		setDiskPressure(nodeB, corev1.ConditionFalse)
+		podA, podB, unscheduled := newTestPod("nginx-a"), newTestPod("nginx-b"), newTestPod("nginx-pending")
+		podA.Spec.NodeName, podB.Spec.NodeName = nodeA.Name, nodeB.Name
+		c := newFakeClient(obj, nodeA, nodeB, podA, podB, unscheduled)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		By("restarting the pod on the node under disk pressure")
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, podA)).To(BeFalse())
+		Expect(podExists(c, podB)).To(BeTrue())
+		Expect(podExists(c, unscheduled)).To(BeTrue())
+
+		By("following the condition to the other node")
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(nodeB), nodeB)).To(Succeed())
+		setDiskPressure(nodeB, corev1.ConditionTrue)
+		Expect(c.Status().Update(ctx, nodeB)).To(Succeed())
+		fakeClock.SetTime(fakeClock.Now().Add(5 * time.Minute))
+
+		_, err = r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, podB)).To(BeFalse())
+		Expect(podExists(c, unscheduled)).To(BeTrue())
+	})
+
+	It("should match conditions by their status", func() {
+		node := &corev1.Node{}
+		setDiskPressure(node, corev1.ConditionFalse)
+
+		Expect(hasNodeCondition(node, []stablev1.NodeConditionMatch{{Type: corev1.NodeDiskPressure}})).To(BeFalse())
+		Expect(hasNodeCondition(node, []stablev1.NodeConditionMatch{
+			{Type: corev1.NodeDiskPressure, Status: corev1.ConditionFalse},
+		})).To(BeTrue())
+		Expect(hasNodeCondition(node, []stablev1.NodeConditionMatch{
+			{Type: "example.com/KernelDeadlock"},
+			{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+		})).To(BeTrue())
+	})
+
+	It("should not count pods from their metadata alone", func() {
+		obj := newTestAutoRestartPod("pressure", "*/5 * * * *")
+		Expect(exactPodCount(obj)).To(BeTrue())
+		obj.Spec.NodeConditions = []stablev1.NodeConditionMatch{{Type: corev1.NodeDiskPressure}}
+		Expect(exactPodCount(obj)).To(BeFalse())
+	})
+})
+
+var _ = Describe("Node selector", func() {
+	It("should only restart pods on the nodes of the selected pool", func() {
+		ctx := context.Background()
+		obj := newTestAutoRestartPod("pool", "*/5 * * * *")
+		obj.Spec.NodeSelector = map[string]string{"pool": "batch"}
+
+		batch := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-batch",
+			Labels: map[string]string{"pool": "batch", "zone": "a"}}}
+		web := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-web",
+			Labels: map[string]string{"pool": "web", "zone": "a"}}}
+		podBatch, podWeb, unscheduled := newTestPod("nginx-batch"), newTestPod("nginx-web"), newTestPod("nginx-pending")
+		podBatch.Spec.NodeName, podWeb.Spec.NodeName = batch.Name, web.Name
+		c := newFakeClient(obj, batch, web, podBatch, podWeb, unscheduled)
+		r := newTestReconciler(c, time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, podBatch)).To(BeFalse())
+		Expect(podExists(c, podWeb)).To(BeTrue())
+		Expect(podExists(c, unscheduled)).To(BeTrue())
+	})
+
+	It("should require the node conditions as well when both are set", func() {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-batch", Labels: map[string]string{"pool": "batch"}}}
+		setDiskPressure(node, corev1.ConditionFalse)
+		c := newFakeClient(node)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme()}
+		spec := &stablev1.AutoRestartPodSpec{NodeSelector: map[string]string{"pool": "batch"}}
+
+		Expect(r.nodeMatches(context.Background(), node.Name, spec)).To(BeTrue())
+		spec.NodeConditions = []stablev1.NodeConditionMatch{{Type: corev1.NodeDiskPressure}}
+		Expect(r.nodeMatches(context.Background(), node.Name, spec)).To(BeFalse())
+		Expect(exactPodCount(&stablev1.AutoRestartPod{Spec: *spec})).To(BeFalse())
+	})
+})
@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// RestartNotifier tells an external system, such as a chat or paging
+// webhook, about a restart that has been performed.
+type RestartNotifier interface {
+	Notify(ctx context.Context, url string, notification RestartNotification) error
+}
+
+// RestartNotification is the JSON body POSTed by the HTTP notifier.
+type RestartNotification struct {
+	Namespace     string    `json:"namespace"`
+	Name          string    `json:"name"`
+	Time          time.Time `json:"time"`
+	RestartedPods []string  `json:"restartedPods"`
+}
+
+// HTTPRestartNotifier POSTs a RestartNotification to the configured URL.
+type HTTPRestartNotifier struct {
+	Client *http.Client
+}
+
+// defaultNotifyTimeout bounds a notification. Notifications are best effort
+// and must not hold up the reconcile for long.
+const defaultNotifyTimeout = 5 * time.Second
+
+// Notify implements RestartNotifier.
+func (n *HTTPRestartNotifier) Notify(ctx context.Context, url string, notification RestartNotification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClientOrDefault(n.Client, defaultNotifyTimeout).Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if !successful(resp) {
+		return fmt.Errorf("notifying restart of %s/%s: unexpected status %s",
+			notification.Namespace, notification.Name, resp.Status)
+	}
+	return nil
+}
+
+// notify sends the restart of the restarted pods to Spec.NotificationWebhook.
+// Delivery failures are only logged.
+func (r *AutoRestartPodReconciler) notify(ctx context.Context, obj *stablev1.AutoRestartPod,
+	now time.Time, restarted []string) {
+	url := obj.Spec.NotificationWebhook
+	if url == "" || len(restarted) == 0 {
+		return
+	}
+
+	notifier := r.Notifier
+	if notifier == nil {
+		notifier = &HTTPRestartNotifier{}
+	}
+	if err := notifier.Notify(ctx, url, RestartNotification{
+		Namespace:     obj.Namespace,
+		Name:          obj.Name,
+		Time:          now,
+		RestartedPods: restarted,
+	}); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to deliver restart notification", "url", url)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Restart notifications", func() {
+	var (
+		obj    *stablev1.AutoRestartPod
+		c      client.Client
+		status int
+		bodies []map[string]any
+		server *httptest.Server
+		r      *AutoRestartPodReconciler
+	)
+
+	BeforeEach(func() {
+		status = http.StatusOK
+		bodies = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+			Expect(req.Method).To(Equal(http.MethodPost))
+			Expect(req.Header.Get("Content-Type")).To(Equal("application/json"))
+			var body map[string]any
+			Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+			bodies = append(bodies, body)
+			w.WriteHeader(status)
+		}))
+		DeferCleanup(server.Close)
+
+		obj = newTestAutoRestartPod("notified", "*/5 * * * *")
+		obj.Spec.NotificationWebhook = server.URL + "/hooks/restarts"
+		c = newFakeClient(obj, newTestPod("nginx-0"), newTestPod("nginx-1"))
+		r = newTestReconciler(c, time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		r.Notifier = &HTTPRestartNotifier{Client: server.Client()}
+	})
+
+	It("should post a summary of the restart", func() {
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(bodies).To(HaveLen(1))
+		Expect(bodies[0]).To(Equal(map[string]any{
+			"namespace":     "default",
+			"name":          "notified",
+			"time":          "2025-05-26T10:04:30Z",
+			"restartedPods": []any{"nginx-0", "nginx-1"},
+		}))
+	})
+
+	It("should not fail the reconcile when delivery fails", func() {
+		status = http.StatusInternalServerError
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bodies).To(HaveLen(1))
+		Expect(podExists(c, newTestPod("nginx-0"))).To(BeFalse())
+	})
+
+	It("should not notify when nothing was restarted", func() {
+		Expect(c.Delete(context.Background(), newTestPod("nginx-0"))).To(Succeed())
+		Expect(c.Delete(context.Background(), newTestPod("nginx-1"))).To(Succeed())
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bodies).To(BeEmpty())
+	})
+})
@@ -0,0 +1,59 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// podOwnerIndex indexes the pod cache by the UIDs of each pod's owners, so
+// the pods of a workload are looked up directly instead of being filtered
+// out of every pod in the namespace.
+const podOwnerIndex = ".metadata.ownerReferences.uid"
+
+// indexPodOwners returns the owner UIDs pod is indexed under in podOwnerIndex.
+func indexPodOwners(obj client.Object) []string {
+	refs := obj.GetOwnerReferences()
+	if len(refs) == 0 {
+		return nil
+	}
+	uids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		uids = append(uids, string(ref.UID))
+	}
+	return uids
+}
+
+// registerPodOwnerIndex adds podOwnerIndex to indexer.
+func registerPodOwnerIndex(ctx context.Context, indexer client.FieldIndexer) error {
+	return indexer.IndexField(ctx, &corev1.Pod{}, podOwnerIndex, indexPodOwners)
+}
+
+// ownedPods returns the pods in namespace owned by the object with uid,
+// answered by podOwnerIndex.
+func (r *AutoRestartPodReconciler) ownedPods(ctx context.Context, namespace string, uid types.UID) ([]corev1.Pod, error) {
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(namespace),
+		client.MatchingFields{podOwnerIndex: string(uid)}); err != nil {
+		return nil, err
+	}
+	return podList.Items, nil
+}
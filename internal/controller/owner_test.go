@@ -0,0 +1,66 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Pod owner index", func() {
+	// podNames returns the names of pods.
+	podNames := func(pods []corev1.Pod) []string {
+		names := make([]string, 0, len(pods))
+		for _, pod := range pods {
+			names = append(names, pod.Name)
+		}
+		return names
+	}
+
+	It("should look up the pods of a workload by its UID", func() {
+		sts := newTestStatefulSet("db")
+		other := newTestStatefulSet("cache")
+		objs := []client.Object{sts, other, newTestPod("nginx-bare")}
+		for i := range 3 {
+			objs = append(objs, newOwnedPod(fmt.Sprintf("db-%d", i), "db", sts, "StatefulSet"))
+		}
+		objs = append(objs, newOwnedPod("cache-0", "cache", other, "StatefulSet"))
+		c := newFakeClientBuilder(objs...).WithIndex(&corev1.Pod{}, podOwnerIndex, indexPodOwners).Build()
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme()}
+
+		pods, err := r.ownedPods(context.Background(), "default", sts.UID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podNames(pods)).To(ConsistOf("db-0", "db-1", "db-2"))
+
+		pods, err = r.ownedPods(context.Background(), "kube-system", sts.UID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pods).To(BeEmpty())
+	})
+
+	It("should index a pod under every owner", func() {
+		pod := newOwnedPod("db-0", "db", newTestStatefulSet("db"), "StatefulSet")
+		pod.OwnerReferences = append(pod.OwnerReferences, newOwnedPod("x", "x", newTestDeployment("web"),
+			"Deployment").OwnerReferences...)
+		Expect(indexPodOwners(pod)).To(Equal([]string{"sts-db", "deploy-web"}))
+		Expect(indexPodOwners(newTestPod("nginx-bare"))).To(BeEmpty())
+	})
+})
@@ -0,0 +1,139 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// resumedByAnnotation marks a paused Deployment that was resumed for a
+// rollout restart and has to be paused again once its controller has rolled
+// out the restart. The value is the namespace and name of the AutoRestartPod
+// that resumed it, as "namespace/name", since its pods may be in other
+// namespaces than it.
+const resumedByAnnotation = "autorestart.crazyfrank.com/resumed-by"
+
+// resumedBy returns the value of resumedByAnnotation for obj.
+func resumedBy(obj *stablev1.AutoRestartPod) string {
+	return obj.Namespace + "/" + obj.Name
+}
+
+// resumedByObj reports whether deploy was resumed by obj. Deployments
+// annotated before the namespace was recorded carry the bare name, which is
+// only trusted in the namespace of obj.
+func resumedByObj(deploy *appsv1.Deployment, obj *stablev1.AutoRestartPod) bool {
+	value := deploy.Annotations[resumedByAnnotation]
+	return value == resumedBy(obj) || (deploy.Namespace == obj.Namespace && value == obj.Name)
+}
+
+// errWorkloadPaused is returned for a paused Deployment that may not be resumed.
+var errWorkloadPaused = errors.New("workload is paused")
+
+// isPaused reports whether workload is a paused Deployment, which ignores
+// changes to its pod template until it is resumed.
+func isPaused(workload client.Object) bool {
+	deploy, ok := workload.(*appsv1.Deployment)
+	return ok && deploy.Spec.Paused
+}
+
+// setWorkloadPaused records in the WorkloadPaused condition which workloads
+// the last restart skipped because they are paused.
+func setWorkloadPaused(obj *stablev1.AutoRestartPod, paused []string) {
+	if len(paused) > 0 {
+		meta.SetStatusCondition(&obj.Status.Conditions, metav1.Condition{
+			Type:   stablev1.ConditionWorkloadPaused,
+			Status: metav1.ConditionTrue,
+			Reason: "Paused",
+			Message: fmt.Sprintf("skipped paused workloads %s; set resumePaused to restart them",
+				strings.Join(paused, ", ")),
+		})
+		return
+	}
+	if meta.FindStatusCondition(obj.Status.Conditions, stablev1.ConditionWorkloadPaused) != nil {
+		meta.SetStatusCondition(&obj.Status.Conditions, metav1.Condition{
+			Type:    stablev1.ConditionWorkloadPaused,
+			Status:  metav1.ConditionFalse,
+			Reason:  "NotPaused",
+			Message: "no workload was skipped",
+		})
+	}
+}
+
+// rolledOut reports whether deploy has rolled out its current template: its
+// controller has observed it and every replica is updated and available, with
+// no old replica left.
+func rolledOut(deploy *appsv1.Deployment) bool {
+	replicas := ptr.Deref(deploy.Spec.Replicas, 1)
+	status := deploy.Status
+	return status.ObservedGeneration >= deploy.Generation &&
+		status.UpdatedReplicas == replicas &&
+		status.AvailableReplicas == status.UpdatedReplicas &&
+		status.Replicas == status.UpdatedReplicas
+}
+
+// repauseWorkloads pauses the Deployments obj resumed for a rollout restart
+// again once they have rolled out the restarted template. It reports whether
+// any of them is still being rolled out.
+func (r *AutoRestartPodReconciler) repauseWorkloads(ctx context.Context, obj *stablev1.AutoRestartPod) (bool, error) {
+	log := logf.FromContext(ctx)
+
+	// Owners are resumed in the namespaces of their pods. One that cannot be
+	// listed is left for a later reconcile, like its pods.
+	var deploys []appsv1.Deployment
+	for _, namespace := range podNamespaces(obj) {
+		deployList := &appsv1.DeploymentList{}
+		if err := r.List(ctx, deployList, client.InNamespace(namespace)); err != nil {
+			log.Error(err, "Failed to list deployments", "namespace", namespace)
+			continue
+		}
+		deploys = append(deploys, deployList.Items...)
+	}
+
+	waiting := false
+	for i := range deploys {
+		deploy := &deploys[i]
+		if !resumedByObj(deploy, obj) {
+			continue
+		}
+		if !rolledOut(deploy) {
+			waiting = true
+			continue
+		}
+
+		patch := client.MergeFrom(deploy.DeepCopy())
+		deploy.Spec.Paused = true
+		delete(deploy.Annotations, resumedByAnnotation)
+		if err := r.Patch(ctx, deploy, patch); err != nil {
+			log.Error(err, "Failed to pause workload", "workload", workloadName(deploy))
+			return false, err
+		}
+		log.Info("Paused workload again after restart", "workload", workloadName(deploy))
+	}
+	return waiting, nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// applyPolicy fills every field obj leaves unset from the AutoRestartPolicy
+// named by Spec.PolicyRef. Only the in-memory copy is changed; the inherited
+// values are never written back to the object.
+func (r *AutoRestartPodReconciler) applyPolicy(ctx context.Context, obj *stablev1.AutoRestartPod) error {
+	if obj.Spec.PolicyRef == nil {
+		return nil
+	}
+	log := logf.FromContext(ctx)
+
+	policy := &stablev1.AutoRestartPolicy{}
+	if err := r.Get(ctx, types.NamespacedName{Name: obj.Spec.PolicyRef.Name}, policy); err != nil {
+		log.Error(err, "Failed to get AutoRestartPolicy", "policy", obj.Spec.PolicyRef.Name)
+		r.warn(ctx, obj, "PolicyNotFound", err.Error())
+		return fmt.Errorf("failed to get policy %s: %w", obj.Spec.PolicyRef.Name, err)
+	}
+	inheritPolicy(&obj.Spec, &policy.Spec)
+	return nil
+}
+
+// inheritPolicy copies the fields of policy into every field spec leaves
+// unset. The schedule is only inherited by objects without a weekly,
+// one-time or rule based schedule, and the restart budget only as a whole.
+func inheritPolicy(spec *stablev1.AutoRestartPodSpec, policy *stablev1.AutoRestartPolicySpec) {
+	if spec.Schedule == "" && len(spec.Weekly) == 0 && spec.At == nil && len(spec.Restarts) == 0 {
+		spec.Schedule = policy.Schedule
+	}
+	if spec.TimeZone == "" {
+		spec.TimeZone = policy.TimeZone
+	}
+	if spec.Strategy == "" {
+		spec.Strategy = policy.Strategy
+	}
+	if spec.RestartOrder == "" {
+		spec.RestartOrder = policy.RestartOrder
+	}
+	if spec.MinInterval == nil && policy.MinInterval != nil {
+		spec.MinInterval = policy.MinInterval.DeepCopy()
+	}
+	if spec.MaxRestartsPerWindow == nil && spec.Window == nil && policy.MaxRestartsPerWindow != nil && policy.Window != nil {
+		budget := *policy.MaxRestartsPerWindow
+		spec.MaxRestartsPerWindow = &budget
+		spec.Window = policy.Window.DeepCopy()
+	}
+	if len(spec.SkipDates) == 0 && len(policy.SkipDates) > 0 {
+		spec.SkipDates = append([]string(nil), policy.SkipDates...)
+	}
+}
+
+// policyToRequests returns a reconcile request for every AutoRestartPod
+// referencing the policy, so that they pick up changed defaults.
+func (r *AutoRestartPodReconciler) policyToRequests(ctx context.Context, policy client.Object) []reconcile.Request {
+	log := logf.FromContext(ctx)
+
+	list := &stablev1.AutoRestartPodList{}
+	if err := r.List(ctx, list); err != nil {
+		log.Error(err, "Failed to list AutoRestartPods for policy", "policy", policy.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range list.Items {
+		obj := &list.Items[i]
+		if obj.Spec.PolicyRef != nil && obj.Spec.PolicyRef.Name == policy.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+		}
+	}
+	return requests
+}
+
+// Status returns a writer for the status of objects. Every write is
+// recorded in an UpdateStatus span. Writing the status of an AutoRestartPod
+// keeps the spec it was called with, because the object read back from the
+// API server lacks the fields inherited by applyPolicy.
+func (r *AutoRestartPodReconciler) Status() client.SubResourceWriter {
+	return statusWriter{SubResourceWriter: r.Client.Status(), tracer: r.tracer()}
+}
+
+// statusWriter traces status writes and restores the spec of an
+// AutoRestartPod with a policyRef after each of them.
+type statusWriter struct {
+	client.SubResourceWriter
+	tracer trace.Tracer
+}
+
+func (w statusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) (err error) {
+	ctx, span := w.tracer.Start(ctx, "UpdateStatus",
+		trace.WithAttributes(objectKey.String(client.ObjectKeyFromObject(obj).String())))
+	defer func() { endSpan(span, err) }()
+	defer preserveSpec(obj)()
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+func (w statusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch,
+	opts ...client.SubResourcePatchOption) (err error) {
+	ctx, span := w.tracer.Start(ctx, "UpdateStatus",
+		trace.WithAttributes(objectKey.String(client.ObjectKeyFromObject(obj).String())))
+	defer func() { endSpan(span, err) }()
+	defer preserveSpec(obj)()
+	return w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+}
+
+// preserveSpec returns a func restoring the current spec of obj when obj is
+// an AutoRestartPod with a policyRef.
+func preserveSpec(obj client.Object) func() {
+	autorestartpod, ok := obj.(*stablev1.AutoRestartPod)
+	if !ok || autorestartpod.Spec.PolicyRef == nil {
+		return func() {}
+	}
+	spec := autorestartpod.Spec.DeepCopy()
+	return func() { autorestartpod.Spec = *spec }
+}
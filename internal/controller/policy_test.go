@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Policy inheritance", func() {
+	var (
+		ctx       context.Context
+		fakeClock *testingclock.FakePassiveClock
+		policy    *stablev1.AutoRestartPolicy
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fakeClock = testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		policy = &stablev1.AutoRestartPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly"},
+			Spec: stablev1.AutoRestartPolicySpec{
+				// Due within the restart window
+				Schedule:    "5 10 * * *",
+				TimeZone:    "UTC",
+				Strategy:    stablev1.DeleteStrategy,
+				MinInterval: &metav1.Duration{Duration: time.Hour},
+			},
+		}
+	})
+
+	It("should restart on the schedule inherited from the policy", func() {
+		obj := newTestAutoRestartPod("inherits", "")
+		obj.Spec.PolicyRef = &stablev1.PolicyReference{Name: "nightly"}
+		pod := newTestPod("nginx-0")
+		c := newFakeClient(obj, pod, policy)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, pod)).To(BeFalse())
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.LastRestartTime).NotTo(BeNil())
+		// The inherited defaults are never written to the object
+		Expect(updated.Spec.Schedule).To(BeEmpty())
+		Expect(updated.Spec.MinInterval).To(BeNil())
+	})
+
+	It("should prefer the object's own schedule over the policy's", func() {
+		obj := newTestAutoRestartPod("overrides", "0 3 * * *")
+		obj.Spec.PolicyRef = &stablev1.PolicyReference{Name: "nightly"}
+		pod := newTestPod("nginx-0")
+		c := newFakeClient(obj, pod, policy)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, pod)).To(BeTrue())
+	})
+
+	It("should fail while the referenced policy is missing", func() {
+		obj := newTestAutoRestartPod("missing", "")
+		obj.Spec.PolicyRef = &stablev1.PolicyReference{Name: "nightly"}
+		pod := newTestPod("nginx-0")
+		c := newFakeClient(obj, pod)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).To(MatchError(ContainSubstring("failed to get policy nightly")))
+		Expect(podExists(c, pod)).To(BeTrue())
+	})
+
+	It("should only fill fields the object leaves unset", func() {
+		policy.Spec.RestartOrder = stablev1.NewestFirst
+		policy.Spec.MaxRestartsPerWindow = ptr.To[int32](2)
+		policy.Spec.Window = &metav1.Duration{Duration: time.Hour}
+		policy.Spec.SkipDates = []string{"2025-12-24"}
+		spec := stablev1.AutoRestartPodSpec{
+			Strategy:    stablev1.RolloutRestartStrategy,
+			MinInterval: &metav1.Duration{Duration: time.Minute},
+			Window:      &metav1.Duration{Duration: 2 * time.Hour},
+			Restarts:    []stablev1.RestartRule{{Name: "web", Schedule: "0 3 * * *"}},
+		}
+
+		inheritPolicy(&spec, &policy.Spec)
+
+		Expect(spec.Schedule).To(BeEmpty(), "rules replace the inherited schedule")
+		Expect(spec.TimeZone).To(Equal("UTC"))
+		Expect(spec.Strategy).To(Equal(stablev1.RolloutRestartStrategy))
+		Expect(spec.RestartOrder).To(Equal(stablev1.NewestFirst))
+		Expect(spec.MinInterval.Duration).To(Equal(time.Minute))
+		// The budget is only inherited as a whole
+		Expect(spec.MaxRestartsPerWindow).To(BeNil())
+		Expect(spec.Window.Duration).To(Equal(2 * time.Hour))
+		Expect(spec.SkipDates).To(Equal([]string{"2025-12-24"}))
+	})
+
+	It("should keep the inherited spec across status writes", func() {
+		obj := newTestAutoRestartPod("inherits", "")
+		obj.Spec.PolicyRef = &stablev1.PolicyReference{Name: "nightly"}
+		c := newFakeClient(obj, policy)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		Expect(r.applyPolicy(ctx, obj)).To(Succeed())
+		obj.Status.LastScheduleTime = &metav1.Time{Time: fakeClock.Now()}
+		Expect(r.Status().Update(ctx, obj)).To(Succeed())
+		Expect(obj.Spec.Schedule).To(Equal("5 10 * * *"))
+		Expect(obj.Spec.MinInterval.Duration).To(Equal(time.Hour))
+	})
+
+	It("should map a policy to the objects referencing it", func() {
+		referencing := newTestAutoRestartPod("referencing", "")
+		referencing.Spec.PolicyRef = &stablev1.PolicyReference{Name: "nightly"}
+		other := newTestAutoRestartPod("other", "0 3 * * *")
+		other.Namespace = "other"
+		other.Spec.PolicyRef = &stablev1.PolicyReference{Name: "weekly"}
+		unrelated := newTestAutoRestartPod("unrelated", "0 3 * * *")
+		c := newFakeClient(referencing, other, unrelated, policy)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme()}
+
+		Expect(r.policyToRequests(ctx, policy)).To(ConsistOf(requestFor(referencing)))
+	})
+})
@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"math"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// preStopDurationAnnotation tells how long the preStop hooks of a pod are
+// expected to run (e.g. "45s").
+const preStopDurationAnnotation = "autorestart.crazyfrank.com/prestop-duration"
+
+// preStopGracePeriod returns the grace period in seconds pod has to be
+// deleted with so that its preStop hooks can finish, or nil when grace
+// already leaves them enough time. grace is the overriding grace period,
+// the pod's own applies when it is nil.
+func preStopGracePeriod(ctx context.Context, pod *corev1.Pod, grace *int64) *int64 {
+	value, ok := pod.Annotations[preStopDurationAnnotation]
+	if !ok || !hasPreStopHook(pod) {
+		return nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		logf.FromContext(ctx).Error(err, "Ignoring invalid pod annotation",
+			"pod", pod.Name, "annotation", preStopDurationAnnotation, "value", value)
+		return nil
+	}
+
+	current := int64(corev1.DefaultTerminationGracePeriodSeconds)
+	switch {
+	case grace != nil:
+		current = *grace
+	case pod.Spec.TerminationGracePeriodSeconds != nil:
+		current = *pod.Spec.TerminationGracePeriodSeconds
+	}
+	// Round up so the hook is never cut short by a fraction of a second
+	needed := int64(math.Ceil(duration.Seconds()))
+	if needed <= current {
+		return nil
+	}
+	return &needed
+}
+
+// hasPreStopHook reports whether a container of pod defines a preStop hook.
+func hasPreStopHook(pod *corev1.Pod) bool {
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, container := range containers {
+			if container.Lifecycle != nil && container.Lifecycle.PreStop != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// awaitingTermination reports whether a pod matched by selector is still
+// terminating at now within its grace period. The deletion timestamp of a
+// terminating pod is when its grace period ends.
+func (r *AutoRestartPodReconciler) awaitingTermination(ctx context.Context, obj *stablev1.AutoRestartPod,
+	selector labels.Selector, now time.Time) (bool, error) {
+	pods, err := r.listPods(ctx, obj, client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list pods", "selector", selector.String())
+		return false, err
+	}
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil && now.Before(pod.DeletionTimestamp.Time) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
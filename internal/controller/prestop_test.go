@@ -0,0 +1,167 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newPreStopPod returns a pod with a preStop hook expected to run for duration.
+func newPreStopPod(name, duration string) *corev1.Pod {
+	pod := newTestPod(name)
+	pod.Annotations = map[string]string{preStopDurationAnnotation: duration}
+	pod.Spec.Containers = []corev1.Container{{
+		Name:  "nginx",
+		Image: "nginx",
+		Lifecycle: &corev1.Lifecycle{
+			PreStop: &corev1.LifecycleHandler{Sleep: &corev1.SleepAction{Seconds: 40}},
+		},
+	}}
+	return pod
+}
+
+var _ = Describe("PreStop hooks", func() {
+	ctx := context.Background()
+
+	It("should extend the grace period to the hook duration", func() {
+		pod := newPreStopPod("nginx", "45.5s")
+		Expect(preStopGracePeriod(ctx, pod, nil)).To(HaveValue(Equal(int64(46))))
+		Expect(preStopGracePeriod(ctx, pod, ptr.To[int64](10))).To(HaveValue(Equal(int64(46))))
+		Expect(preStopGracePeriod(ctx, pod, ptr.To[int64](60))).To(BeNil())
+
+		pod.Spec.TerminationGracePeriodSeconds = ptr.To[int64](90)
+		Expect(preStopGracePeriod(ctx, pod, nil)).To(BeNil())
+	})
+
+	It("should compare against the default grace period", func() {
+		Expect(preStopGracePeriod(ctx, newPreStopPod("nginx", "20s"), nil)).To(BeNil())
+	})
+
+	It("should ignore pods without a hook or with an invalid duration", func() {
+		pod := newPreStopPod("nginx", "45s")
+		pod.Spec.Containers[0].Lifecycle = nil
+		Expect(preStopGracePeriod(ctx, pod, nil)).To(BeNil())
+
+		Expect(preStopGracePeriod(ctx, newPreStopPod("nginx", "soon"), nil)).To(BeNil())
+		Expect(preStopGracePeriod(ctx, newTestPod("nginx"), nil)).To(BeNil())
+	})
+
+	It("should delete every pod with the grace period its hooks need", func() {
+		obj := newTestAutoRestartPod("prestop", "*/5 * * * *")
+		obj.Spec.GracePeriodSeconds = ptr.To[int64](10)
+		grace := map[string]*int64{}
+		c := newFakeClientBuilder(obj, newPreStopPod("nginx-slow", "2m"), newPreStopPod("nginx-fast", "5s"),
+			newTestPod("nginx-plain")).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(ctx context.Context, c client.WithWatch, o client.Object, opts ...client.DeleteOption) error {
+					options := client.DeleteOptions{}
+					options.ApplyOptions(opts)
+					grace[o.GetName()] = options.GracePeriodSeconds
+					return c.Delete(ctx, o, opts...)
+				},
+			}).Build()
+		r := newTestReconciler(c, time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(grace).To(HaveLen(3))
+		Expect(grace["nginx-slow"]).To(HaveValue(Equal(int64(120))))
+		Expect(grace["nginx-fast"]).To(HaveValue(Equal(int64(10))))
+		Expect(grace["nginx-plain"]).To(HaveValue(Equal(int64(10))))
+	})
+
+	Context("when waiting for preStop hooks", func() {
+		var now time.Time
+
+		BeforeEach(func() {
+			now = time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC)
+		})
+
+		// terminating returns the names of the pods being deleted
+		terminating := func(c client.Client) []string {
+			podList := &corev1.PodList{}
+			Expect(c.List(ctx, podList, client.InNamespace("default"))).To(Succeed())
+			var names []string
+			for _, pod := range podList.Items {
+				if pod.DeletionTimestamp != nil {
+					names = append(names, pod.Name)
+				}
+			}
+			return names
+		}
+
+		It("should delete the pods one at a time once the previous one terminated", func() {
+			obj := newTestAutoRestartPod("serial", "*/5 * * * *")
+			obj.Spec.WaitForPreStop = true
+			pods := newAgedPods(3, now.Add(-time.Hour))
+			for _, pod := range pods {
+				// The finalizer keeps a deleted pod terminating
+				pod.SetFinalizers([]string{"example.com/hold"})
+			}
+			c := newFakeClient(append(pods, obj)...)
+			fakeClock := testingclock.NewFakePassiveClock(now)
+			r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+			result, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(runRequeueInterval))
+			Expect(terminating(c)).To(ConsistOf("nginx-0"))
+
+			// The next pod waits while the first one is terminating
+			fakeClock.SetTime(now.Add(runRequeueInterval))
+			_, err = r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(terminating(c)).To(ConsistOf("nginx-0"))
+
+			first := &corev1.Pod{}
+			Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "nginx-0"}, first)).To(Succeed())
+			first.Finalizers = nil
+			Expect(c.Update(ctx, first)).To(Succeed())
+
+			fakeClock.SetTime(now.Add(2 * runRequeueInterval))
+			_, err = r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remainingPods(c)).To(ConsistOf("nginx-1", "nginx-2"))
+			Expect(terminating(c)).To(ConsistOf("nginx-1"))
+		})
+
+		It("should not wait for a pod past its grace period", func() {
+			obj := newTestAutoRestartPod("serial", "*/5 * * * *")
+			obj.Spec.WaitForPreStop = true
+			stuck := newTestPod("nginx-stuck")
+			stuck.Finalizers = []string{"example.com/hold"}
+			stuck.DeletionTimestamp = &metav1.Time{Time: now.Add(-time.Second)}
+			c := newFakeClient(obj, stuck, newTestPod("nginx-0"), newTestPod("nginx-1"))
+			r := newTestReconciler(c, now)
+
+			_, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remainingPods(c)).To(HaveLen(2))
+			Expect(remainingPods(c)).To(ContainElement("nginx-stuck"))
+		})
+	})
+})
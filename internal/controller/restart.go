@@ -0,0 +1,768 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+	"github.com/crazyfrankie/autorestart-operator/internal/audit"
+)
+
+// runRequeueInterval is how long the controller waits between the passes of
+// a restart that could not restart every pod at once.
+const runRequeueInterval = 30 * time.Second
+
+// restart performs one pass of the restart scheduled for scheduledTime and
+// records the outcome in status. It reports pending when pods are left for a
+// following pass or their replacements are awaited, in which case
+// Status.ActiveRun is kept. A run of an entry of Spec.Restarts is continued
+// with the pods of the entry.
+func (r *AutoRestartPodReconciler) restart(ctx context.Context, obj *stablev1.AutoRestartPod,
+	now, scheduledTime time.Time) (pending bool, err error) {
+	if run := obj.Status.ActiveRun; run != nil && run.Rule != "" {
+		rule := findRule(obj, run.Rule)
+		if rule == nil {
+			return false, r.dropRuleRun(ctx, obj)
+		}
+		return r.restartRule(ctx, obj, rule, now, scheduledTime)
+	}
+
+	// Resolve the selector either from the spec or from the referenced workload
+	target, selector, err := r.podSelector(ctx, obj)
+	if err != nil {
+		return false, err
+	}
+	return r.restartPods(ctx, obj, restartPass{target: target, selector: selector}, now, scheduledTime)
+}
+
+// restartPass is what the passes of a restart restart: the pods of the
+// top-level selector or targetRef of obj, or those of an entry of
+// Spec.Restarts.
+type restartPass struct {
+	// target is the workload of Spec.TargetRef, if any.
+	target client.Object
+	// selector selects the pods.
+	selector labels.Selector
+	// rule is the entry of Spec.Restarts restarted, nil for the top level.
+	rule *stablev1.RestartRule
+}
+
+// ruleName returns the name of the rule restarted, empty for the top level.
+func (p restartPass) ruleName() string {
+	if p.rule == nil {
+		return ""
+	}
+	return p.rule.Name
+}
+
+// describe prefixes message with the rule restarted, if any.
+func (p restartPass) describe(message string) string {
+	if p.rule == nil {
+		return message
+	}
+	return fmt.Sprintf("rule %s %s", p.rule.Name, message)
+}
+
+// restartPods performs one pass of the restart of pass scheduled for
+// scheduledTime, see restart. Every guard applies to the pods of rules as
+// much as to those of the top level.
+func (r *AutoRestartPodReconciler) restartPods(ctx context.Context, obj *stablev1.AutoRestartPod,
+	pass restartPass, now, scheduledTime time.Time) (pending bool, err error) {
+	log := logf.FromContext(ctx)
+	if pass.rule != nil {
+		log = log.WithValues("rule", pass.rule.Name)
+	}
+	target, selector := pass.target, pass.selector
+
+	runStart := now
+	if obj.Status.ActiveRun != nil {
+		runStart = obj.Status.ActiveRun.StartTime.Time
+	}
+
+	// While the circuit breaker is open the restart is kept as an active run
+	// and picked up again once the failures have subsided
+	if r.Breaker != nil {
+		if r.Breaker.Open(now) {
+			log.Info("Circuit breaker is open, deferring restart")
+			meta.SetStatusCondition(&obj.Status.Conditions, metav1.Condition{
+				Type:    stablev1.ConditionCircuitOpen,
+				Status:  metav1.ConditionTrue,
+				Reason:  "TooManyFailures",
+				Message: "restarts are paused after a burst of failed restarts",
+			})
+			return true, r.deferRun(ctx, obj, pass.ruleName(), runStart, scheduledTime)
+		}
+		if meta.FindStatusCondition(obj.Status.Conditions, stablev1.ConditionCircuitOpen) != nil {
+			meta.SetStatusCondition(&obj.Status.Conditions, metav1.Condition{
+				Type:    stablev1.ConditionCircuitOpen,
+				Status:  metav1.ConditionFalse,
+				Reason:  "FailuresSubsided",
+				Message: "restarts are allowed",
+			})
+		}
+	}
+
+	// Likewise while the SLA metric is above its threshold
+	reason, message := r.slaDeferral(ctx, obj)
+	setSLADeferred(obj, reason, message)
+	if reason != "" {
+		log.Info("SLA gate is closed, deferring restart", "reason", message)
+		return true, r.deferRun(ctx, obj, pass.ruleName(), runStart, scheduledTime)
+	}
+
+	strategy := obj.Spec.Strategy
+	limit := obj.Spec.MaxPodsPerRestart
+	refuseOverflow := limit != nil && strategy != stablev1.RolloutRestartStrategy &&
+		obj.Spec.OverflowPolicy == stablev1.RefuseOverflow
+
+	// An oversized restart can be refused from the pod metadata alone,
+	// before the full pods are fetched
+	if refuseOverflow && exactPodCount(obj) {
+		count, terminating, err := r.countCandidatePods(ctx, obj, selector, now, runStart)
+		if err != nil {
+			return false, err
+		}
+		if count > int(*limit) {
+			obj.Status.TerminatingPods = int32(terminating)
+			return false, r.refuseRestart(ctx, obj, count)
+		}
+	}
+
+	// Get all pods that match the selector and may be restarted now
+	pods, terminating, err := r.candidatePods(ctx, obj, selector, now)
+	if err != nil {
+		return false, err
+	}
+	obj.Status.TerminatingPods = int32(terminating)
+	// Pods created since the run started are replacements of restarted pods.
+	// The order decides which pods go first when a pass is capped.
+	pods = createdBefore(pods, runStart)
+	orderPods(pods, &obj.Spec)
+
+	// Only restart a random sample of RestartSampleSize of the pods
+	if obj.Spec.RestartSampleSize != nil && strategy != stablev1.RolloutRestartStrategy {
+		restartedSoFar := 0
+		if obj.Status.ActiveRun != nil {
+			restartedSoFar = int(obj.Status.ActiveRun.RestartedPods)
+		}
+		pods = samplePods(obj, pods, int(*obj.Spec.RestartSampleSize)-restartedSoFar, scheduledTime)
+	}
+
+	// Restart one failure domain at a time, at most MaxUnavailablePerDomain
+	// of its pods per pass
+	if key := obj.Spec.TopologyKey; key != "" && strategy != stablev1.RolloutRestartStrategy {
+		batch, more, err := r.domainBatch(ctx, pods, key)
+		if err != nil {
+			return false, err
+		}
+		if limit := obj.Spec.MaxUnavailablePerDomain; limit != nil && len(batch) > int(*limit) {
+			batch = batch[:*limit]
+			more = true
+		}
+		pods = batch
+		pending = more
+	}
+
+	// Restart the pods of a DaemonSet on a few nodes at a time
+	if strategy != stablev1.RolloutRestartStrategy {
+		batch, more, err := r.daemonSetBatch(ctx, obj, pods)
+		if err != nil {
+			return false, err
+		}
+		pods = batch
+		pending = pending || more
+	}
+
+	// Only restart the share of RestartFraction of the pods on every fire
+	fraction := obj.Spec.RestartFraction != nil && strategy != stablev1.RolloutRestartStrategy
+	if fraction {
+		restartedSoFar := 0
+		if obj.Status.ActiveRun != nil {
+			restartedSoFar = int(obj.Status.ActiveRun.RestartedPods)
+		}
+		if pods, err = fractionBatch(obj, pods, restartedSoFar); err != nil {
+			r.warn(ctx, obj, "InvalidRestartFraction", err.Error())
+			return false, err
+		}
+	}
+
+	// Never delete more pods in one pass than MaxPodsPerRestart allows
+	if limit != nil && strategy != stablev1.RolloutRestartStrategy {
+		if len(pods) > int(*limit) {
+			if refuseOverflow {
+				return false, r.refuseRestart(ctx, obj, len(pods))
+			}
+
+			pods = pods[:*limit]
+			pending = true
+		}
+		meta.SetStatusCondition(&obj.Status.Conditions, metav1.Condition{
+			Type:    stablev1.ConditionPodLimitExceeded,
+			Status:  metav1.ConditionFalse,
+			Reason:  "WithinLimit",
+			Message: fmt.Sprintf("restarting %d pods", len(pods)),
+		})
+	}
+
+	// Delete one pod at a time, each once the previous one has terminated
+	if obj.Spec.WaitForPreStop && strategy != stablev1.RolloutRestartStrategy && !obj.Spec.DryRun {
+		waiting, err := r.awaitingTermination(ctx, obj, selector, now)
+		if err != nil {
+			return false, err
+		}
+		if waiting {
+			log.Info("Waiting for terminating pods before deleting the next one")
+			return true, r.deferRun(ctx, obj, pass.ruleName(), runStart, scheduledTime)
+		}
+		if len(pods) > 1 {
+			pods = pods[:1]
+			pending = true
+		}
+	}
+
+	// A dry run stops short of restarting the pods
+	if obj.Spec.DryRun {
+		return false, r.dryRun(ctx, obj, pass, pods, now, scheduledTime)
+	}
+
+	var results podResults
+	var paused []string
+	switch {
+	case strategy == stablev1.RolloutRestartStrategy && target != nil:
+		// The referenced workload is restarted directly
+		name := workloadName(target)
+		if err := r.rolloutRestart(ctx, obj, target, now); errors.Is(err, errWorkloadPaused) {
+			log.Info("Skipping paused workload", "workload", name)
+			paused = append(paused, name)
+		} else if err != nil {
+			log.Error(err, "Failed to rollout restart workload", "workload", name)
+			r.recordFailure()
+			results.add(name, err)
+		} else {
+			log.Info("Restarted workload", "workload", name)
+			results.add(name, nil)
+			r.receipt(target, obj, "rollout restarted")
+		}
+	case strategy == stablev1.RolloutRestartStrategy:
+		results, paused = r.rolloutRestartOwners(ctx, obj, pods, now)
+	default:
+		// Pods are deregistered in one pass and deleted in a later one, once
+		// their drain period has passed, rather than waited on here
+		if period := drainPeriod(obj); period > 0 {
+			var draining bool
+			pods, results, draining = r.drainPods(ctx, obj, pods, now, period)
+			if draining && len(pods) == 0 && len(results) == 0 {
+				log.Info("Waiting for pods to drain", "drainPeriod", period.String())
+				return true, r.deferRun(ctx, obj, pass.ruleName(), runStart, scheduledTime)
+			}
+			pending = pending || draining
+		}
+		results = append(results, r.deletePods(ctx, obj, pods)...)
+	}
+	// A pass cut short by the manager shutting down is kept as the active
+	// run, so that a manager picks up the pods left; its progress is still
+	// recorded
+	if ctx.Err() != nil {
+		log.Info("Manager is shutting down, stopping the restart", "restarted", len(results.names(true)))
+		ctx = context.WithoutCancel(ctx)
+		pending = true
+	}
+	restarted, failed := results.names(true), results.names(false)
+	recordRestartResults(obj, results, obj.Status.ActiveRun != nil)
+	setWorkloadPaused(obj, paused)
+	if len(restarted) > 0 {
+		obj.Status.TargetKinds = restartedKinds(restarted)
+		obj.Status.LastRestartStrategy = restartedStrategy(obj, restarted)
+	}
+	if fraction {
+		recordFractionRestarts(obj, restarted)
+	}
+
+	// Keep an audit trail of the restart; a failing store must not block restarts
+	if r.Audit != nil {
+		schedule, timeZone := effectiveSchedule(obj), obj.Spec.TimeZone
+		if pass.rule != nil {
+			schedule, timeZone = pass.rule.Schedule, pass.rule.TimeZone
+		}
+		if err := r.Audit.Record(ctx, audit.Record{
+			Time:          now,
+			Namespace:     obj.Namespace,
+			Name:          obj.Name,
+			Schedule:      schedule,
+			TimeZone:      timeZone,
+			Selector:      selector.String(),
+			ScheduledTime: scheduledTime,
+			RestartedPods: restarted,
+			FailedPods:    failed,
+		}); err != nil {
+			log.Error(err, "Failed to record restart in audit store")
+		}
+	}
+
+	if len(restarted) > 0 {
+		r.event(ctx, obj, corev1.EventTypeNormal, "Restarted",
+			pass.describe(fmt.Sprintf("restarted %d pods", len(restarted))))
+	}
+	if len(failed) > 0 {
+		r.warn(ctx, obj, "RestartFailed", pass.describe(fmt.Sprintf("failed to restart %d pods", len(failed))))
+	}
+
+	// Push the restart to the configured webhook; delivery is best effort
+	r.notify(ctx, obj, now, restarted)
+
+	// Update the LastRestartTime status field to record this restart event.
+	// The restarts of a rule are recorded in its own status, they must not
+	// count as restarts of the top-level schedule.
+	if pass.rule != nil {
+		setRuleRestarted(obj, pass.rule.Name, now)
+	} else {
+		obj.Status.LastRestartTime = &metav1.Time{Time: now}
+		obj.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+		lag := now.Sub(scheduledTime)
+		obj.Status.LastScheduleLag = &metav1.Duration{Duration: lag}
+		restartLag.WithLabelValues(obj.Namespace, obj.Name).Set(lag.Seconds())
+	}
+	if obj.Status.Initialized == nil {
+		obj.Status.Initialized = &metav1.Time{Time: now}
+	}
+
+	restartedPods := int32(len(restarted))
+	if obj.Status.ActiveRun != nil {
+		restartedPods += obj.Status.ActiveRun.RestartedPods
+	}
+	// Deleted pods may have to be replaced before the run counts as done
+	awaiting := !pending && obj.Spec.ReplacementWait != nil &&
+		strategy != stablev1.RolloutRestartStrategy && restartedPods > 0
+	if pending || awaiting {
+		obj.Status.ActiveRun = &stablev1.RestartRun{
+			StartTime:            metav1.Time{Time: runStart},
+			ScheduledTime:        metav1.Time{Time: scheduledTime},
+			RestartedPods:        restartedPods,
+			AwaitingReplacements: awaiting,
+			Rule:                 pass.ruleName(),
+		}
+	} else {
+		obj.Status.ActiveRun = nil
+		if meta.FindStatusCondition(obj.Status.Conditions, stablev1.ConditionRunTimedOut) != nil {
+			setCondition(obj, stablev1.ConditionRunTimedOut, metav1.ConditionFalse, "RunFinished",
+				"the last restart finished in time")
+		}
+		if restartedPods > 0 {
+			// Nothing tracks the replacements without a replacement wait
+			setCondition(obj, stablev1.ConditionAvailable, metav1.ConditionUnknown, "NotAwaited",
+				"replacements are only tracked when replacementWait is set")
+		}
+	}
+	setRunConditions(obj)
+	recordPassOutcome(obj, len(restarted), len(failed))
+	if err := r.Status().Update(ctx, obj); err != nil {
+		log.Error(err, "Failed to update AutoRestartPod status")
+		return false, err
+	}
+	return pending || awaiting, nil
+}
+
+// deferRun keeps the restart scheduled for scheduledTime as the active run
+// without restarting anything in this pass. rule names the entry of
+// Spec.Restarts restarted, empty for the top level.
+func (r *AutoRestartPodReconciler) deferRun(ctx context.Context, obj *stablev1.AutoRestartPod,
+	rule string, runStart, scheduledTime time.Time) error {
+	if obj.Status.ActiveRun == nil {
+		obj.Status.ActiveRun = &stablev1.RestartRun{
+			StartTime:     metav1.Time{Time: runStart},
+			ScheduledTime: metav1.Time{Time: scheduledTime},
+			Rule:          rule,
+		}
+		// The results of the previous restart are superseded by this one
+		obj.Status.LastRestartResults = nil
+	}
+	setRunConditions(obj)
+	if err := r.Status().Update(ctx, obj); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to update AutoRestartPod status")
+		return err
+	}
+	return nil
+}
+
+// claimRun persists the restart scheduled for scheduledTime as the active
+// run before its first pass restarts anything. Only the leader reconciles,
+// and the status it persisted is all a new leader goes by: after a failover
+// it continues the claimed run instead of firing it again, and a leader
+// acting on a stale copy of obj fails the claim with a conflict.
+func (r *AutoRestartPodReconciler) claimRun(ctx context.Context, obj *stablev1.AutoRestartPod,
+	now, scheduledTime time.Time) error {
+	return r.deferRun(ctx, obj, "", now, scheduledTime)
+}
+
+// refuseRestart skips a restart of count pods that exceeds
+// Spec.MaxPodsPerRestart and reports it in the PodLimitExceeded condition.
+func (r *AutoRestartPodReconciler) refuseRestart(ctx context.Context, obj *stablev1.AutoRestartPod, count int) error {
+	log := logf.FromContext(ctx)
+
+	message := fmt.Sprintf("%d pods match, more than maxPodsPerRestart %d", count, *obj.Spec.MaxPodsPerRestart)
+	log.Info("Refusing restart", "reason", message)
+	// A claimed run that restarted nothing yet is given up
+	if run := obj.Status.ActiveRun; run != nil && run.RestartedPods == 0 {
+		obj.Status.ActiveRun = nil
+		setRunConditions(obj)
+	}
+	meta.SetStatusCondition(&obj.Status.Conditions, metav1.Condition{
+		Type:    stablev1.ConditionPodLimitExceeded,
+		Status:  metav1.ConditionTrue,
+		Reason:  "TooManyPods",
+		Message: message,
+	})
+	if err := r.Status().Update(ctx, obj); err != nil {
+		log.Error(err, "Failed to update AutoRestartPod status")
+		return err
+	}
+	return nil
+}
+
+// podSelector returns the selector of the pods obj restarts, along with the
+// referenced workload when Spec.TargetRef is set.
+func (r *AutoRestartPodReconciler) podSelector(ctx context.Context,
+	obj *stablev1.AutoRestartPod) (client.Object, labels.Selector, error) {
+	if obj.Spec.TargetRef == nil {
+		// A selector that does not parse must not be mistaken for one that
+		// selects every pod
+		selector, err := metav1.LabelSelectorAsSelector(obj.Spec.Selector)
+		if err != nil {
+			logf.FromContext(ctx).Error(err, "Invalid pod selector")
+			r.warn(ctx, obj, "InvalidSelector", err.Error())
+			return nil, nil, fmt.Errorf("invalid selector: %w", err)
+		}
+		return nil, selector, nil
+	}
+
+	target, selector, err := r.resolveTargetRef(ctx, obj)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to resolve target workload",
+			"kind", obj.Spec.TargetRef.Kind, "name", obj.Spec.TargetRef.Name)
+		return nil, nil, err
+	}
+	return target, selector, nil
+}
+
+// runSelector returns the selector of the pods Status.ActiveRun restarts:
+// those of its entry of Spec.Restarts, or those of the top level.
+func (r *AutoRestartPodReconciler) runSelector(ctx context.Context, obj *stablev1.AutoRestartPod) (labels.Selector, error) {
+	if run := obj.Status.ActiveRun; run != nil && run.Rule != "" {
+		if rule := findRule(obj, run.Rule); rule != nil {
+			return r.ruleSelector(ctx, obj, rule)
+		}
+	}
+	_, selector, err := r.podSelector(ctx, obj)
+	return selector, err
+}
+
+// runTimedOut reports whether Status.ActiveRun is still restarting pods
+// after Spec.RunTimeout.
+func runTimedOut(obj *stablev1.AutoRestartPod, now time.Time) bool {
+	run := obj.Status.ActiveRun
+	if run == nil || run.AwaitingReplacements || obj.Spec.RunTimeout == nil {
+		return false
+	}
+	return !now.Before(run.StartTime.Add(obj.Spec.RunTimeout.Duration))
+}
+
+// stopTimedOutRun gives up on Status.ActiveRun once it ran out of
+// Spec.RunTimeout, leaving the pods it did not get to for the next run.
+func (r *AutoRestartPodReconciler) stopTimedOutRun(ctx context.Context, obj *stablev1.AutoRestartPod,
+	now time.Time) error {
+	log := logf.FromContext(ctx)
+	run := obj.Status.ActiveRun
+
+	message := fmt.Sprintf("restart scheduled at %s did not finish within %s, stopped after restarting %d pods",
+		run.ScheduledTime.UTC().Format(time.RFC3339), obj.Spec.RunTimeout.Duration, run.RestartedPods)
+	log.Info("Stopping timed out restart", "scheduledTime", run.ScheduledTime.Format(time.RFC3339),
+		"runTimeout", obj.Spec.RunTimeout.Duration.String(), "restarted", run.RestartedPods,
+		"elapsed", now.Sub(run.StartTime.Time).String())
+	r.warn(ctx, obj, "RunTimedOut", message)
+
+	setCondition(obj, stablev1.ConditionRunTimedOut, metav1.ConditionTrue, "RunTimedOut", message)
+	obj.Status.ActiveRun = nil
+	setRunConditions(obj)
+	if err := r.Status().Update(ctx, obj); err != nil {
+		log.Error(err, "Failed to update AutoRestartPod status")
+		return err
+	}
+	return nil
+}
+
+// awaitReplacements checks whether the pods deleted by the active run have
+// been replaced, or Spec.ReplacementWait has run out, and finishes the run
+// if so. Otherwise it returns how long to wait before checking again; pod
+// events usually wake the controller up earlier.
+func (r *AutoRestartPodReconciler) awaitReplacements(ctx context.Context, obj *stablev1.AutoRestartPod,
+	now time.Time) (time.Duration, error) {
+	log := logf.FromContext(ctx)
+	run := obj.Status.ActiveRun
+
+	selector, err := r.runSelector(ctx, obj)
+	if err != nil {
+		return 0, err
+	}
+	pods, err := r.listPods(ctx, obj, client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		log.Error(err, "Failed to list pods", "selector", selector.String())
+		return 0, err
+	}
+	running, _ := withoutTerminating(pods)
+	var replaced, ready int32
+	for i := range running {
+		if running[i].CreationTimestamp.After(run.StartTime.Time) {
+			replaced++
+			if isPodReady(&running[i]) {
+				ready++
+			}
+		}
+	}
+
+	var deadline time.Time
+	if last := obj.Status.LastRestartTime; last != nil && obj.Spec.ReplacementWait != nil {
+		deadline = last.Add(obj.Spec.ReplacementWait.Duration)
+	}
+	if replaced < run.RestartedPods && now.Before(deadline) {
+		return min(runRequeueInterval, deadline.Sub(now)), nil
+	}
+
+	if replaced < run.RestartedPods {
+		log.Info("Gave up waiting for replacement pods", "restarted", run.RestartedPods, "replaced", replaced)
+	} else {
+		log.Info("Restarted pods have been replaced", "replaced", replaced)
+	}
+	setReplacementAvailability(obj, run.RestartedPods, replaced, ready)
+	obj.Status.ActiveRun = nil
+	setRunConditions(obj)
+	if err := r.Status().Update(ctx, obj); err != nil {
+		log.Error(err, "Failed to update AutoRestartPod status")
+		return 0, err
+	}
+	return 0, nil
+}
+
+// deletePods deletes each pod to trigger a restart, or evicts it under the
+// Evict strategy, and returns whether each pod was deleted in the order of
+// pods. Up to DeleteConcurrency pods are deleted at a time. It stops early,
+// leaving the remaining pods out of the results, once ctx is cancelled.
+// Kubernetes will automatically recreate these pods if they're managed by controllers like Deployment, ReplicaSet, etc.
+func (r *AutoRestartPodReconciler) deletePods(ctx context.Context, obj *stablev1.AutoRestartPod,
+	pods []corev1.Pod) (results podResults) {
+	var opts []client.DeleteOption
+	if policy := obj.Spec.DeletePropagationPolicy; policy != nil {
+		opts = append(opts, client.PropagationPolicy(*policy))
+	}
+	if seconds := obj.Spec.GracePeriodSeconds; seconds != nil {
+		opts = append(opts, client.GracePeriodSeconds(*seconds))
+	}
+	if obj.Spec.Strategy == stablev1.EvictStrategy && obj.Spec.ValidateEvictionFirst {
+		pods, results = r.validateEvictions(ctx, obj, pods, opts)
+	}
+	errs := make([]error, len(pods))
+	started := 0
+	workers := make(chan struct{}, max(r.DeleteConcurrency, 1))
+	var wg sync.WaitGroup
+	for i := range pods {
+		workers <- struct{}{}
+		// A manager shutting down deletes no more pods
+		if ctx.Err() != nil {
+			break
+		}
+		started++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-workers }()
+			errs[i] = r.deletePod(ctx, obj, &pods[i], opts)
+		}()
+	}
+	wg.Wait()
+	for i := range started {
+		results.add(pods[i].Name, errs[i])
+	}
+	return results
+}
+
+// podResults are the outcomes of the pods and workloads restarted in a pass.
+type podResults []stablev1.PodRestartResult
+
+// add records the outcome of restarting name, which failed unless err is nil.
+func (results *podResults) add(name string, err error) {
+	result := stablev1.PodRestartResult{Pod: name, Success: err == nil}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	*results = append(*results, result)
+}
+
+// names returns the names of the pods and workloads that were restarted
+// when success is set, and of those that failed otherwise.
+func (results podResults) names(success bool) []string {
+	var names []string
+	for _, result := range results {
+		if result.Success == success {
+			names = append(names, result.Pod)
+		}
+	}
+	return names
+}
+
+// recordRestartResults reports results in Status.LastRestartResults. The
+// passes that continue a run add to the results of the run so far.
+func recordRestartResults(obj *stablev1.AutoRestartPod, results podResults, continued bool) {
+	if !continued {
+		obj.Status.LastRestartResults = nil
+	}
+	obj.Status.LastRestartResults = append(obj.Status.LastRestartResults, results...)
+}
+
+// deletePod drains pod when Spec.PreDrain asks for it and deletes it with
+// opts, within a DeletePod span.
+func (r *AutoRestartPodReconciler) deletePod(ctx context.Context, obj *stablev1.AutoRestartPod,
+	pod *corev1.Pod, opts []client.DeleteOption) (err error) {
+	ctx, span := r.tracer().Start(ctx, "DeletePod", trace.WithAttributes(podNameKey.String(pod.Name)))
+	defer func() { endSpan(span, err) }()
+	log := logf.FromContext(ctx)
+
+	// The pod is still deleted, but the user is told it is gone for good
+	if !recreatedByOwner(pod) {
+		r.warn(ctx, obj, "WontBeRecreated",
+			fmt.Sprintf("pod %s has no ReplicaSet, StatefulSet or DaemonSet owner and will not be recreated", pod.Name))
+	}
+
+	// Take the pod out of the load balancer first so it stops
+	// receiving traffic; a pod that could not be drained is kept. Pods
+	// marked by drainPods were deregistered in an earlier pass already.
+	if _, drained := pod.Annotations[podDrainedAtAnnotation]; obj.Spec.PreDrain != nil && !drained {
+		if err := r.drainPod(ctx, obj.Spec.PreDrain, pod); err != nil {
+			log.Error(err, "Failed to drain pod, skipping deletion", "pod", pod.Name)
+			return err
+		}
+	}
+
+	// Leave the preStop hooks as much time as the pod says they need
+	if grace := preStopGracePeriod(ctx, pod, obj.Spec.GracePeriodSeconds); grace != nil {
+		log.Info("Extending grace period for preStop hooks", "pod", pod.Name, "gracePeriodSeconds", *grace)
+		// opts is shared by the pods of the pass
+		opts = append(opts[:len(opts):len(opts)], client.GracePeriodSeconds(*grace))
+	}
+
+	if obj.Spec.Strategy == stablev1.EvictStrategy {
+		if err := r.evictPod(ctx, pod, opts, false); err != nil {
+			log.Error(err, "Failed to evict pod", "pod", pod.Name)
+			r.recordFailure()
+			return err
+		}
+	} else if err := r.Delete(ctx, pod, opts...); err != nil {
+		log.Error(err, "Failed to delete pod", "pod", pod.Name)
+		r.recordFailure()
+		return err
+	}
+	log.Info("Restarted pod", "pod", pod.Name)
+	r.podReceipt(ctx, obj, pod)
+	return nil
+}
+
+// recreatingKinds are the kinds of pod owner that replace a deleted pod.
+var recreatingKinds = []string{"ReplicaSet", "StatefulSet", "DaemonSet"}
+
+// recreatedByOwner reports whether pod is controlled by an owner that
+// recreates it once deleted.
+func recreatedByOwner(pod *corev1.Pod) bool {
+	owner := metav1.GetControllerOf(pod)
+	return owner != nil && slices.Contains(recreatingKinds, owner.Kind)
+}
+
+// recordFailure feeds a failed restart into the circuit breaker, if any.
+func (r *AutoRestartPodReconciler) recordFailure() {
+	if r.Breaker != nil {
+		r.Breaker.RecordFailure(r.now())
+	}
+}
+
+// createdBefore returns the pods created no later than t.
+func createdBefore(pods []corev1.Pod, t time.Time) []corev1.Pod {
+	kept := pods[:0]
+	for _, pod := range pods {
+		if !pod.CreationTimestamp.After(t) {
+			kept = append(kept, pod)
+		}
+	}
+	return kept
+}
+
+// orderPods sorts pods into the order they are restarted in. Pods are
+// restarted oldest first unless spec.RestartOrder says otherwise, and
+// grouped by the value of spec.RestartOrderByLabel when set.
+func orderPods(pods []corev1.Pod, spec *stablev1.AutoRestartPodSpec) {
+	switch spec.RestartOrder {
+	case stablev1.NewestFirst:
+		sort.SliceStable(pods, func(i, j int) bool {
+			return pods[j].CreationTimestamp.Before(&pods[i].CreationTimestamp)
+		})
+	case stablev1.RandomOrder:
+		rand.Shuffle(len(pods), func(i, j int) { pods[i], pods[j] = pods[j], pods[i] })
+	default:
+		sort.SliceStable(pods, func(i, j int) bool {
+			return pods[i].CreationTimestamp.Before(&pods[j].CreationTimestamp)
+		})
+	}
+	if spec.RestartOrderByLabel != "" {
+		orderByLabel(pods, spec.RestartOrderByLabel, spec.RestartOrderLabelValues)
+	}
+}
+
+// orderByLabel stably sorts pods by the value of their label key, the
+// listed values first in the order of values, then the other values in
+// lexical order and the pods without the label last.
+func orderByLabel(pods []corev1.Pod, key string, values []string) {
+	rank := func(pod *corev1.Pod) (int, string) {
+		value, ok := pod.Labels[key]
+		if !ok {
+			return len(values) + 1, ""
+		}
+		if i := slices.Index(values, value); i >= 0 {
+			return i, ""
+		}
+		return len(values), value
+	}
+	sort.SliceStable(pods, func(i, j int) bool {
+		ri, vi := rank(&pods[i])
+		rj, vj := rank(&pods[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return vi < vj
+	})
+}
@@ -0,0 +1,700 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// newAgedPods returns count pods named nginx-<i>, where nginx-0 is the oldest.
+func newAgedPods(count int, newest time.Time) []client.Object {
+	pods := make([]client.Object, 0, count)
+	for i := range count {
+		pod := newTestPod(fmt.Sprintf("nginx-%d", i))
+		pod.CreationTimestamp = metav1.NewTime(newest.Add(-time.Duration(count-i) * time.Hour))
+		pods = append(pods, pod)
+	}
+	return pods
+}
+
+// remainingPods lists the names of the pods left in the default namespace.
+func remainingPods(c client.Client) []string {
+	podList := &corev1.PodList{}
+	Expect(c.List(context.Background(), podList, client.InNamespace("default"))).To(Succeed())
+	names := make([]string, 0, len(podList.Items))
+	for _, pod := range podList.Items {
+		names = append(names, pod.Name)
+	}
+	return names
+}
+
+var _ = Describe("Restart limits", func() {
+	var (
+		fakeClock *testingclock.FakePassiveClock
+		obj       *stablev1.AutoRestartPod
+	)
+
+	BeforeEach(func() {
+		fakeClock = testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		obj = newTestAutoRestartPod("limited", "*/5 * * * *")
+		obj.Spec.MaxPodsPerRestart = ptr.To[int32](2)
+	})
+
+	It("should restart the oldest pods up to the cap and continue on the next pass", func() {
+		pods := newAgedPods(3, fakeClock.Now())
+		c := newFakeClient(append(pods, obj)...)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		result, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(runRequeueInterval))
+		Expect(remainingPods(c)).To(ConsistOf("nginx-2"))
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.ActiveRun).NotTo(BeNil())
+		Expect(updated.Status.ActiveRun.ScheduledTime.Time).To(BeTemporally("==", time.Date(2025, 5, 26, 10, 5, 0, 0, time.UTC)))
+
+		// Replacements created during the run are not restarted again
+		replacement := newTestPod("nginx-new")
+		replacement.CreationTimestamp = metav1.NewTime(fakeClock.Now().Add(10 * time.Second))
+		Expect(c.Create(context.Background(), replacement)).To(Succeed())
+		fakeClock.SetTime(fakeClock.Now().Add(runRequeueInterval))
+
+		result, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", runRequeueInterval))
+		Expect(remainingPods(c)).To(ConsistOf("nginx-new"))
+
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.ActiveRun).To(BeNil())
+	})
+
+	It("should keep the fire time apart from when a delayed pass restarted pods", func() {
+		c := newFakeClient(append(newAgedPods(3, fakeClock.Now()), obj)...)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+		fireTime := time.Date(2025, 5, 26, 10, 5, 0, 0, time.UTC)
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.LastScheduleTime.Time).To(BeTemporally("==", fireTime))
+		Expect(updated.Status.LastRestartTime.Time).To(BeTemporally("==", fakeClock.Now()))
+
+		// The second pass runs after the fire time but still belongs to it
+		fakeClock.SetTime(fireTime.Add(2 * time.Minute))
+		_, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(BeEmpty())
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.LastScheduleTime.Time).To(BeTemporally("==", fireTime))
+		Expect(updated.Status.LastRestartTime.Time).To(BeTemporally("==", fireTime.Add(2*time.Minute)))
+	})
+
+	It("should refuse the restart and report a condition under the Refuse policy", func() {
+		obj.Spec.OverflowPolicy = stablev1.RefuseOverflow
+		pods := newAgedPods(3, fakeClock.Now())
+		c := newFakeClient(append(pods, obj)...)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(HaveLen(3))
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.LastRestartTime).To(BeNil())
+		condition := meta.FindStatusCondition(updated.Status.Conditions, stablev1.ConditionPodLimitExceeded)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring("3 pods match"))
+	})
+
+	It("should refuse from the pod metadata without listing full pods", func() {
+		obj.Spec.OverflowPolicy = stablev1.RefuseOverflow
+		recordLists := func(listed *[]string) interceptor.Funcs {
+			return interceptor.Funcs{
+				List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+					*listed = append(*listed, fmt.Sprintf("%T", list))
+					return c.List(ctx, list, opts...)
+				},
+			}
+		}
+		var cached, read []string
+		base := newFakeClientBuilder(append(newAgedPods(3, fakeClock.Now()), obj)...).Build()
+		c := interceptor.NewClient(base, recordLists(&cached))
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock,
+			APIReader: interceptor.NewClient(base, recordLists(&read))}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		// The metadata is read from the API server, not through the cache
+		Expect(read).To(ContainElement("*v1.PartialObjectMetadataList"))
+		Expect(cached).NotTo(ContainElement("*v1.PartialObjectMetadataList"))
+		Expect(cached).NotTo(ContainElement("*v1.PodList"))
+		Expect(remainingPods(c)).To(HaveLen(3))
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, stablev1.ConditionPodLimitExceeded)).To(BeTrue())
+	})
+
+	It("should count the full pods when a filter needs more than their metadata", func() {
+		obj.Spec.OverflowPolicy = stablev1.RefuseOverflow
+		obj.Spec.PodPhaseFilter = []corev1.PodPhase{corev1.PodRunning}
+		pods := newAgedPods(3, fakeClock.Now())
+		pods[0].(*corev1.Pod).Status.Phase = corev1.PodPending
+		for _, pod := range pods[1:] {
+			pod.(*corev1.Pod).Status.Phase = corev1.PodRunning
+		}
+		c := newFakeClient(append(pods, obj)...)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ConsistOf("nginx-0"))
+	})
+
+	It("should restart every pod when the match is within the cap", func() {
+		pods := newAgedPods(2, fakeClock.Now())
+		c := newFakeClient(append(pods, obj)...)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Run timeout", func() {
+	It("should stop a run that does not finish within the timeout", func() {
+		ctx := context.Background()
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		obj := newTestAutoRestartPod("stuck", "*/5 * * * *")
+		obj.Spec.MaxPodsPerRestart = ptr.To[int32](1)
+		obj.Spec.RunTimeout = &metav1.Duration{Duration: time.Minute}
+		c := newFakeClient(append(newAgedPods(5, fakeClock.Now()), obj)...)
+		recorder := record.NewFakeRecorder(20)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock, Recorder: recorder}
+
+		By("restarting one pod per pass until the timeout")
+		for range 2 {
+			_, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			fakeClock.SetTime(fakeClock.Now().Add(runRequeueInterval))
+		}
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ConsistOf("nginx-2", "nginx-3", "nginx-4"))
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.ActiveRun).To(BeNil())
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, stablev1.ConditionRunTimedOut)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(updated.Status.Conditions, stablev1.ConditionProgressing)).To(BeTrue())
+		var last string
+		for len(recorder.Events) > 0 {
+			last = <-recorder.Events
+		}
+		Expect(last).To(Equal("Warning RunTimedOut restart scheduled at " +
+			"2025-05-26T10:05:00Z did not finish within 1m0s, stopped after restarting 2 pods"))
+
+		By("clearing the condition once a run finishes in time")
+		fakeClock.SetTime(time.Date(2025, 5, 26, 10, 9, 30, 0, time.UTC))
+		Expect(c.Delete(ctx, newTestPod("nginx-4"))).To(Succeed())
+		for range 2 {
+			_, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			fakeClock.SetTime(fakeClock.Now().Add(runRequeueInterval))
+		}
+		Expect(remainingPods(c)).To(BeEmpty())
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.ActiveRun).To(BeNil())
+		Expect(meta.IsStatusConditionFalse(updated.Status.Conditions, stablev1.ConditionRunTimedOut)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Restart order", func() {
+	var (
+		fakeClock *testingclock.FakePassiveClock
+		obj       *stablev1.AutoRestartPod
+	)
+
+	BeforeEach(func() {
+		fakeClock = testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		obj = newTestAutoRestartPod("ordered", "*/5 * * * *")
+	})
+
+	// deletionOrder reconciles obj once and returns the names of the pods in
+	// the order they were deleted.
+	deletionOrder := func(pods []client.Object) []string {
+		var deleted []string
+		c := newFakeClientBuilder(append(pods, obj)...).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(ctx context.Context, c client.WithWatch, o client.Object, opts ...client.DeleteOption) error {
+					deleted = append(deleted, o.GetName())
+					return c.Delete(ctx, o, opts...)
+				},
+			}).Build()
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		return deleted
+	}
+
+	It("should delete the oldest pods first by default", func() {
+		Expect(deletionOrder(newAgedPods(3, fakeClock.Now()))).To(Equal([]string{"nginx-0", "nginx-1", "nginx-2"}))
+	})
+
+	It("should delete the newest pods first under the Newest order", func() {
+		obj.Spec.RestartOrder = stablev1.NewestFirst
+		Expect(deletionOrder(newAgedPods(3, fakeClock.Now()))).To(Equal([]string{"nginx-2", "nginx-1", "nginx-0"}))
+	})
+
+	It("should cap a pass at the newest pods under the Newest order", func() {
+		obj.Spec.RestartOrder = stablev1.NewestFirst
+		obj.Spec.MaxPodsPerRestart = ptr.To[int32](2)
+		Expect(deletionOrder(newAgedPods(3, fakeClock.Now()))).To(Equal([]string{"nginx-2", "nginx-1"}))
+	})
+
+	It("should delete every pod under the Random order", func() {
+		obj.Spec.RestartOrder = stablev1.RandomOrder
+		Expect(deletionOrder(newAgedPods(3, fakeClock.Now()))).To(ConsistOf("nginx-0", "nginx-1", "nginx-2"))
+	})
+
+	Context("by a label", func() {
+		// roledPods returns aged pods labeled with the given roles, no label
+		// for an empty role.
+		roledPods := func(roles ...string) []client.Object {
+			pods := newAgedPods(len(roles), fakeClock.Now())
+			for i, role := range roles {
+				if role != "" {
+					pods[i].GetLabels()["role"] = role
+				}
+			}
+			return pods
+		}
+
+		It("should delete the pods in the order of the listed label values", func() {
+			obj.Spec.RestartOrderByLabel = "role"
+			obj.Spec.RestartOrderLabelValues = []string{"follower", "leader"}
+			Expect(deletionOrder(roledPods("leader", "follower", "observer", "follower", ""))).To(Equal(
+				[]string{"nginx-1", "nginx-3", "nginx-0", "nginx-2", "nginx-4"}))
+		})
+
+		It("should order the values lexically and keep the restart order among equal values", func() {
+			obj.Spec.RestartOrder = stablev1.NewestFirst
+			obj.Spec.RestartOrderByLabel = "role"
+			Expect(deletionOrder(roledPods("leader", "follower", "", "follower"))).To(Equal(
+				[]string{"nginx-3", "nginx-1", "nginx-0", "nginx-2"}))
+		})
+	})
+})
+
+var _ = Describe("Delete concurrency", func() {
+	It("should delete at most DeleteConcurrency pods at a time and report every failure", func() {
+		const concurrency = 4
+		obj := newTestAutoRestartPod("bulk", "*/5 * * * *")
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		pods := newAgedPods(100, fakeClock.Now())
+
+		var inFlight, maxInFlight atomic.Int32
+		c := newFakeClientBuilder(append(pods, obj)...).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(ctx context.Context, c client.WithWatch, o client.Object, opts ...client.DeleteOption) error {
+					current := inFlight.Add(1)
+					defer inFlight.Add(-1)
+					for seen := maxInFlight.Load(); current > seen && !maxInFlight.CompareAndSwap(seen, current); {
+						seen = maxInFlight.Load()
+					}
+					time.Sleep(time.Millisecond)
+					// Every tenth pod fails
+					var n int
+					_, _ = fmt.Sscanf(o.GetName(), "nginx-%d", &n)
+					if n%10 == 0 {
+						return fmt.Errorf("deleting %s failed", o.GetName())
+					}
+					return c.Delete(ctx, o, opts...)
+				},
+			}).Build()
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock, DeleteConcurrency: concurrency}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(maxInFlight.Load()).To(BeNumerically("<=", concurrency))
+		Expect(remainingPods(c)).To(HaveLen(10))
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.LastRestartResults).To(HaveLen(100))
+		var failed []string
+		for i, result := range updated.Status.LastRestartResults {
+			// Results keep the restart order
+			Expect(result.Pod).To(Equal(fmt.Sprintf("nginx-%d", i)))
+			if !result.Success {
+				failed = append(failed, result.Pod)
+				Expect(result.Error).To(Equal(fmt.Sprintf("deleting %s failed", result.Pod)))
+			}
+		}
+		Expect(failed).To(HaveLen(10))
+	})
+})
+
+var _ = Describe("Delete options", func() {
+	// deleteOptions reconciles obj once and returns the options of every pod deletion.
+	deleteOptions := func(obj *stablev1.AutoRestartPod) []client.DeleteOptions {
+		var deletes []client.DeleteOptions
+		c := newFakeClientBuilder(obj, newTestPod("nginx-0")).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(ctx context.Context, c client.WithWatch, o client.Object, opts ...client.DeleteOption) error {
+					options := client.DeleteOptions{}
+					options.ApplyOptions(opts)
+					deletes = append(deletes, options)
+					return c.Delete(ctx, o, opts...)
+				},
+			}).Build()
+		r := newTestReconciler(c, time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		return deletes
+	}
+
+	It("should pass the configured propagation policy", func() {
+		obj := newTestAutoRestartPod("foreground", "*/5 * * * *")
+		obj.Spec.DeletePropagationPolicy = ptr.To(metav1.DeletePropagationForeground)
+
+		deletes := deleteOptions(obj)
+		Expect(deletes).To(HaveLen(1))
+		Expect(deletes[0].PropagationPolicy).To(HaveValue(Equal(metav1.DeletePropagationForeground)))
+	})
+
+	It("should pass the configured grace period", func() {
+		obj := newTestAutoRestartPod("grace", "*/5 * * * *")
+		obj.Spec.GracePeriodSeconds = ptr.To[int64](5)
+
+		deletes := deleteOptions(obj)
+		Expect(deletes).To(HaveLen(1))
+		Expect(deletes[0].GracePeriodSeconds).To(HaveValue(Equal(int64(5))))
+	})
+
+	It("should leave the propagation policy and grace period to the API server by default", func() {
+		deletes := deleteOptions(newTestAutoRestartPod("default", "*/5 * * * *"))
+		Expect(deletes).To(HaveLen(1))
+		Expect(deletes[0].PropagationPolicy).To(BeNil())
+		Expect(deletes[0].GracePeriodSeconds).To(BeNil())
+	})
+})
+
+var _ = Describe("Waiting for replacements", func() {
+	var (
+		fakeClock *testingclock.FakePassiveClock
+		obj       *stablev1.AutoRestartPod
+		c         client.Client
+		r         *AutoRestartPodReconciler
+	)
+
+	BeforeEach(func() {
+		fakeClock = testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		obj = newTestAutoRestartPod("replaced", "*/5 * * * *")
+		obj.Spec.ReplacementWait = &metav1.Duration{Duration: 2 * time.Minute}
+		c = newFakeClient(append(newAgedPods(2, fakeClock.Now()), obj)...)
+		r = &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+	})
+
+	fetch := func() *stablev1.AutoRestartPod {
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		return updated
+	}
+
+	createReplacement := func(name string) {
+		replacement := newTestPod(name)
+		replacement.CreationTimestamp = metav1.NewTime(fakeClock.Now())
+		Expect(c.Create(context.Background(), replacement)).To(Succeed())
+	}
+
+	It("should only schedule the next run after the replacements are created", func() {
+		result, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(runRequeueInterval))
+		Expect(remainingPods(c)).To(BeEmpty())
+		run := fetch().Status.ActiveRun
+		Expect(run).NotTo(BeNil())
+		Expect(run.AwaitingReplacements).To(BeTrue())
+		Expect(run.RestartedPods).To(Equal(int32(2)))
+
+		By("seeing only one replacement so far")
+		fakeClock.SetTime(fakeClock.Now().Add(20 * time.Second))
+		createReplacement("nginx-a")
+		result, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(runRequeueInterval))
+		Expect(fetch().Status.ActiveRun).NotTo(BeNil())
+
+		By("seeing every replacement")
+		fakeClock.SetTime(fakeClock.Now().Add(40 * time.Second))
+		createReplacement("nginx-b")
+		result, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fetch().Status.ActiveRun).To(BeNil())
+		Expect(remainingPods(c)).To(ConsistOf("nginx-a", "nginx-b"))
+		// 10:05:30 now, the next fire time is 10:10:00
+		Expect(result.RequeueAfter).To(Equal(4*time.Minute + 30*time.Second))
+	})
+
+	It("should stop waiting once the replacement wait is over", func() {
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+
+		fakeClock.SetTime(fakeClock.Now().Add(90 * time.Second))
+		result, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(runRequeueInterval))
+
+		fakeClock.SetTime(fakeClock.Now().Add(30 * time.Second))
+		result, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fetch().Status.ActiveRun).To(BeNil())
+		Expect(result.RequeueAfter).To(Equal(3*time.Minute + 30*time.Second))
+	})
+
+	It("should count the pods of every pass", func() {
+		obj.Spec.MaxPodsPerRestart = ptr.To[int32](1)
+		Expect(c.Update(context.Background(), obj)).To(Succeed())
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fetch().Status.ActiveRun.AwaitingReplacements).To(BeFalse())
+
+		fakeClock.SetTime(fakeClock.Now().Add(runRequeueInterval))
+		_, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		run := fetch().Status.ActiveRun
+		Expect(run.AwaitingReplacements).To(BeTrue())
+		Expect(run.RestartedPods).To(Equal(int32(2)))
+	})
+})
+
+var _ = Describe("Restart results", func() {
+	var fakeClock *testingclock.FakePassiveClock
+
+	BeforeEach(func() {
+		fakeClock = testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+	})
+
+	fetch := func(c client.Client, obj *stablev1.AutoRestartPod) *stablev1.AutoRestartPod {
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		return updated
+	}
+
+	It("should report the outcome of every pod", func() {
+		obj := newTestAutoRestartPod("results", "*/5 * * * *")
+		c := newFakeClientBuilder(append(newAgedPods(2, fakeClock.Now()), obj)...).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(ctx context.Context, c client.WithWatch, o client.Object, opts ...client.DeleteOption) error {
+					if o.GetName() == "nginx-1" {
+						return fmt.Errorf("etcd is unavailable")
+					}
+					return c.Delete(ctx, o, opts...)
+				},
+			}).Build()
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fetch(c, obj).Status.LastRestartResults).To(Equal([]stablev1.PodRestartResult{
+			{Pod: "nginx-0", Success: true},
+			{Pod: "nginx-1", Error: "etcd is unavailable"},
+		}))
+	})
+
+	It("should collect the results of every pass of a run and start over on the next fire", func() {
+		obj := newTestAutoRestartPod("results", "*/5 * * * *")
+		obj.Spec.MaxPodsPerRestart = ptr.To[int32](1)
+		c := newFakeClient(append(newAgedPods(2, fakeClock.Now()), obj)...)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		fakeClock.SetTime(fakeClock.Now().Add(runRequeueInterval))
+		_, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fetch(c, obj).Status.LastRestartResults).To(Equal([]stablev1.PodRestartResult{
+			{Pod: "nginx-0", Success: true},
+			{Pod: "nginx-1", Success: true},
+		}))
+
+		Expect(c.Create(context.Background(), newTestPod("nginx-2"))).To(Succeed())
+		fakeClock.SetTime(time.Date(2025, 5, 26, 10, 9, 30, 0, time.UTC))
+		_, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fetch(c, obj).Status.LastRestartResults).To(Equal([]stablev1.PodRestartResult{
+			{Pod: "nginx-2", Success: true},
+		}))
+	})
+})
+
+var _ = Describe("Leader failover", func() {
+	var (
+		fakeClock *testingclock.FakePassiveClock
+		obj       *stablev1.AutoRestartPod
+	)
+
+	BeforeEach(func() {
+		fakeClock = testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		obj = newTestAutoRestartPod("failover", "*/5 * * * *")
+	})
+
+	// newLeader returns the reconciler of a newly elected leader, which
+	// only knows the state persisted in c
+	newLeader := func(c client.Client) *AutoRestartPodReconciler {
+		return &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+	}
+
+	fetch := func(c client.Client, obj *stablev1.AutoRestartPod) *stablev1.AutoRestartPod {
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		return updated
+	}
+
+	It("should continue a run the old leader started without firing it again", func() {
+		ctx := context.Background()
+		obj.Spec.MaxPodsPerRestart = ptr.To[int32](1)
+		var deletes int
+		c := newFakeClientBuilder(append(newAgedPods(3, fakeClock.Now()), obj)...).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(ctx context.Context, c client.WithWatch, o client.Object, opts ...client.DeleteOption) error {
+					deletes++
+					return c.Delete(ctx, o, opts...)
+				},
+			}).Build()
+
+		_, err := newLeader(c).Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ConsistOf("nginx-1", "nginx-2"))
+		replacement := newTestPod("nginx-replacement")
+		replacement.CreationTimestamp = metav1.NewTime(fakeClock.Now().Add(time.Second))
+		Expect(c.Create(ctx, replacement)).To(Succeed())
+
+		// The old leader is gone; a new one takes over for the next passes
+		r := newLeader(c)
+		for range 4 {
+			fakeClock.SetTime(fakeClock.Now().Add(runRequeueInterval + 5*time.Second))
+			_, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(remainingPods(c)).To(ConsistOf("nginx-replacement"))
+		Expect(deletes).To(Equal(3))
+		Expect(fetch(c, obj).Status.ActiveRun).To(BeNil())
+	})
+
+	It("should restart a claimed fire the old leader did not get to", func() {
+		ctx := context.Background()
+		c := newFakeClient(append(newAgedPods(2, fakeClock.Now()), obj)...)
+		// The old leader loses the API server right after claiming the fire
+		lost := interceptor.NewClient(c.(client.WithWatch), interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				if _, ok := list.(*corev1.PodList); ok {
+					return fmt.Errorf("connection refused")
+				}
+				return c.List(ctx, list, opts...)
+			},
+		})
+		_, err := newLeader(lost).Reconcile(ctx, requestFor(obj))
+		Expect(err).To(HaveOccurred())
+		Expect(remainingPods(c)).To(HaveLen(2))
+		Expect(fetch(c, obj).Status.ActiveRun).NotTo(BeNil())
+
+		fakeClock.SetTime(fakeClock.Now().Add(10 * time.Second))
+		_, err = newLeader(c).Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(BeEmpty())
+		Expect(fetch(c, obj).Status.ActiveRun).To(BeNil())
+	})
+
+	It("should not fire again after a failover within the restart window", func() {
+		ctx := context.Background()
+		c := newFakeClient(obj, newTestPod("nginx-0"))
+		_, err := newLeader(c).Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(BeEmpty())
+
+		Expect(c.Create(ctx, newTestPod("nginx-1"))).To(Succeed())
+		fakeClock.SetTime(fakeClock.Now().Add(20 * time.Second))
+		_, err = newLeader(c).Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ConsistOf("nginx-1"))
+	})
+})
+
+var _ = Describe("Manager shutdown", func() {
+	It("should stop deleting pods once the context is cancelled and keep the run", func() {
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		obj := newTestAutoRestartPod("shutdown", "*/5 * * * *")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var deletes int
+		c := newFakeClientBuilder(append(newAgedPods(3, fakeClock.Now()), obj)...).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(ctx context.Context, c client.WithWatch, o client.Object, opts ...client.DeleteOption) error {
+					deletes++
+					// The manager is told to stop while the first pod is deleted
+					cancel()
+					return c.Delete(ctx, o, opts...)
+				},
+			}).Build()
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deletes).To(Equal(1))
+		Expect(remainingPods(c)).To(ConsistOf("nginx-1", "nginx-2"))
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.ActiveRun).NotTo(BeNil())
+		Expect(updated.Status.ActiveRun.RestartedPods).To(Equal(int32(1)))
+		Expect(updated.Status.LastRestartResults).To(HaveLen(1))
+		Expect(updated.Status.ConsecutiveFailures).To(BeZero())
+
+		// The next manager restarts the pods left
+		fakeClock.SetTime(fakeClock.Now().Add(runRequeueInterval))
+		_, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(BeEmpty())
+	})
+})
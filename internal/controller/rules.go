@@ -0,0 +1,197 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// reconcileRules fires every due entry of Spec.Restarts, records the restart
+// in the rule's status and returns how long until the next rule is due.
+func (r *AutoRestartPodReconciler) reconcileRules(ctx context.Context, obj *stablev1.AutoRestartPod) (time.Duration, error) {
+	log := logf.FromContext(ctx)
+
+	// Only one restart runs at a time. The top-level schedule continues a
+	// run when there is one, otherwise the run of a rule is continued here.
+	if obj.Status.ActiveRun != nil {
+		if obj.Spec.At != nil || hasRecurringSchedule(obj) {
+			return runRequeueInterval, nil
+		}
+		wait, err := r.continueRun(ctx, obj, r.now())
+		if err != nil || wait > 0 {
+			return wait, err
+		}
+	}
+
+	statuses := make([]stablev1.RuleStatus, 0, len(obj.Spec.Restarts))
+	var requeue time.Duration
+	for _, rule := range obj.Spec.Restarts {
+		schedule, err := parseCronSchedule(rule.Schedule)
+		if err != nil {
+			log.Error(err, "Failed to parse cron schedule", "rule", rule.Name, "schedule", rule.Schedule)
+			return 0, &scheduleParseError{err: fmt.Errorf("rule %s: %w", rule.Name, err)}
+		}
+		if obj.Spec.AlignToMidnight {
+			schedule = alignToMidnight(schedule)
+		}
+		now, err := r.nowIn(rule.TimeZone)
+		if err != nil {
+			log.Error(err, "Failed to parse timezone", "rule", rule.Name, "timezone", rule.TimeZone)
+			return 0, err
+		}
+
+		status := ruleStatus(obj, rule.Name)
+
+		// Rules use the same restart window as the top-level schedule
+		nextRun := schedule.Next(now)
+		wait := nextRun.Sub(now)
+		if wait < restartWindow {
+			last := status.LastRestartTime
+			inWindow, err := inTimeWindow(obj, nextRun)
+			if err != nil {
+				log.Error(err, "Invalid time window", "rule", rule.Name)
+				return 0, err
+			}
+			if onSkipDate(obj, nextRun) {
+				log.Info("Skipping rule tick on a skip date", "rule", rule.Name, "nextRunTime", nextRun.Format(time.RFC3339))
+			} else if !inWindow {
+				log.Info("Skipping rule tick outside the time windows", "rule", rule.Name, "nextRunTime", nextRun.Format(time.RFC3339))
+			} else if last == nil || last.Time.Before(nextRun.Add(-restartWindow)) {
+				pending, err := r.restartRule(ctx, obj, &rule, now, nextRun)
+				if err != nil {
+					return 0, err
+				}
+				// A restart that did not finish keeps the other rules waiting
+				if pending {
+					return runRequeueInterval, nil
+				}
+				status = ruleStatus(obj, rule.Name)
+				// A restart that was deferred or refused still handled the tick
+				status.LastRestartTime = &metav1.Time{Time: now}
+			}
+			wait = schedule.Next(nextRun).Sub(now)
+		}
+		if requeue == 0 || wait < requeue {
+			requeue = wait
+		}
+		statuses = append(statuses, status)
+	}
+
+	// Status of removed rules is dropped
+	if !equalRuleStatuses(obj.Status.Rules, statuses) {
+		obj.Status.Rules = statuses
+		if err := r.Status().Update(ctx, obj); err != nil {
+			log.Error(err, "Failed to update AutoRestartPod status")
+			return 0, err
+		}
+	}
+	return requeue, nil
+}
+
+// restartRule performs one pass of the restart of the pods matching rule,
+// with every guard of restart, and reports pending like it.
+func (r *AutoRestartPodReconciler) restartRule(ctx context.Context, obj *stablev1.AutoRestartPod,
+	rule *stablev1.RestartRule, now, scheduledTime time.Time) (bool, error) {
+	selector, err := r.ruleSelector(ctx, obj, rule)
+	if err != nil {
+		return false, err
+	}
+	return r.restartPods(ctx, obj, restartPass{selector: selector, rule: rule}, now, scheduledTime)
+}
+
+// ruleSelector returns the selector of the pods rule restarts.
+func (r *AutoRestartPodReconciler) ruleSelector(ctx context.Context, obj *stablev1.AutoRestartPod,
+	rule *stablev1.RestartRule) (labels.Selector, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&rule.Selector)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Invalid rule selector", "rule", rule.Name)
+		r.warn(ctx, obj, "InvalidSelector", fmt.Sprintf("rule %s: %v", rule.Name, err))
+		return nil, fmt.Errorf("invalid selector of rule %s: %w", rule.Name, err)
+	}
+	return selector, nil
+}
+
+// findRule returns the entry of Spec.Restarts named name, nil when there is
+// none.
+func findRule(obj *stablev1.AutoRestartPod, name string) *stablev1.RestartRule {
+	for i := range obj.Spec.Restarts {
+		if obj.Spec.Restarts[i].Name == name {
+			return &obj.Spec.Restarts[i]
+		}
+	}
+	return nil
+}
+
+// ruleStatus returns the status of the rule named name, an empty one when
+// it has none yet.
+func ruleStatus(obj *stablev1.AutoRestartPod, name string) stablev1.RuleStatus {
+	for _, status := range obj.Status.Rules {
+		if status.Name == name {
+			return status
+		}
+	}
+	return stablev1.RuleStatus{Name: name}
+}
+
+// setRuleRestarted records in the status of the rule named name that it
+// restarted pods at now.
+func setRuleRestarted(obj *stablev1.AutoRestartPod, name string, now time.Time) {
+	for i := range obj.Status.Rules {
+		if obj.Status.Rules[i].Name == name {
+			obj.Status.Rules[i].LastRestartTime = &metav1.Time{Time: now}
+			return
+		}
+	}
+	obj.Status.Rules = append(obj.Status.Rules,
+		stablev1.RuleStatus{Name: name, LastRestartTime: &metav1.Time{Time: now}})
+}
+
+// dropRuleRun gives up on Status.ActiveRun once its rule was removed from
+// Spec.Restarts.
+func (r *AutoRestartPodReconciler) dropRuleRun(ctx context.Context, obj *stablev1.AutoRestartPod) error {
+	log := logf.FromContext(ctx)
+
+	log.Info("Dropping restart of a removed rule", "rule", obj.Status.ActiveRun.Rule)
+	obj.Status.ActiveRun = nil
+	setRunConditions(obj)
+	if err := r.Status().Update(ctx, obj); err != nil {
+		log.Error(err, "Failed to update AutoRestartPod status")
+		return err
+	}
+	return nil
+}
+
+// equalRuleStatuses reports whether a and b hold the same rule statuses.
+func equalRuleStatuses(a, b []stablev1.RuleStatus) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || !a[i].LastRestartTime.Equal(b[i].LastRestartTime) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Restart rules", func() {
+	var (
+		fakeClock *testingclock.FakePassiveClock
+		obj       *stablev1.AutoRestartPod
+		c         client.Client
+		r         *AutoRestartPodReconciler
+	)
+
+	BeforeEach(func() {
+		fakeClock = testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		obj = newTestAutoRestartPod("rules", "")
+		obj.Spec.Selector = nil
+		obj.Spec.Restarts = []stablev1.RestartRule{
+			{
+				Name:     "web",
+				Schedule: "5 * * * *",
+				Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+			{
+				Name:     "api",
+				Schedule: "7 * * * *",
+				Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+			},
+		}
+
+		webPod := newTestPod("web-0")
+		webPod.Labels = map[string]string{"app": "web"}
+		apiPod := newTestPod("api-0")
+		apiPod.Labels = map[string]string{"app": "api"}
+		c = newFakeClient(obj, webPod, apiPod, newTestPod("nginx-0"))
+		r = &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+	})
+
+	ruleRestartTime := func(name string) *metav1.Time {
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		for _, status := range updated.Status.Rules {
+			if status.Name == name {
+				return status.LastRestartTime
+			}
+		}
+		return nil
+	}
+
+	It("should fire each rule independently", func() {
+		By("restarting the web pods at minute 5")
+		result, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ConsistOf("api-0", "nginx-0"))
+		Expect(ruleRestartTime("web")).NotTo(BeNil())
+		Expect(ruleRestartTime("api")).To(BeNil())
+		// The api rule is due next, at 10:07
+		Expect(result.RequeueAfter).To(Equal(2*time.Minute + 30*time.Second))
+
+		By("restarting the api pods at minute 7 without restarting web again")
+		replacement := newTestPod("web-1")
+		replacement.Labels = map[string]string{"app": "web"}
+		Expect(c.Create(context.Background(), replacement)).To(Succeed())
+		fakeClock.SetTime(time.Date(2025, 5, 26, 10, 6, 30, 0, time.UTC))
+
+		result, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ConsistOf("web-1", "nginx-0"))
+		Expect(ruleRestartTime("web").Time).To(BeTemporally("==", time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC)))
+		Expect(ruleRestartTime("api").Time).To(BeTemporally("==", time.Date(2025, 5, 26, 10, 6, 30, 0, time.UTC)))
+		// The web rule fires again at 11:05
+		Expect(result.RequeueAfter).To(Equal(58*time.Minute + 30*time.Second))
+	})
+
+	It("should run rules alongside the top-level schedule", func() {
+		obj.Spec.Schedule = "*/5 * * * *"
+		obj.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}}
+		Expect(c.Update(context.Background(), obj)).To(Succeed())
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ConsistOf("api-0"))
+	})
+
+	It("should drop the status of removed rules", func() {
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		updated.Spec.Restarts = updated.Spec.Restarts[1:]
+		Expect(c.Update(context.Background(), updated)).To(Succeed())
+
+		_, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ruleRestartTime("web")).To(BeNil())
+	})
+
+	It("should not restart rules on a skip date", func() {
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		updated.Spec.SkipDates = []string{"2025-05-26"}
+		Expect(c.Update(context.Background(), updated)).To(Succeed())
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ruleRestartTime("web")).To(BeNil())
+		Expect(remainingPods(c)).To(ContainElement("web-0"))
+	})
+
+	It("should defer a rule while the circuit breaker is open", func() {
+		r.Breaker = NewCircuitBreaker(1, time.Minute)
+		r.Breaker.RecordFailure(fakeClock.Now())
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ContainElement("web-0"))
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.ActiveRun).NotTo(BeNil())
+		Expect(updated.Status.ActiveRun.Rule).To(Equal("web"))
+
+		By("continuing the run of the rule once the breaker closed")
+		fakeClock.SetTime(fakeClock.Now().Add(time.Minute))
+		_, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ConsistOf("api-0", "nginx-0"))
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.ActiveRun).To(BeNil())
+		Expect(ruleRestartTime("web")).NotTo(BeNil())
+	})
+
+	It("should refuse a rule restart over the overflow cap", func() {
+		obj.Spec.MaxPodsPerRestart = ptr.To[int32](1)
+		obj.Spec.OverflowPolicy = stablev1.RefuseOverflow
+		Expect(c.Update(context.Background(), obj)).To(Succeed())
+		webPod := newTestPod("web-1")
+		webPod.Labels = map[string]string{"app": "web"}
+		Expect(c.Create(context.Background(), webPod)).To(Succeed())
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ContainElements("web-0", "web-1"))
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, stablev1.ConditionPodLimitExceeded)).To(BeTrue())
+		Expect(updated.Status.ActiveRun).To(BeNil())
+	})
+})
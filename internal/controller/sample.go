@@ -0,0 +1,58 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// samplePods returns n of pods chosen at random, in their order, or every pod
+// when there are no more than n. The choice is seeded by Spec.RestartSampleSeed,
+// or the object's UID, and the fire time, so every pass of the same fire
+// draws from the same sequence.
+func samplePods(obj *stablev1.AutoRestartPod, pods []corev1.Pod, n int, fire time.Time) []corev1.Pod {
+	if n <= 0 {
+		return nil
+	}
+	if len(pods) <= n {
+		return pods
+	}
+
+	seed := []byte(obj.UID)
+	if obj.Spec.RestartSampleSeed != nil {
+		seed = strconv.AppendInt(nil, *obj.Spec.RestartSampleSeed, 10)
+	}
+	sum := sha256.Sum256(binary.BigEndian.AppendUint64(seed, uint64(fire.Unix())))
+	rng := rand.New(rand.NewPCG(binary.BigEndian.Uint64(sum[:8]), binary.BigEndian.Uint64(sum[8:16])))
+
+	picked := rng.Perm(len(pods))[:n]
+	slices.Sort(picked)
+	sample := make([]corev1.Pod, 0, n)
+	for _, i := range picked {
+		sample = append(sample, pods[i])
+	}
+	return sample
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("Restart sample", func() {
+	now := time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC)
+
+	// restartSample reconciles an object restarting size of five pods and
+	// returns the names of the pods left
+	restartSample := func(size int32, seed *int64) []string {
+		obj := newTestAutoRestartPod("sample", "*/5 * * * *")
+		obj.Spec.RestartSampleSize = ptr.To(size)
+		obj.Spec.RestartSampleSeed = seed
+		c := newFakeClient(append(newAgedPods(5, now), obj)...)
+		r := newTestReconciler(c, now)
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		return remainingPods(c)
+	}
+
+	It("should restart exactly the sample size when more pods match", func() {
+		Expect(restartSample(2, nil)).To(HaveLen(3))
+	})
+
+	It("should restart every pod when the sample size matches the pod count", func() {
+		Expect(restartSample(5, nil)).To(BeEmpty())
+	})
+
+	It("should restart every pod when fewer pods match than the sample size", func() {
+		Expect(restartSample(10, nil)).To(BeEmpty())
+	})
+
+	It("should pick the same pods for the same seed and fire time", func() {
+		remaining := restartSample(2, ptr.To[int64](42))
+		Expect(remaining).To(HaveLen(3))
+		Expect(restartSample(2, ptr.To[int64](42))).To(Equal(remaining))
+	})
+
+	It("should pick different pods on different fires", func() {
+		obj := newTestAutoRestartPod("sample", "*/5 * * * *")
+		obj.Spec.RestartSampleSeed = ptr.To[int64](42)
+		var pods []corev1.Pod
+		for i := range 20 {
+			pods = append(pods, *newTestPod(fmt.Sprintf("nginx-%d", i)))
+		}
+		first := samplePods(obj, pods, 5, time.Date(2025, 5, 26, 10, 5, 0, 0, time.UTC))
+		second := samplePods(obj, pods, 5, time.Date(2025, 5, 26, 10, 10, 0, 0, time.UTC))
+		Expect(first).To(HaveLen(5))
+		Expect(second).To(HaveLen(5))
+		Expect(first).NotTo(Equal(second))
+	})
+})
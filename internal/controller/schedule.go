@@ -0,0 +1,381 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// scheduleParseError is returned by a reconcile that failed because a
+// schedule of the spec does not parse.
+type scheduleParseError struct {
+	err error
+}
+
+func (e *scheduleParseError) Error() string { return e.err.Error() }
+
+func (e *scheduleParseError) Unwrap() error { return e.err }
+
+// updateInvalidSchedule reports in the InvalidSchedule condition whether
+// reconcileErr is a scheduleParseError, and counts it in
+// scheduleParseErrors. The condition is only reported False once a schedule
+// failed to parse before and a reconcile succeeded since. It reports whether
+// the condition changed.
+func updateInvalidSchedule(obj *stablev1.AutoRestartPod, reconcileErr error) bool {
+	var parseErr *scheduleParseError
+	switch {
+	case errors.As(reconcileErr, &parseErr):
+		scheduleParseErrors.WithLabelValues(obj.Namespace, obj.Name).Inc()
+		return setCondition(obj, stablev1.ConditionInvalidSchedule, metav1.ConditionTrue, "ParseError",
+			parseErr.Error())
+	case reconcileErr == nil && getCondition(obj, stablev1.ConditionInvalidSchedule) != nil:
+		return setCondition(obj, stablev1.ConditionInvalidSchedule, metav1.ConditionFalse, "Parsed",
+			"every schedule parses")
+	}
+	return false
+}
+
+// environmentLabel on an AutoRestartPod names the environment it is deployed
+// to, selecting an entry of Spec.EnvironmentSchedules.
+const environmentLabel = "environment"
+
+// effectiveSchedule returns the schedule expression obj runs on: the entry of
+// Spec.EnvironmentSchedules for its environment label, or Spec.Schedule when
+// there is none. A weekly pattern is described by its cron expressions.
+func effectiveSchedule(obj *stablev1.AutoRestartPod) string {
+	if len(obj.Spec.Weekly) > 0 {
+		expressions, _ := weeklyExpressions(obj.Spec.Weekly)
+		return strings.Join(expressions, ", ")
+	}
+	if env, ok := obj.Labels[environmentLabel]; ok {
+		if schedule, ok := obj.Spec.EnvironmentSchedules[env]; ok {
+			return schedule
+		}
+	}
+	return obj.Spec.Schedule
+}
+
+// weekdays maps the days of Spec.Weekly to their cron day of the week.
+var weekdays = map[string]time.Weekday{
+	"Sunday":    time.Sunday,
+	"Monday":    time.Monday,
+	"Tuesday":   time.Tuesday,
+	"Wednesday": time.Wednesday,
+	"Thursday":  time.Thursday,
+	"Friday":    time.Friday,
+	"Saturday":  time.Saturday,
+}
+
+// weeklyExpressions converts the entries of a weekly pattern into one cron
+// expression per entry.
+func weeklyExpressions(entries []stablev1.WeeklyEntry) ([]string, error) {
+	expressions := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		day, ok := weekdays[entry.Day]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekly day %q", entry.Day)
+		}
+		at, err := time.Parse("15:04", entry.Time)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weekly time %q: %w", entry.Time, err)
+		}
+		expressions = append(expressions, fmt.Sprintf("%d %d * * %d", at.Minute(), at.Hour(), day))
+	}
+	return expressions, nil
+}
+
+// unionSchedule fires whenever any of its schedules fires.
+type unionSchedule []cron.Schedule
+
+// Next implements cron.Schedule.
+func (s unionSchedule) Next(t time.Time) time.Time {
+	var next time.Time
+	for _, schedule := range s {
+		if n := schedule.Next(t); !n.IsZero() && (next.IsZero() || n.Before(next)) {
+			next = n
+		}
+	}
+	return next
+}
+
+// weekdayKeys maps the keys of Spec.WeeklySchedules to their day of the week.
+var weekdayKeys = map[string]time.Weekday{
+	"SUN": time.Sunday,
+	"MON": time.Monday,
+	"TUE": time.Tuesday,
+	"WED": time.Wednesday,
+	"THU": time.Thursday,
+	"FRI": time.Friday,
+	"SAT": time.Saturday,
+}
+
+// weekdaySchedule fires on every day of the week with its own schedule of
+// days, and on the fallback schedule on the other days. Only the fire times
+// falling on a day count for it. Days are those of the location of the time
+// passed to Next.
+type weekdaySchedule struct {
+	days map[time.Weekday]cron.Schedule
+	// fallback is nil when only the listed days restart.
+	fallback cron.Schedule
+}
+
+// Next implements cron.Schedule.
+func (s weekdaySchedule) Next(t time.Time) time.Time {
+	// A week and a day covers every weekday after t's
+	for i := range 8 {
+		day := time.Date(t.Year(), t.Month(), t.Day()+i, 0, 0, 0, 0, t.Location())
+		schedule, ok := s.days[day.Weekday()]
+		if !ok {
+			schedule = s.fallback
+		}
+		if schedule == nil {
+			continue
+		}
+		from := t
+		if i > 0 {
+			// Start just before midnight so a fire time at 00:00 counts
+			from = day.Add(-time.Second)
+		}
+		next := schedule.Next(from)
+		if y, m, d := next.Date(); !next.IsZero() && y == day.Year() && m == day.Month() && d == day.Day() {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// topLevelSchedule returns the schedule the top-level fields of obj run on:
+// the weekly pattern when Spec.Weekly is set, the effective cron expression
+// otherwise, overridden on the days Spec.WeeklySchedules lists.
+func topLevelSchedule(obj *stablev1.AutoRestartPod) (cron.Schedule, error) {
+	if len(obj.Spec.WeeklySchedules) > 0 {
+		return weeklySchedules(obj)
+	}
+	if len(obj.Spec.Weekly) == 0 {
+		return parseCronSchedule(effectiveSchedule(obj))
+	}
+	expressions, err := weeklyExpressions(obj.Spec.Weekly)
+	if err != nil {
+		return nil, err
+	}
+	schedules := make(unionSchedule, 0, len(expressions))
+	for _, expression := range expressions {
+		schedule, err := parseCronSchedule(expression)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+// weeklySchedules returns the schedule of Spec.WeeklySchedules falling back
+// to the effective cron expression of obj.
+func weeklySchedules(obj *stablev1.AutoRestartPod) (cron.Schedule, error) {
+	schedule := weekdaySchedule{days: make(map[time.Weekday]cron.Schedule, len(obj.Spec.WeeklySchedules))}
+	for key, expression := range obj.Spec.WeeklySchedules {
+		day, ok := weekdayKeys[key]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", key)
+		}
+		daySchedule, err := parseCronSchedule(expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule for %s: %w", key, err)
+		}
+		schedule.days[day] = daySchedule
+	}
+	if expression := effectiveSchedule(obj); expression != "" {
+		fallback, err := parseCronSchedule(expression)
+		if err != nil {
+			return nil, err
+		}
+		schedule.fallback = fallback
+	}
+	return schedule, nil
+}
+
+// hasRecurringSchedule reports whether the top-level fields of obj restart
+// on a recurring schedule. Objects with nothing but rules do not.
+func hasRecurringSchedule(obj *stablev1.AutoRestartPod) bool {
+	return obj.Spec.Schedule != "" || len(obj.Spec.Weekly) > 0 || len(obj.Spec.WeeklySchedules) > 0 ||
+		len(obj.Spec.Restarts) == 0
+}
+
+// alignedSchedule fires every interval counted from midnight in the location
+// of the time passed to Next. When the interval does not divide a day evenly
+// the sequence starts over at the following midnight.
+type alignedSchedule struct {
+	interval time.Duration
+}
+
+// Next implements cron.Schedule.
+func (s alignedSchedule) Next(t time.Time) time.Time {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	nextMidnight := midnight.AddDate(0, 0, 1)
+
+	next := midnight.Add((t.Sub(midnight)/s.interval + 1) * s.interval)
+	if !next.Before(nextMidnight) {
+		return nextMidnight
+	}
+	return next
+}
+
+// alignToMidnight returns schedule aligned to midnight when it is an
+// "@every" interval; other schedules are returned unchanged.
+func alignToMidnight(schedule cron.Schedule) cron.Schedule {
+	switch every := schedule.(type) {
+	case everySchedule:
+		return alignedSchedule{interval: every.interval}
+	case cron.ConstantDelaySchedule:
+		if every.Delay > 0 {
+			return alignedSchedule{interval: every.Delay}
+		}
+	}
+	return schedule
+}
+
+// everySchedule fires on the multiples of interval since the zero time,
+// which fall on midnight UTC when interval divides a day. The
+// cron.ConstantDelaySchedule of an "@every" descriptor fires interval after
+// whatever time it is asked about, so its next fire time would move along
+// with every reconcile and never come due.
+type everySchedule struct {
+	interval time.Duration
+}
+
+// Next implements cron.Schedule.
+func (s everySchedule) Next(t time.Time) time.Time {
+	return t.Truncate(s.interval).Add(s.interval)
+}
+
+// upcomingRestartTimes is how many fire times Status.UpcomingRestartTimes lists.
+const upcomingRestartTimes = 3
+
+// NextFireTimes returns the next n fire times of schedule after from. It
+// returns fewer once the schedule stops firing.
+func NextFireTimes(schedule cron.Schedule, from time.Time, n int) []time.Time {
+	var fires []time.Time
+	for range n {
+		from = schedule.Next(from)
+		if from.IsZero() {
+			break
+		}
+		fires = append(fires, from)
+	}
+	return fires
+}
+
+// scheduleFormat returns the format the expressions of the top-level
+// schedule of obj are parsed in, seconds when any of them has a seconds
+// field. Weekly patterns are always standard.
+func scheduleFormat(obj *stablev1.AutoRestartPod) stablev1.ScheduleFormat {
+	var expressions []string
+	switch {
+	case len(obj.Spec.WeeklySchedules) > 0:
+		for _, expression := range obj.Spec.WeeklySchedules {
+			expressions = append(expressions, expression)
+		}
+		if expression := effectiveSchedule(obj); expression != "" {
+			expressions = append(expressions, expression)
+		}
+	case len(obj.Spec.Weekly) > 0:
+		return stablev1.StandardScheduleFormat
+	default:
+		expressions = append(expressions, effectiveSchedule(obj))
+	}
+	format := stablev1.StandardScheduleFormat
+	for _, expression := range expressions {
+		if _, f, err := parseCronScheduleFormat(expression); err == nil && f == stablev1.SecondsScheduleFormat {
+			format = f
+		}
+	}
+	return format
+}
+
+// updateScheduleStatus lists the next fire times of schedule after now in
+// Status.UpcomingRestartTimes and records the format its expressions were
+// parsed in.
+func (r *AutoRestartPodReconciler) updateScheduleStatus(ctx context.Context, obj *stablev1.AutoRestartPod,
+	schedule cron.Schedule, now time.Time) error {
+	fires := NextFireTimes(schedule, now, upcomingRestartTimes)
+	upcoming := make([]metav1.Time, 0, len(fires))
+	for _, fire := range fires {
+		upcoming = append(upcoming, metav1.Time{Time: fire})
+	}
+	format := scheduleFormat(obj)
+	if obj.Status.ParsedScheduleFormat == format && slices.EqualFunc(obj.Status.UpcomingRestartTimes, upcoming,
+		func(a, b metav1.Time) bool {
+			return a.Equal(&b)
+		}) {
+		return nil
+	}
+	obj.Status.UpcomingRestartTimes = upcoming
+	obj.Status.ParsedScheduleFormat = format
+	if err := r.Status().Update(ctx, obj); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to update AutoRestartPod status")
+		return err
+	}
+	return nil
+}
+
+// updateTimeZoneCondition explains in the TimeZoneOffset condition how the
+// schedule's timezone moves nextRun relative to UTC. The condition is True
+// when the run falls on a different calendar day in UTC than in the
+// timezone, which is easily mistaken for a wrong schedule. It is removed
+// for timezones without an offset.
+func (r *AutoRestartPodReconciler) updateTimeZoneCondition(ctx context.Context, obj *stablev1.AutoRestartPod,
+	nextRun time.Time) error {
+	var changed bool
+	_, offset := nextRun.Zone()
+	if offset == 0 {
+		changed = meta.RemoveStatusCondition(&obj.Status.Conditions, stablev1.ConditionTimeZoneOffset)
+	} else {
+		utc := nextRun.UTC()
+		condition := metav1.Condition{
+			Type:   stablev1.ConditionTimeZoneOffset,
+			Status: metav1.ConditionFalse,
+			Reason: "SameDay",
+		}
+		if y, m, d := nextRun.Date(); utc.Year() != y || utc.Month() != m || utc.Day() != d {
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = "DayBoundaryCrossed"
+		}
+		condition.Message = fmt.Sprintf("timezone %s is UTC%s, the next run at %s is %s in UTC",
+			nextRun.Location(), nextRun.Format("-07:00"), nextRun.Format(time.RFC3339), utc.Format(time.RFC3339))
+		changed = meta.SetStatusCondition(&obj.Status.Conditions, condition)
+	}
+	if !changed {
+		return nil
+	}
+	if err := r.Status().Update(ctx, obj); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to update AutoRestartPod status")
+		return err
+	}
+	return nil
+}
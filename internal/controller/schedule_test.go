@@ -0,0 +1,524 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/robfig/cron/v3"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Schedules", func() {
+	Context("aligned to midnight", func() {
+		It("should fire on even boundaries from midnight", func() {
+			schedule, err := parseCronSchedule("@every 6h")
+			Expect(err).NotTo(HaveOccurred())
+			schedule = alignToMidnight(schedule)
+
+			from := time.Date(2025, 5, 26, 7, 13, 0, 0, time.UTC)
+			var fires []time.Time
+			for range 4 {
+				from = schedule.Next(from)
+				fires = append(fires, from)
+			}
+			Expect(fires).To(Equal([]time.Time{
+				time.Date(2025, 5, 26, 12, 0, 0, 0, time.UTC),
+				time.Date(2025, 5, 26, 18, 0, 0, 0, time.UTC),
+				time.Date(2025, 5, 27, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, 5, 27, 6, 0, 0, 0, time.UTC),
+			}))
+		})
+
+		It("should start over at midnight when the interval does not divide the day", func() {
+			schedule := alignToMidnight(cron.Every(7 * time.Hour))
+			Expect(schedule.Next(time.Date(2025, 5, 26, 20, 0, 0, 0, time.UTC))).
+				To(Equal(time.Date(2025, 5, 26, 21, 0, 0, 0, time.UTC)))
+			Expect(schedule.Next(time.Date(2025, 5, 26, 21, 0, 0, 0, time.UTC))).
+				To(Equal(time.Date(2025, 5, 27, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("should align to midnight in the schedule's timezone", func() {
+			shanghai, err := time.LoadLocation("Asia/Shanghai")
+			Expect(err).NotTo(HaveOccurred())
+
+			schedule := alignToMidnight(cron.Every(6 * time.Hour))
+			next := schedule.Next(time.Date(2025, 5, 26, 1, 0, 0, 0, time.UTC).In(shanghai))
+			Expect(next).To(BeTemporally("==", time.Date(2025, 5, 26, 12, 0, 0, 0, shanghai)))
+		})
+
+		It("should leave cron expressions unchanged", func() {
+			schedule, err := parseCronSchedule("0 3 * * *")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(alignToMidnight(schedule)).To(Equal(schedule))
+		})
+
+		It("should restart on an aligned boundary", func() {
+			obj := newTestAutoRestartPod("aligned", "@every 6h")
+			obj.Spec.AlignToMidnight = true
+			c := newFakeClient(obj, newTestPod("nginx-0"))
+			r := newTestReconciler(c, time.Date(2025, 5, 26, 11, 59, 30, 0, time.UTC))
+
+			result, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, newTestPod("nginx-0"))).To(BeFalse())
+			Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+		})
+	})
+
+	Context("per environment", func() {
+		var obj *stablev1.AutoRestartPod
+
+		BeforeEach(func() {
+			obj = newTestAutoRestartPod("environments", "0 3 * * *")
+			obj.Spec.EnvironmentSchedules = map[string]string{
+				"staging":    "*/5 * * * *",
+				"production": "0 4 * * 0",
+			}
+		})
+
+		It("should use the schedule of the environment label", func() {
+			obj.Labels = map[string]string{environmentLabel: "staging"}
+			Expect(effectiveSchedule(obj)).To(Equal("*/5 * * * *"))
+			obj.Labels[environmentLabel] = "production"
+			Expect(effectiveSchedule(obj)).To(Equal("0 4 * * 0"))
+		})
+
+		It("should fall back to the default schedule", func() {
+			Expect(effectiveSchedule(obj)).To(Equal("0 3 * * *"))
+			obj.Labels = map[string]string{environmentLabel: "dev"}
+			Expect(effectiveSchedule(obj)).To(Equal("0 3 * * *"))
+		})
+
+		It("should restart on the environment's schedule", func() {
+			fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+			staging := obj.DeepCopy()
+			staging.Name = "staging"
+			staging.Labels = map[string]string{environmentLabel: "staging"}
+			c := newFakeClient(obj, staging, newTestPod("nginx-0"))
+			r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+			// The default schedule is not due at 10:04:30
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, newTestPod("nginx-0"))).To(BeTrue())
+
+			// The staging schedule fires at 10:05
+			_, err = r.Reconcile(context.Background(), requestFor(staging))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, newTestPod("nginx-0"))).To(BeFalse())
+		})
+	})
+
+	Context("as a weekly pattern", func() {
+		var obj *stablev1.AutoRestartPod
+
+		BeforeEach(func() {
+			obj = newTestAutoRestartPod("weekly", "")
+			obj.Spec.Weekly = []stablev1.WeeklyEntry{
+				{Day: "Monday", Time: "03:00"},
+				{Day: "Friday", Time: "01:30"},
+				{Day: "Wednesday", Time: "22:00"},
+			}
+		})
+
+		It("should fire on every entry in turn", func() {
+			schedule, err := topLevelSchedule(obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			// 2025-05-26 is a Monday
+			next := schedule.Next(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+			Expect(next).To(Equal(time.Date(2025, 5, 28, 22, 0, 0, 0, time.UTC)))
+			next = schedule.Next(next)
+			Expect(next).To(Equal(time.Date(2025, 5, 30, 1, 30, 0, 0, time.UTC)))
+			next = schedule.Next(next)
+			Expect(next).To(Equal(time.Date(2025, 6, 2, 3, 0, 0, 0, time.UTC)))
+		})
+
+		It("should read the times in the schedule's timezone", func() {
+			schedule, err := topLevelSchedule(obj)
+			Expect(err).NotTo(HaveOccurred())
+			shanghai, err := time.LoadLocation("Asia/Shanghai")
+			Expect(err).NotTo(HaveOccurred())
+
+			next := schedule.Next(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC).In(shanghai))
+			Expect(next).To(BeTemporally("==", time.Date(2025, 5, 28, 14, 0, 0, 0, time.UTC)))
+		})
+
+		It("should describe the pattern by its cron expressions", func() {
+			Expect(effectiveSchedule(obj)).To(Equal("0 3 * * 1, 30 1 * * 5, 0 22 * * 3"))
+		})
+
+		It("should reject an unknown day", func() {
+			obj.Spec.Weekly[1].Day = "Fri"
+			_, err := topLevelSchedule(obj)
+			Expect(err).To(MatchError(ContainSubstring(`invalid weekly day "Fri"`)))
+		})
+
+		It("should restart when an entry is due", func() {
+			fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+			obj.Spec.Weekly = append(obj.Spec.Weekly, stablev1.WeeklyEntry{Day: "Monday", Time: "10:05"})
+			c := newFakeClient(obj, newTestPod("nginx-0"))
+			r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, newTestPod("nginx-0"))).To(BeFalse())
+
+			updated := &stablev1.AutoRestartPod{}
+			Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+			Expect(updated.Status.LastRestartTime).NotTo(BeNil())
+		})
+	})
+
+	Context("per weekday", func() {
+		var obj *stablev1.AutoRestartPod
+
+		BeforeEach(func() {
+			obj = newTestAutoRestartPod("weekdays", "0 3 * * *")
+			obj.Spec.WeeklySchedules = map[string]string{
+				"FRI": "0 1 * * *",
+				"SUN": "30 4 * * *",
+			}
+		})
+
+		// next returns the first run of obj after t
+		next := func(t time.Time) time.Time {
+			schedule, err := topLevelSchedule(obj)
+			Expect(err).NotTo(HaveOccurred())
+			return schedule.Next(t)
+		}
+
+		It("should use the schedule of the day", func() {
+			// 2025-05-30 is a Friday; its own schedule replaces the 03:00 run
+			Expect(next(time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC))).
+				To(Equal(time.Date(2025, 5, 30, 1, 0, 0, 0, time.UTC)))
+			Expect(next(time.Date(2025, 5, 30, 0, 30, 0, 0, time.UTC))).
+				To(Equal(time.Date(2025, 5, 30, 1, 0, 0, 0, time.UTC)))
+			Expect(next(time.Date(2025, 5, 31, 10, 0, 0, 0, time.UTC))).
+				To(Equal(time.Date(2025, 6, 1, 4, 30, 0, 0, time.UTC)))
+		})
+
+		It("should fall back to the schedule on other days", func() {
+			// Friday's schedule does not fire again on Friday
+			Expect(next(time.Date(2025, 5, 30, 2, 0, 0, 0, time.UTC))).
+				To(Equal(time.Date(2025, 5, 31, 3, 0, 0, 0, time.UTC)))
+			Expect(next(time.Date(2025, 6, 1, 5, 0, 0, 0, time.UTC))).
+				To(Equal(time.Date(2025, 6, 2, 3, 0, 0, 0, time.UTC)))
+		})
+
+		It("should only restart on the listed days without a schedule", func() {
+			obj.Spec.Schedule = ""
+			Expect(next(time.Date(2025, 6, 1, 5, 0, 0, 0, time.UTC))).
+				To(Equal(time.Date(2025, 6, 6, 1, 0, 0, 0, time.UTC)))
+		})
+
+		It("should pick the weekday in the schedule's timezone", func() {
+			shanghai, err := time.LoadLocation("Asia/Shanghai")
+			Expect(err).NotTo(HaveOccurred())
+
+			// Thursday 20:00 in UTC is already Friday 04:00 in Shanghai
+			Expect(next(time.Date(2025, 5, 29, 20, 0, 0, 0, time.UTC).In(shanghai))).
+				To(BeTemporally("==", time.Date(2025, 5, 31, 3, 0, 0, 0, shanghai)))
+		})
+
+		It("should reject an unknown weekday", func() {
+			obj.Spec.WeeklySchedules["FRIDAY"] = "0 1 * * *"
+			_, err := topLevelSchedule(obj)
+			Expect(err).To(MatchError(ContainSubstring(`invalid weekday "FRIDAY"`)))
+		})
+
+		It("should restart on the schedule of the simulated weekday", func() {
+			fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 30, 0, 59, 30, 0, time.UTC))
+			plain := newTestAutoRestartPod("plain", "0 3 * * *")
+			c := newFakeClient(obj, plain, newTestPod("nginx-0"))
+			r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+			// Only the Friday schedule is due at 00:59:30 on a Friday
+			_, err := r.Reconcile(context.Background(), requestFor(plain))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, newTestPod("nginx-0"))).To(BeTrue())
+
+			_, err = r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, newTestPod("nginx-0"))).To(BeFalse())
+		})
+	})
+
+	Context("with a timezone offset", func() {
+		// reconcileOffset reconciles a daily 03:00 schedule in timeZone at
+		// 10:04:30 UTC and returns its TimeZoneOffset condition.
+		reconcileOffset := func(timeZone string) *metav1.Condition {
+			obj := newTestAutoRestartPod("offset", "0 3 * * *")
+			obj.Spec.TimeZone = timeZone
+			c := newFakeClient(obj)
+			r := newTestReconciler(c, time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			updated := &stablev1.AutoRestartPod{}
+			Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+			return meta.FindStatusCondition(updated.Status.Conditions, stablev1.ConditionTimeZoneOffset)
+		}
+
+		It("should explain a run that lands on another day in UTC", func() {
+			condition := reconcileOffset("Pacific/Kiritimati")
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("DayBoundaryCrossed"))
+			Expect(condition.Message).To(Equal("timezone Pacific/Kiritimati is UTC+14:00, " +
+				"the next run at 2025-05-27T03:00:00+14:00 is 2025-05-26T13:00:00Z in UTC"))
+		})
+
+		It("should report the offset of a run on the same day", func() {
+			condition := reconcileOffset("America/New_York")
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring("UTC-04:00"))
+		})
+
+		It("should not report timezones without an offset", func() {
+			Expect(reconcileOffset("UTC")).To(BeNil())
+			Expect(reconcileOffset("")).To(BeNil())
+		})
+	})
+
+	Context("next fire times", func() {
+		It("should list the next fire times of a standard schedule", func() {
+			schedule, err := parseCronSchedule("0 */6 * * *")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(NextFireTimes(schedule, time.Date(2025, 5, 26, 7, 13, 0, 0, time.UTC), 3)).To(Equal([]time.Time{
+				time.Date(2025, 5, 26, 12, 0, 0, 0, time.UTC),
+				time.Date(2025, 5, 26, 18, 0, 0, 0, time.UTC),
+				time.Date(2025, 5, 27, 0, 0, 0, 0, time.UTC),
+			}))
+		})
+
+		It("should list the next fire times of a schedule with seconds", func() {
+			schedule, err := parseCronSchedule("15,45 * * * * *")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(NextFireTimes(schedule, time.Date(2025, 5, 26, 7, 13, 30, 0, time.UTC), 3)).To(Equal([]time.Time{
+				time.Date(2025, 5, 26, 7, 13, 45, 0, time.UTC),
+				time.Date(2025, 5, 26, 7, 14, 15, 0, time.UTC),
+				time.Date(2025, 5, 26, 7, 14, 45, 0, time.UTC),
+			}))
+		})
+
+		It("should keep the wall clock time of a timezone across DST changes", func() {
+			newYork, err := time.LoadLocation("America/New_York")
+			Expect(err).NotTo(HaveOccurred())
+			schedule, err := parseCronSchedule("0 12 * * *")
+			Expect(err).NotTo(HaveOccurred())
+
+			// Clocks sprang forward on 2025-03-09 and fell back on 2025-11-02
+			fires := NextFireTimes(schedule, time.Date(2025, 3, 8, 0, 0, 0, 0, newYork), 2)
+			Expect(fires).To(HaveLen(2))
+			Expect(fires[0].UTC()).To(Equal(time.Date(2025, 3, 8, 17, 0, 0, 0, time.UTC)))
+			Expect(fires[1].UTC()).To(Equal(time.Date(2025, 3, 9, 16, 0, 0, 0, time.UTC)))
+
+			fires = NextFireTimes(schedule, time.Date(2025, 11, 1, 0, 0, 0, 0, newYork), 2)
+			Expect(fires).To(HaveLen(2))
+			Expect(fires[0].UTC()).To(Equal(time.Date(2025, 11, 1, 16, 0, 0, 0, time.UTC)))
+			Expect(fires[1].UTC()).To(Equal(time.Date(2025, 11, 2, 17, 0, 0, 0, time.UTC)))
+		})
+
+		It("should report the upcoming restart times in status", func() {
+			ctx := context.Background()
+			obj := newTestAutoRestartPod("upcoming", "0 */6 * * *")
+			c := newFakeClient(obj)
+			r := newTestReconciler(c, time.Date(2025, 5, 26, 7, 13, 0, 0, time.UTC))
+
+			_, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			updated := &stablev1.AutoRestartPod{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+			var upcoming []time.Time
+			for _, fire := range updated.Status.UpcomingRestartTimes {
+				upcoming = append(upcoming, fire.UTC())
+			}
+			Expect(upcoming).To(Equal([]time.Time{
+				time.Date(2025, 5, 26, 12, 0, 0, 0, time.UTC),
+				time.Date(2025, 5, 26, 18, 0, 0, 0, time.UTC),
+				time.Date(2025, 5, 27, 0, 0, 0, 0, time.UTC),
+			}))
+			Expect(updated.Status.ParsedScheduleFormat).To(Equal(stablev1.StandardScheduleFormat))
+		})
+
+		It("should evaluate a schedule without timezone in the default timezone", func() {
+			ctx := context.Background()
+			shanghai, err := time.LoadLocation("Asia/Shanghai")
+			Expect(err).NotTo(HaveOccurred())
+			obj := newTestAutoRestartPod("default-timezone", "0 3 * * *")
+			c := newFakeClient(obj)
+			r := newTestReconciler(c, time.Date(2025, 5, 26, 7, 13, 0, 0, time.UTC))
+			r.DefaultTimeZone = shanghai
+
+			_, err = r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			updated := &stablev1.AutoRestartPod{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+			Expect(updated.Status.UpcomingRestartTimes).NotTo(BeEmpty())
+			// 03:00 in Shanghai is 19:00 in UTC the day before
+			Expect(updated.Status.UpcomingRestartTimes[0].UTC()).To(Equal(time.Date(2025, 5, 26, 19, 0, 0, 0, time.UTC)))
+		})
+
+		It("should report a six field expression as parsed with seconds", func() {
+			ctx := context.Background()
+			obj := newTestAutoRestartPod("seconds", "30 */5 * * * *")
+			c := newFakeClient(obj)
+			r := newTestReconciler(c, time.Date(2025, 5, 26, 7, 13, 0, 0, time.UTC))
+
+			_, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			updated := &stablev1.AutoRestartPod{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+			Expect(updated.Status.ParsedScheduleFormat).To(Equal(stablev1.SecondsScheduleFormat))
+			Expect(updated.Status.UpcomingRestartTimes).NotTo(BeEmpty())
+			Expect(updated.Status.UpcomingRestartTimes[0].UTC()).To(Equal(time.Date(2025, 5, 26, 7, 15, 30, 0, time.UTC)))
+		})
+
+		It("should report seconds when any weekday schedule has a seconds field", func() {
+			obj := newTestAutoRestartPod("weekdays", "0 3 * * *")
+			obj.Spec.WeeklySchedules = map[string]string{"MON": "0 0 4 * * *"}
+			Expect(scheduleFormat(obj)).To(Equal(stablev1.SecondsScheduleFormat))
+		})
+	})
+
+	Context("as a descriptor", func() {
+		// nextAfter parses expression and returns its next fire times after from.
+		nextAfter := func(expression string, from time.Time, n int) []time.Time {
+			schedule, err := parseCronSchedule(expression)
+			Expect(err).NotTo(HaveOccurred())
+			return NextFireTimes(schedule, from, n)
+		}
+
+		It("should fire @every on fixed multiples of the interval", func() {
+			from := time.Date(2025, 5, 26, 10, 0, 10, 0, time.UTC)
+			Expect(nextAfter("@every 90s", from, 3)).To(Equal([]time.Time{
+				time.Date(2025, 5, 26, 10, 1, 30, 0, time.UTC),
+				time.Date(2025, 5, 26, 10, 3, 0, 0, time.UTC),
+				time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC),
+			}))
+			// The next fire time does not move with the time it is computed from
+			Expect(nextAfter("@every 90s", from.Add(time.Minute), 1)).To(Equal([]time.Time{
+				time.Date(2025, 5, 26, 10, 1, 30, 0, time.UTC),
+			}))
+			Expect(nextAfter("@every 1h30m", from, 2)).To(Equal([]time.Time{
+				time.Date(2025, 5, 26, 10, 30, 0, 0, time.UTC),
+				time.Date(2025, 5, 26, 12, 0, 0, 0, time.UTC),
+			}))
+		})
+
+		It("should fire the named descriptors at midnight", func() {
+			from := time.Date(2025, 5, 26, 10, 0, 0, 0, time.UTC)
+			Expect(nextAfter("@daily", from, 2)).To(Equal([]time.Time{
+				time.Date(2025, 5, 27, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, 5, 28, 0, 0, 0, 0, time.UTC),
+			}))
+			// 2025-05-26 is a Monday
+			Expect(nextAfter("@weekly", from, 2)).To(Equal([]time.Time{
+				time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, 6, 8, 0, 0, 0, 0, time.UTC),
+			}))
+		})
+
+		It("should read @every with seconds unless it is in whole minutes", func() {
+			for expression, format := range map[string]stablev1.ScheduleFormat{
+				"@every 90s":   stablev1.SecondsScheduleFormat,
+				"@every 1h30m": stablev1.StandardScheduleFormat,
+				"@daily":       stablev1.StandardScheduleFormat,
+				"@weekly":      stablev1.StandardScheduleFormat,
+			} {
+				_, parsed, err := parseCronScheduleFormat(expression)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parsed).To(Equal(format), expression)
+			}
+		})
+
+		It("should not read an invalid descriptor with seconds", func() {
+			_, err := parseCronSchedule("@every 5x")
+			Expect(err).To(MatchError(ContainSubstring("failed to parse duration")))
+			_, err = parseCronSchedule("@fortnightly")
+			Expect(err).To(MatchError(ContainSubstring("unrecognized descriptor")))
+		})
+
+		It("should restart when an @every interval comes due", func() {
+			obj := newTestAutoRestartPod("every", "@every 90s")
+			c := newFakeClient(obj, newTestPod("nginx-0"))
+			fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 0, 10, 0, time.UTC))
+			r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+			result, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, newTestPod("nginx-0"))).To(BeTrue())
+			Expect(result.RequeueAfter).To(Equal(80 * time.Second))
+
+			fakeClock.SetTime(fakeClock.Now().Add(result.RequeueAfter))
+			_, err = r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, newTestPod("nginx-0"))).To(BeFalse())
+		})
+	})
+
+	Context("with seconds", func() {
+		It("should requeue at the exact fire time", func() {
+			obj := newTestAutoRestartPod("seconds", "*/30 * * * * *")
+			c := newFakeClient(obj, newTestPod("nginx-0"))
+			r := newTestReconciler(c, time.Date(2025, 5, 26, 10, 0, 10, 0, time.UTC))
+
+			result, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, newTestPod("nginx-0"))).To(BeTrue())
+			Expect(result.RequeueAfter).To(Equal(20 * time.Second))
+		})
+
+		It("should fire twice within a minute on a 30 second schedule", func() {
+			ctx := context.Background()
+			obj := newTestAutoRestartPod("seconds", "*/30 * * * * *")
+			c := newFakeClient(obj)
+			start := time.Date(2025, 5, 26, 10, 0, 10, 0, time.UTC)
+			fakeClock := testingclock.NewFakePassiveClock(start)
+			r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+			var fires []time.Time
+			for i := 0; i < 10 && fakeClock.Now().Before(start.Add(time.Minute)); i++ {
+				if !podExists(c, newTestPod("nginx-0")) {
+					Expect(c.Create(ctx, newTestPod("nginx-0"))).To(Succeed())
+				}
+				result, err := r.Reconcile(ctx, requestFor(obj))
+				Expect(err).NotTo(HaveOccurred())
+				if !podExists(c, newTestPod("nginx-0")) {
+					fires = append(fires, fakeClock.Now())
+				}
+				Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+				fakeClock.SetTime(fakeClock.Now().Add(result.RequeueAfter))
+			}
+			Expect(fires).To(Equal([]time.Time{
+				time.Date(2025, 5, 26, 10, 0, 30, 0, time.UTC),
+				time.Date(2025, 5, 26, 10, 1, 0, 0, time.UTC),
+			}))
+		})
+	})
+})
@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// secretRotated reports whether the data of the Secret of
+// Spec.RestartOnSecretChange changed since it was last seen, and records
+// its new hash in status. The Secret as first seen only has its hash
+// recorded; a missing Secret is waited for. The Secret is read from the API
+// server since only the metadata of Secrets is cached.
+func (r *AutoRestartPodReconciler) secretRotated(ctx context.Context, obj *stablev1.AutoRestartPod) (bool, error) {
+	ref := obj.Spec.RestartOnSecretChange
+	if ref == nil {
+		return false, nil
+	}
+	log := logf.FromContext(ctx).WithValues("secret", ref.Name)
+
+	secret := &corev1.Secret{}
+	if err := r.apiReader().Get(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: ref.Name}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("Secret restarted on change does not exist")
+			return false, nil
+		}
+		log.Error(err, "Failed to get the Secret restarted on change")
+		return false, err
+	}
+	data, err := json.Marshal(secret.Data)
+	if err != nil {
+		return false, err
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	if hash == obj.Status.ObservedSecretHash {
+		return false, nil
+	}
+
+	first := obj.Status.ObservedSecretHash == ""
+	obj.Status.ObservedSecretHash = hash
+	if first {
+		if err := r.Status().Update(ctx, obj); err != nil {
+			log.Error(err, "Failed to update AutoRestartPod status")
+			return false, err
+		}
+		return false, nil
+	}
+	log.Info("Secret restarted on change was rotated, restarting")
+	return true, nil
+}
+
+// secretToRequests returns a reconcile request for every AutoRestartPod of
+// the Secret's namespace restarted on its changes.
+func (r *AutoRestartPodReconciler) secretToRequests(ctx context.Context, secret client.Object) []reconcile.Request {
+	list := &stablev1.AutoRestartPodList{}
+	if err := r.List(ctx, list, client.InNamespace(secret.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list AutoRestartPods for secret", "secret", secret.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range list.Items {
+		obj := &list.Items[i]
+		if ref := obj.Spec.RestartOnSecretChange; ref != nil && ref.Name == secret.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+		}
+	}
+	return requests
+}
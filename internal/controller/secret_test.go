@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Secret rotation", func() {
+	var (
+		ctx    context.Context
+		obj    *stablev1.AutoRestartPod
+		secret *corev1.Secret
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		// The schedule is far off, only the rotation restarts
+		obj = newTestAutoRestartPod("rotation", "0 3 * * *")
+		obj.Spec.RestartOnSecretChange = &corev1.LocalObjectReference{Name: "tls"}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "default"},
+			Data:       map[string][]byte{"tls.crt": []byte("first")},
+		}
+	})
+
+	It("should restart the pods once the secret is rotated", func() {
+		c := newFakeClient(obj, secret, newTestPod("nginx-0"))
+		r := newTestReconciler(c, time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+
+		// The secret as first seen restarts nothing
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ConsistOf("nginx-0"))
+
+		// Nor does a change of its metadata
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(secret), secret)).To(Succeed())
+		secret.Labels = map[string]string{"team": "web"}
+		Expect(c.Update(ctx, secret)).To(Succeed())
+		_, err = r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ConsistOf("nginx-0"))
+
+		secret.Data["tls.crt"] = []byte("rotated")
+		Expect(c.Update(ctx, secret)).To(Succeed())
+		_, err = r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(BeEmpty())
+
+		// The rotation is only restarted for once
+		Expect(c.Create(ctx, newTestPod("nginx-1"))).To(Succeed())
+		_, err = r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ConsistOf("nginx-1"))
+	})
+
+	It("should wait for a missing secret", func() {
+		c := newFakeClient(obj, newTestPod("nginx-0"))
+		r := newTestReconciler(c, time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Create(ctx, secret)).To(Succeed())
+		_, err = r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ConsistOf("nginx-0"))
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.ObservedSecretHash).NotTo(BeEmpty())
+	})
+
+	It("should read the secret from the API server rather than the cache", func() {
+		c := newFakeClient(obj, newTestPod("nginx-0"))
+		r := newTestReconciler(c, time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		r.APIReader = newFakeClient(secret)
+
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.ObservedSecretHash).NotTo(BeEmpty())
+
+		// So is a Secret restarted on change
+		obj.Spec.RestartOnChangeOf = []stablev1.ObjectReference{{Kind: "Secret", Name: "tls"}}
+		present, err := r.changeHash(ctx, obj)
+		Expect(err).NotTo(HaveOccurred())
+		r.APIReader = newFakeClient()
+		missing, err := r.changeHash(ctx, obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(present).NotTo(Equal(missing))
+	})
+
+	It("should enqueue the objects restarted on changes of a secret", func() {
+		other := newTestAutoRestartPod("other", "0 3 * * *")
+		c := newFakeClient(obj, other)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme()}
+
+		Expect(r.secretToRequests(ctx, secret)).To(ConsistOf(requestFor(obj)))
+		secret.Namespace = "prod"
+		Expect(r.secretToRequests(ctx, secret)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,34 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"slices"
+	"time"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// skipDateLayout is the layout of the dates in Spec.SkipDates, the RFC 3339
+// full-date.
+const skipDateLayout = time.DateOnly
+
+// onSkipDate reports whether the tick at tick falls on one of Spec.SkipDates,
+// in the location of tick.
+func onSkipDate(obj *stablev1.AutoRestartPod, tick time.Time) bool {
+	return slices.Contains(obj.Spec.SkipDates, tick.Format(skipDateLayout))
+}
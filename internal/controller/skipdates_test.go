@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Skip dates", func() {
+	It("should skip scheduled ticks on a skip date", func() {
+		ctx := context.Background()
+		// Daily at 23:30 in Shanghai, 15:30 UTC
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 15, 29, 30, 0, time.UTC))
+		obj := newTestAutoRestartPod("skip", "30 23 * * *")
+		obj.Spec.TimeZone = "Asia/Shanghai"
+		obj.Spec.SkipDates = []string{"2025-05-26", "2025-12-24"}
+		c := newFakeClient(obj)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		updated := &stablev1.AutoRestartPod{}
+		for i, restarts := range []bool{false, true} {
+			pod := newTestPod(fmt.Sprintf("nginx-%d", i))
+			Expect(c.Create(ctx, pod)).To(Succeed())
+
+			_, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, pod)).To(Equal(!restarts), "day %d", i)
+
+			// The skipped tick counts as handled
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+			Expect(updated.Status.LastScheduleTime.Time).To(BeTemporally("==", fakeClock.Now().Add(30*time.Second)))
+			fakeClock.SetTime(fakeClock.Now().Add(24 * time.Hour))
+		}
+	})
+
+	It("should read skip dates in the timezone of the tick", func() {
+		obj := newTestAutoRestartPod("skip", "30 23 * * *")
+		obj.Spec.SkipDates = []string{"2025-05-26"}
+		shanghai, err := time.LoadLocation("Asia/Shanghai")
+		Expect(err).NotTo(HaveOccurred())
+
+		tick := time.Date(2025, 5, 26, 23, 30, 0, 0, shanghai)
+		Expect(onSkipDate(obj, tick)).To(BeTrue())
+		// The same instant is still the 26th in UTC, the next day's tick is not
+		Expect(onSkipDate(obj, tick.UTC())).To(BeTrue())
+		Expect(onSkipDate(obj, tick.Add(24*time.Hour))).To(BeFalse())
+		Expect(onSkipDate(obj, time.Date(2025, 5, 26, 1, 0, 0, 0, shanghai).UTC())).To(BeFalse())
+	})
+})
@@ -0,0 +1,164 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// MetricQuerier evaluates a query against a metrics server and returns its
+// single result.
+type MetricQuerier interface {
+	Query(ctx context.Context, url, query string) (float64, error)
+}
+
+// PrometheusQuerier runs instant queries against the HTTP API of Prometheus.
+// The query must evaluate to a scalar or to a vector with one sample.
+type PrometheusQuerier struct {
+	Client *http.Client
+}
+
+// defaultQueryTimeout bounds a query, see httpClientOrDefault.
+const defaultQueryTimeout = 10 * time.Second
+
+// prometheusResponse is the part of a Prometheus query response the querier reads.
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// Query implements MetricQuerier.
+func (q *PrometheusQuerier) Query(ctx context.Context, server, query string) (float64, error) {
+	endpoint := strings.TrimSuffix(server, "/") + "/api/v1/query?" + url.Values{"query": {query}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := httpClientOrDefault(q.Client, defaultQueryTimeout).Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var body prometheusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("decoding query response: %w", err)
+	}
+	if body.Status != "success" {
+		return 0, fmt.Errorf("query failed with status %s: %s", resp.Status, body.Error)
+	}
+
+	// A sample is a [timestamp, "value"] pair
+	var sample []any
+	switch body.Data.ResultType {
+	case "scalar":
+		if err := json.Unmarshal(body.Data.Result, &sample); err != nil {
+			return 0, err
+		}
+	case "vector":
+		var vector []struct {
+			Value []any `json:"value"`
+		}
+		if err := json.Unmarshal(body.Data.Result, &vector); err != nil {
+			return 0, err
+		}
+		if len(vector) != 1 {
+			return 0, fmt.Errorf("query returned %d samples, expected 1", len(vector))
+		}
+		sample = vector[0].Value
+	default:
+		return 0, fmt.Errorf("unsupported result type %q", body.Data.ResultType)
+	}
+	if len(sample) != 2 {
+		return 0, errors.New("malformed sample in query response")
+	}
+	value, ok := sample[1].(string)
+	if !ok {
+		return 0, errors.New("malformed sample value in query response")
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// slaDeferral returns the reason and message of Spec.SLAGate deferring a
+// restart pass right now, or an empty reason when it allows one. A failing
+// query defers the pass as well, restarts are never made blind.
+func (r *AutoRestartPodReconciler) slaDeferral(ctx context.Context, obj *stablev1.AutoRestartPod) (string, string) {
+	gate := obj.Spec.SLAGate
+	if gate == nil {
+		return "", ""
+	}
+	threshold, err := strconv.ParseFloat(gate.Threshold, 64)
+	if err != nil {
+		return "InvalidThreshold", fmt.Sprintf("invalid threshold %q", gate.Threshold)
+	}
+
+	querier := r.Querier
+	if querier == nil {
+		querier = &PrometheusQuerier{}
+	}
+	value, err := querier.Query(ctx, gate.URL, gate.Query)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to query SLA metric", "url", gate.URL, "query", gate.Query)
+		return "QueryFailed", fmt.Sprintf("query %q failed: %v", gate.Query, err)
+	}
+	if value > threshold {
+		return "MetricAboveThreshold",
+			fmt.Sprintf("query %q returned %g, above the threshold %s", gate.Query, value, gate.Threshold)
+	}
+	return "", ""
+}
+
+// setSLADeferred records in the SLADeferred condition whether the last
+// restart pass was deferred, and why.
+func setSLADeferred(obj *stablev1.AutoRestartPod, reason, message string) {
+	if reason != "" {
+		meta.SetStatusCondition(&obj.Status.Conditions, metav1.Condition{
+			Type:    stablev1.ConditionSLADeferred,
+			Status:  metav1.ConditionTrue,
+			Reason:  reason,
+			Message: message,
+		})
+		return
+	}
+	if meta.FindStatusCondition(obj.Status.Conditions, stablev1.ConditionSLADeferred) != nil {
+		meta.SetStatusCondition(&obj.Status.Conditions, metav1.Condition{
+			Type:    stablev1.ConditionSLADeferred,
+			Status:  metav1.ConditionFalse,
+			Reason:  "WithinThreshold",
+			Message: "the SLA metric allows restarts",
+		})
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// fakeQuerier answers every query with value, or with err when set.
+type fakeQuerier struct {
+	value   float64
+	err     error
+	queries []string
+}
+
+func (q *fakeQuerier) Query(_ context.Context, url, query string) (float64, error) {
+	q.queries = append(q.queries, url+" "+query)
+	return q.value, q.err
+}
+
+var _ = Describe("SLA gate", func() {
+	It("should defer the restart while the metric is above the threshold", func() {
+		ctx := context.Background()
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		obj := newTestAutoRestartPod("sla", "*/5 * * * *")
+		obj.Spec.SLAGate = &stablev1.SLAGateSpec{
+			URL:       "http://prometheus:9090",
+			Query:     "latency_p99",
+			Threshold: "0.5",
+		}
+		pod := newTestPod("nginx-0")
+		c := newFakeClient(obj, pod)
+		querier := &fakeQuerier{value: 0.8}
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock, Querier: querier}
+
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, pod)).To(BeTrue())
+		Expect(querier.queries).To(Equal([]string{"http://prometheus:9090 latency_p99"}))
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.ActiveRun).NotTo(BeNil())
+		cond := meta.FindStatusCondition(updated.Status.Conditions, stablev1.ConditionSLADeferred)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal("MetricAboveThreshold"))
+
+		// The deferred run restarts once the load has dropped, even after the
+		// fire time has passed
+		querier.value = 0.2
+		fakeClock.SetTime(fakeClock.Now().Add(time.Minute))
+		_, err = r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, pod)).To(BeFalse())
+
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.ActiveRun).To(BeNil())
+		Expect(meta.IsStatusConditionFalse(updated.Status.Conditions, stablev1.ConditionSLADeferred)).To(BeTrue())
+	})
+
+	It("should defer the restart while the query fails", func() {
+		ctx := context.Background()
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		obj := newTestAutoRestartPod("sla", "*/5 * * * *")
+		obj.Spec.SLAGate = &stablev1.SLAGateSpec{URL: "http://prometheus:9090", Query: "up", Threshold: "1"}
+		pod := newTestPod("nginx-0")
+		c := newFakeClient(obj, pod)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock,
+			Querier: &fakeQuerier{err: errors.New("connection refused")}}
+
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, pod)).To(BeTrue())
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		cond := meta.FindStatusCondition(updated.Status.Conditions, stablev1.ConditionSLADeferred)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal("QueryFailed"))
+	})
+
+	It("should read the value of a Prometheus query", func() {
+		var response string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			Expect(req.URL.Path).To(Equal("/api/v1/query"))
+			Expect(req.URL.Query().Get("query")).To(Equal(`histogram_quantile(0.99, rate(latency[5m]))`))
+			_, _ = w.Write([]byte(response))
+		}))
+		defer server.Close()
+		q := &PrometheusQuerier{}
+		query := `histogram_quantile(0.99, rate(latency[5m]))`
+
+		response = `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1716717870,"0.75"]}]}}`
+		Expect(q.Query(context.Background(), server.URL, query)).To(Equal(0.75))
+
+		response = `{"status":"success","data":{"resultType":"scalar","result":[1716717870,"0.25"]}}`
+		Expect(q.Query(context.Background(), server.URL+"/", query)).To(Equal(0.25))
+
+		response = `{"status":"success","data":{"resultType":"vector","result":[]}}`
+		_, err := q.Query(context.Background(), server.URL, query)
+		Expect(err).To(MatchError(ContainSubstring("returned 0 samples")))
+
+		response = `{"status":"error","error":"parse error"}`
+		_, err = q.Query(context.Background(), server.URL, query)
+		Expect(err).To(MatchError(ContainSubstring("parse error")))
+	})
+})
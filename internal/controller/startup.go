@@ -0,0 +1,52 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// startupDue reports whether obj asks for Spec.RestartOnStart and has not
+// been restarted for it since this process started. A restart in progress
+// is finished first.
+func (r *AutoRestartPodReconciler) startupDue(obj *stablev1.AutoRestartPod) bool {
+	if !obj.Spec.RestartOnStart || obj.Status.ActiveRun != nil {
+		return false
+	}
+	_, done := r.started.Load(obj.UID)
+	return !done
+}
+
+// startupRestart restarts the pods of obj once for this process, whatever
+// the schedule, and records it in Status.LastStartupRestartTime. A failed
+// restart is retried by the next reconcile.
+func (r *AutoRestartPodReconciler) startupRestart(ctx context.Context, obj *stablev1.AutoRestartPod) (bool, error) {
+	logf.FromContext(ctx).Info("Performing restart on controller start")
+
+	obj.Status.LastStartupRestartTime = &metav1.Time{Time: r.now()}
+	pending, err := r.restartNow(ctx, obj)
+	if err != nil {
+		return false, err
+	}
+	r.started.Store(obj.UID, struct{}{})
+	return pending, nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Restart on controller start", func() {
+	It("should restart once per controller process", func() {
+		ctx := context.Background()
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		// Nothing is due on the daily schedule
+		obj := newTestAutoRestartPod("on-start", "0 3 * * *")
+		obj.UID = "on-start-uid"
+		obj.Spec.RestartOnStart = true
+		c := newFakeClient(obj)
+
+		// reconcileAll reconciles obj three times with r and returns how many
+		// of the passes restarted the pods
+		reconcileAll := func(r *AutoRestartPodReconciler) int {
+			restarts := 0
+			for i := range 3 {
+				pod := newTestPod(fmt.Sprintf("nginx-%d", i))
+				Expect(c.Create(ctx, pod)).To(Succeed())
+
+				_, err := r.Reconcile(ctx, requestFor(obj))
+				Expect(err).NotTo(HaveOccurred())
+				if !podExists(c, pod) {
+					restarts++
+				} else {
+					Expect(c.Delete(ctx, pod)).To(Succeed())
+				}
+				fakeClock.SetTime(fakeClock.Now().Add(time.Minute))
+			}
+			return restarts
+		}
+
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+		Expect(reconcileAll(r)).To(Equal(1))
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.LastStartupRestartTime).NotTo(BeNil())
+		Expect(updated.Status.LastStartupRestartTime.Time).To(BeTemporally("==", time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC)))
+
+		// A fresh controller process restarts once more
+		restarted := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+		Expect(reconcileAll(restarted)).To(Equal(1))
+
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.LastStartupRestartTime.Time).To(BeTemporally("==", time.Date(2025, 5, 26, 10, 7, 30, 0, time.UTC)))
+	})
+
+	It("should not restart on start unless asked to", func() {
+		ctx := context.Background()
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		obj := newTestAutoRestartPod("on-start", "0 3 * * *")
+		pod := newTestPod("nginx-0")
+		c := newFakeClient(obj, pod)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, pod)).To(BeTrue())
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.LastStartupRestartTime).To(BeNil())
+	})
+})
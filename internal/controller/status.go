@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// updateReadiness records the outcome of a reconcile in the Ready condition,
+// and an error that is not transient in the Degraded condition.
+// Status.ObservedGeneration only advances when the reconcile succeeded, so it
+// tells users whether their latest spec change has been processed. Status is
+// only written when something changed.
+func (r *AutoRestartPodReconciler) updateReadiness(ctx context.Context, obj *stablev1.AutoRestartPod,
+	reconcileErr error) error {
+	observed := obj.Generation
+	ready := metav1.Condition{
+		Type:               stablev1.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Reconciled",
+		Message:            "the restart schedule is up to date",
+		ObservedGeneration: obj.Generation,
+	}
+	if reconcileErr != nil {
+		observed = obj.Status.ObservedGeneration
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "ReconcileError"
+		ready.Message = reconcileErr.Error()
+	}
+
+	changed := meta.SetStatusCondition(&obj.Status.Conditions, ready)
+	changed = updateInvalidSchedule(obj, reconcileErr) || changed
+	// Failed restart passes are reported in Degraded by recordPassOutcome
+	degraded := getCondition(obj, stablev1.ConditionDegraded)
+	switch {
+	case reconcileErr != nil && !isTransient(reconcileErr):
+		changed = setCondition(obj, stablev1.ConditionDegraded, metav1.ConditionTrue, "ReconcileError",
+			reconcileErr.Error()) || changed
+	case reconcileErr == nil && degraded != nil && degraded.Reason == "ReconcileError":
+		changed = setCondition(obj, stablev1.ConditionDegraded, metav1.ConditionFalse, "Reconciled",
+			"the last reconcile succeeded") || changed
+	}
+	if !changed && obj.Status.ObservedGeneration == observed {
+		return nil
+	}
+	obj.Status.ObservedGeneration = observed
+	return r.Status().Update(ctx, obj)
+}
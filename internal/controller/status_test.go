@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Observed generation", func() {
+	var (
+		c   client.Client
+		r   *AutoRestartPodReconciler
+		obj *stablev1.AutoRestartPod
+	)
+
+	BeforeEach(func() {
+		obj = newTestAutoRestartPod("observed", "0 3 * * *")
+		obj.Generation = 1
+		c = newFakeClient(obj)
+		r = newTestReconciler(c, time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+	})
+
+	fetch := func() *stablev1.AutoRestartPod {
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		return updated
+	}
+
+	It("should advance with the spec and report Ready for that generation", func() {
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := fetch()
+		Expect(updated.Status.ObservedGeneration).To(Equal(int64(1)))
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, stablev1.ConditionReady)).To(BeTrue())
+
+		By("changing the spec")
+		updated.Spec.Schedule = "0 4 * * *"
+		updated.Generation = 2
+		Expect(c.Update(context.Background(), updated)).To(Succeed())
+
+		_, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+
+		updated = fetch()
+		Expect(updated.Status.ObservedGeneration).To(Equal(int64(2)))
+		ready := meta.FindStatusCondition(updated.Status.Conditions, stablev1.ConditionReady)
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.ObservedGeneration).To(Equal(int64(2)))
+	})
+
+	It("should keep the last good generation when the new spec fails", func() {
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := fetch()
+		updated.Spec.Schedule = "not a schedule"
+		updated.Generation = 2
+		Expect(c.Update(context.Background(), updated)).To(Succeed())
+
+		_, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).To(HaveOccurred())
+
+		updated = fetch()
+		Expect(updated.Status.ObservedGeneration).To(Equal(int64(1)))
+		ready := meta.FindStatusCondition(updated.Status.Conditions, stablev1.ConditionReady)
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready.Reason).To(Equal("ReconcileError"))
+	})
+})
@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// updateSuspended reports Spec.Suspend in the Suspended condition. The
+// condition is only reported False once the object was suspended before.
+func (r *AutoRestartPodReconciler) updateSuspended(ctx context.Context, obj *stablev1.AutoRestartPod) error {
+	var changed bool
+	switch {
+	case obj.Spec.Suspend:
+		changed = setCondition(obj, stablev1.ConditionSuspended, metav1.ConditionTrue, "Suspended",
+			"restarts are suspended")
+	case getCondition(obj, stablev1.ConditionSuspended) != nil:
+		changed = setCondition(obj, stablev1.ConditionSuspended, metav1.ConditionFalse, "Resumed",
+			"restarts are resumed")
+	}
+	if !changed {
+		return nil
+	}
+	if err := r.Status().Update(ctx, obj); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to update AutoRestartPod status")
+		return err
+	}
+	return nil
+}
+
+// activeDeadlineRemaining returns how long Spec.ActiveDeadline still lets
+// obj restart pods at now, zero once it has passed. ok is false without a
+// deadline.
+func activeDeadlineRemaining(obj *stablev1.AutoRestartPod, now time.Time) (remaining time.Duration, ok bool) {
+	deadline := obj.Spec.ActiveDeadline
+	if deadline == nil {
+		return 0, false
+	}
+	return max(obj.CreationTimestamp.Add(deadline.Duration).Sub(now), 0), true
+}
+
+// expire marks obj Completed once Spec.ActiveDeadline has passed.
+func (r *AutoRestartPodReconciler) expire(ctx context.Context, obj *stablev1.AutoRestartPod) error {
+	if !setCondition(obj, stablev1.ConditionCompleted, metav1.ConditionTrue, "Expired",
+		fmt.Sprintf("the active deadline of %s after creation has passed", obj.Spec.ActiveDeadline.Duration)) {
+		return nil
+	}
+	logf.FromContext(ctx).Info("Active deadline passed, restarts stopped",
+		"activeDeadline", obj.Spec.ActiveDeadline.Duration.String())
+	if err := r.Status().Update(ctx, obj); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to update AutoRestartPod status")
+		return err
+	}
+	return nil
+}
+
+// pauseRemaining returns how long Spec.PauseUntil still defers the restarts
+// of obj at now, zero once it has passed.
+func pauseRemaining(obj *stablev1.AutoRestartPod, now time.Time) time.Duration {
+	until := obj.Spec.PauseUntil
+	if until == nil || !now.Before(until.Time) {
+		return 0
+	}
+	return until.Sub(now)
+}
+
+// pauseEnd returns the time right before Spec.PauseUntil when it is after
+// now, so that the next fire time of a schedule after it is at the end of
+// the pause at the earliest, and now otherwise.
+func pauseEnd(obj *stablev1.AutoRestartPod, now time.Time) time.Time {
+	if pauseRemaining(obj, now) == 0 {
+		return now
+	}
+	return obj.Spec.PauseUntil.In(now.Location()).Add(-time.Nanosecond)
+}
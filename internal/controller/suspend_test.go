@@ -0,0 +1,181 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Suspend", func() {
+	It("should not restart while suspended and restart again once resumed", func() {
+		ctx := context.Background()
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 9, 59, 30, 0, time.UTC))
+		obj := newTestAutoRestartPod("suspended", "0 * * * *")
+		obj.Spec.Suspend = true
+		pod := newTestPod("nginx")
+		c := newFakeClient(obj, pod)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		result, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(podExists(c, pod)).To(BeTrue())
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.LastScheduleTime).To(BeNil())
+		cond := getCondition(updated, stablev1.ConditionSuspended)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+
+		// The tick passed while suspended is not caught up
+		fakeClock.SetTime(time.Date(2025, 5, 26, 10, 0, 30, 0, time.UTC))
+		updated.Spec.Suspend = false
+		Expect(c.Update(ctx, updated)).To(Succeed())
+		_, err = r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, pod)).To(BeTrue())
+
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		cond = getCondition(updated, stablev1.ConditionSuspended)
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal("Resumed"))
+
+		fakeClock.SetTime(time.Date(2025, 5, 26, 10, 59, 30, 0, time.UTC))
+		_, err = r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, pod)).To(BeFalse())
+	})
+
+	It("should not report an object that was never suspended", func() {
+		ctx := context.Background()
+		obj := newTestAutoRestartPod("active", "0 * * * *")
+		c := newFakeClient(obj)
+		r := newTestReconciler(c, time.Date(2025, 5, 26, 9, 10, 0, 0, time.UTC))
+
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(getCondition(updated, stablev1.ConditionSuspended)).To(BeNil())
+	})
+})
+
+var _ = Describe("Active deadline", func() {
+	It("should stop restarting and complete once the deadline passed", func() {
+		ctx := context.Background()
+		created := time.Date(2025, 5, 26, 8, 0, 0, 0, time.UTC)
+		fakeClock := testingclock.NewFakePassiveClock(created.Add(59*time.Minute + 30*time.Second))
+		obj := newTestAutoRestartPod("temporary", "0 * * * *")
+		obj.CreationTimestamp = metav1.NewTime(created)
+		obj.Spec.ActiveDeadline = &metav1.Duration{Duration: 2 * time.Hour}
+		c := newFakeClient(obj)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		// tick creates a pod, reconciles at the time and reports
+		// whether the pod was restarted
+		tick := func(now time.Time) (ctrl.Result, bool) {
+			fakeClock.SetTime(now)
+			pod := newTestPod("nginx")
+			Expect(c.Create(ctx, pod)).To(Succeed())
+			result, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			restarted := !podExists(c, pod)
+			if !restarted {
+				Expect(c.Delete(ctx, pod)).To(Succeed())
+			}
+			return result, restarted
+		}
+
+		_, restarted := tick(time.Date(2025, 5, 26, 8, 59, 30, 0, time.UTC))
+		Expect(restarted).To(BeTrue())
+		// The last fire time before the deadline still restarts, and the
+		// controller looks again when the deadline passes
+		result, restarted := tick(time.Date(2025, 5, 26, 9, 59, 30, 0, time.UTC))
+		Expect(restarted).To(BeTrue())
+		Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+
+		result, restarted = tick(time.Date(2025, 5, 26, 10, 59, 30, 0, time.UTC))
+		Expect(restarted).To(BeFalse())
+		Expect(result.RequeueAfter).To(BeZero())
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		completed := getCondition(updated, stablev1.ConditionCompleted)
+		Expect(completed).NotTo(BeNil())
+		Expect(completed.Status).To(Equal(metav1.ConditionTrue))
+		Expect(completed.Reason).To(Equal("Expired"))
+
+		_, restarted = tick(time.Date(2025, 5, 26, 11, 59, 30, 0, time.UTC))
+		Expect(restarted).To(BeFalse())
+	})
+})
+
+var _ = Describe("Pause until", func() {
+	pauseUntil := time.Date(2025, 5, 26, 10, 30, 0, 0, time.UTC)
+
+	It("should not restart before pauseUntil and restart as usual after it", func() {
+		ctx := context.Background()
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 9, 59, 30, 0, time.UTC))
+		obj := newTestAutoRestartPod("paused", "0 * * * *")
+		obj.Spec.PauseUntil = &metav1.Time{Time: pauseUntil}
+		pod := newTestPod("nginx")
+		c := newFakeClient(obj, pod)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		result, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(30*time.Minute + 30*time.Second))
+		Expect(podExists(c, pod)).To(BeTrue())
+
+		// The tick passed while paused is not caught up
+		fakeClock.SetTime(pauseUntil)
+		_, err = r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, pod)).To(BeTrue())
+
+		fakeClock.SetTime(time.Date(2025, 5, 26, 10, 59, 30, 0, time.UTC))
+		_, err = r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, pod)).To(BeFalse())
+	})
+
+	It("should list the first restart after pauseUntil as upcoming", func() {
+		obj := newTestAutoRestartPod("paused", "0 * * * *")
+		obj.Spec.PauseUntil = &metav1.Time{Time: pauseUntil}
+		r := &AutoRestartPodReconciler{
+			Clock: testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 9, 10, 0, 0, time.UTC))}
+
+		restarts := r.nextRestarts(obj)
+		Expect(restarts).To(HaveLen(1))
+		Expect(restarts[0].NextRestartTime).To(Equal(time.Date(2025, 5, 26, 11, 0, 0, 0, time.UTC)))
+
+		// A restart on the hour of pauseUntil is not deferred
+		obj.Spec.PauseUntil = &metav1.Time{Time: time.Date(2025, 5, 26, 11, 0, 0, 0, time.UTC)}
+		restarts = r.nextRestarts(obj)
+		Expect(restarts[0].NextRestartTime).To(Equal(time.Date(2025, 5, 26, 11, 0, 0, 0, time.UTC)))
+	})
+})
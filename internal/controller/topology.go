@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// domainBatch narrows pods down to those of a single failure domain, taken
+// from the topologyKey label of each pod's node, so that two domains are
+// never restarted in the same pass. Domains are handled in name order and
+// keep the order of pods; pods that are not scheduled or whose node lacks the
+// label share the "" domain.
+// More is reported when pods of other domains are left for later passes.
+func (r *AutoRestartPodReconciler) domainBatch(ctx context.Context, pods []corev1.Pod,
+	topologyKey string) (batch []corev1.Pod, more bool, err error) {
+	domains := make(map[string][]corev1.Pod)
+	nodeDomains := make(map[string]string)
+	for _, pod := range pods {
+		domain, ok := nodeDomains[pod.Spec.NodeName]
+		if !ok {
+			domain, err = r.nodeDomain(ctx, pod.Spec.NodeName, topologyKey)
+			if err != nil {
+				return nil, false, err
+			}
+			nodeDomains[pod.Spec.NodeName] = domain
+		}
+		domains[domain] = append(domains[domain], pod)
+	}
+	if len(domains) == 0 {
+		return nil, false, nil
+	}
+
+	names := make([]string, 0, len(domains))
+	for name := range domains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	logf.FromContext(ctx).Info("Restarting failure domain", "topologyKey", topologyKey,
+		"domain", names[0], "pods", len(domains[names[0]]), "domainsLeft", len(names)-1)
+	return domains[names[0]], len(names) > 1, nil
+}
+
+// nodeDomain returns the topologyKey label of the named node, or "" when the
+// pod is not scheduled or the node is gone.
+func (r *AutoRestartPodReconciler) nodeDomain(ctx context.Context, nodeName, topologyKey string) (string, error) {
+	if nodeName == "" {
+		return "", nil
+	}
+	node := &corev1.Node{}
+	if err := r.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		logf.FromContext(ctx).Error(err, "Failed to get node", "node", nodeName)
+		return "", err
+	}
+	return node.Labels[topologyKey], nil
+}
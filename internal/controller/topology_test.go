@@ -0,0 +1,134 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+const zoneKey = "topology.kubernetes.io/zone"
+
+// newZonedPods returns a node per zone and two pods named nginx-<zone>-<i>
+// scheduled on each of them.
+func newZonedPods(now time.Time, zones ...string) []client.Object {
+	var objs []client.Object
+	for _, zone := range zones {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   "node-" + zone,
+			Labels: map[string]string{zoneKey: zone},
+		}}
+		objs = append(objs, node)
+		for i := range 2 {
+			pod := newTestPod(fmt.Sprintf("nginx-%s-%d", zone, i))
+			pod.Spec.NodeName = node.Name
+			pod.CreationTimestamp = metav1.NewTime(now.Add(-time.Duration(2-i) * time.Hour))
+			objs = append(objs, pod)
+		}
+	}
+	return objs
+}
+
+var _ = Describe("Topology batching", func() {
+	var (
+		fakeClock *testingclock.FakePassiveClock
+		obj       *stablev1.AutoRestartPod
+	)
+
+	BeforeEach(func() {
+		fakeClock = testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		obj = newTestAutoRestartPod("zoned", "*/5 * * * *")
+		obj.Spec.TopologyKey = zoneKey
+	})
+
+	It("should restart one zone per pass", func() {
+		c := newFakeClient(append(newZonedPods(fakeClock.Now(), "zone-a", "zone-b", "zone-c"), obj)...)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		result, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(runRequeueInterval))
+		Expect(remainingPods(c)).To(ConsistOf("nginx-zone-b-0", "nginx-zone-b-1", "nginx-zone-c-0", "nginx-zone-c-1"))
+
+		fakeClock.SetTime(fakeClock.Now().Add(runRequeueInterval))
+		result, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(runRequeueInterval))
+		Expect(remainingPods(c)).To(ConsistOf("nginx-zone-c-0", "nginx-zone-c-1"))
+
+		fakeClock.SetTime(fakeClock.Now().Add(runRequeueInterval))
+		_, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(BeEmpty())
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.ActiveRun).To(BeNil())
+	})
+
+	It("should limit the pods restarted per zone", func() {
+		obj.Spec.MaxUnavailablePerDomain = ptr.To[int32](1)
+		c := newFakeClient(append(newZonedPods(fakeClock.Now(), "zone-a", "zone-b", "zone-c"), obj)...)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		var restartedPerPass [][]string
+		previous := remainingPods(c)
+		for range 6 {
+			_, err := r.Reconcile(context.Background(), requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			current := remainingPods(c)
+			var restarted []string
+			for _, name := range previous {
+				if !slices.Contains(current, name) {
+					restarted = append(restarted, name)
+				}
+			}
+			restartedPerPass = append(restartedPerPass, restarted)
+			previous = current
+			fakeClock.SetTime(fakeClock.Now().Add(runRequeueInterval))
+		}
+
+		Expect(restartedPerPass).To(Equal([][]string{
+			{"nginx-zone-a-0"}, {"nginx-zone-a-1"},
+			{"nginx-zone-b-0"}, {"nginx-zone-b-1"},
+			{"nginx-zone-c-0"}, {"nginx-zone-c-1"},
+		}))
+	})
+
+	It("should group unscheduled pods into their own domain", func() {
+		unscheduled := newTestPod("nginx-pending")
+		unscheduled.CreationTimestamp = metav1.NewTime(fakeClock.Now().Add(-time.Hour))
+		c := newFakeClient(append(newZonedPods(fakeClock.Now(), "zone-a"), obj, unscheduled)...)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remainingPods(c)).To(ConsistOf("nginx-zone-a-0", "nginx-zone-a-1"))
+	})
+})
@@ -0,0 +1,55 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName names the tracer of the spans the controller records.
+const tracerName = "github.com/crazyfrankie/autorestart-operator/internal/controller"
+
+// Attributes of the spans the controller records.
+const (
+	// objectKey is the namespace/name of the AutoRestartPod.
+	objectKey = attribute.Key("autorestartpod.key")
+	// podCountKey is the number of pods a span dealt with.
+	podCountKey = attribute.Key("autorestartpod.pods")
+	// podNameKey is the name of the pod a span dealt with.
+	podNameKey = attribute.Key("autorestartpod.pod")
+)
+
+// tracer returns the tracer of TracerProvider, or of the global tracer
+// provider when nil.
+func (r *AutoRestartPodReconciler) tracer() trace.Tracer {
+	if r.TracerProvider == nil {
+		return otel.GetTracerProvider().Tracer(tracerName)
+	}
+	return r.TracerProvider.Tracer(tracerName)
+}
+
+// endSpan marks span as failed when err is set and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
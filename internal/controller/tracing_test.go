@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+var _ = Describe("Tracing", func() {
+	var (
+		exporter *tracetest.InMemoryExporter
+		provider *sdktrace.TracerProvider
+	)
+
+	BeforeEach(func() {
+		exporter = tracetest.NewInMemoryExporter()
+		provider = sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	})
+
+	// attributeValue returns the value of key on span, or an invalid value
+	attributeValue := func(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+		for _, kv := range span.Attributes {
+			if kv.Key == key {
+				return kv.Value
+			}
+		}
+		return attribute.Value{}
+	}
+
+	It("should record a span tree for every reconcile", func() {
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+		obj := newTestAutoRestartPod("traced", "*/5 * * * *")
+		c := newFakeClient(obj, newTestPod("nginx-0"), newTestPod("nginx-1"))
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock, TracerProvider: provider}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+
+		spans := exporter.GetSpans()
+		byName := map[string][]tracetest.SpanStub{}
+		for _, span := range spans {
+			byName[span.Name] = append(byName[span.Name], span)
+		}
+		Expect(byName["Reconcile"]).To(HaveLen(1))
+		root := byName["Reconcile"][0]
+		Expect(root.Parent.IsValid()).To(BeFalse())
+		Expect(attributeValue(root, objectKey).AsString()).To(Equal("default/traced"))
+
+		Expect(byName["ListPods"]).NotTo(BeEmpty())
+		Expect(attributeValue(byName["ListPods"][0], podCountKey).AsInt64()).To(Equal(int64(2)))
+
+		Expect(byName["DeletePod"]).To(HaveLen(2))
+		var deleted []string
+		for _, span := range byName["DeletePod"] {
+			deleted = append(deleted, attributeValue(span, podNameKey).AsString())
+		}
+		Expect(deleted).To(ConsistOf("nginx-0", "nginx-1"))
+
+		Expect(byName["UpdateStatus"]).NotTo(BeEmpty())
+		Expect(attributeValue(byName["UpdateStatus"][0], objectKey).AsString()).To(Equal("default/traced"))
+
+		// Every other span is a child of the reconcile
+		for _, span := range spans {
+			if span.Name == "Reconcile" {
+				continue
+			}
+			Expect(span.Parent.SpanID()).To(Equal(root.SpanContext.SpanID()), span.Name)
+			Expect(span.SpanContext.TraceID()).To(Equal(root.SpanContext.TraceID()), span.Name)
+		}
+	})
+
+	It("should mark the reconcile span of a failed reconcile", func() {
+		obj := newTestAutoRestartPod("traced", "not a schedule")
+		c := newFakeClient(obj)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), TracerProvider: provider}
+
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).To(HaveOccurred())
+
+		var root []tracetest.SpanStub
+		for _, span := range exporter.GetSpans() {
+			if span.Name == "Reconcile" {
+				root = append(root, span)
+			}
+		}
+		Expect(root).To(HaveLen(1))
+		Expect(root[0].Status.Code).To(Equal(codes.Error))
+	})
+})
@@ -0,0 +1,66 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// triggered reports whether Spec.RestartTrigger requests a restart that has
+// not been performed yet. A restart in progress is finished first.
+func triggered(obj *stablev1.AutoRestartPod) bool {
+	return obj.Spec.RestartTrigger != obj.Status.LastTriggeredRestart && obj.Status.ActiveRun == nil
+}
+
+// triggerRestart restarts the pods of obj right away and records the
+// consumed Spec.RestartTrigger. It reports pending when the restart needs
+// further passes, which continue like those of a scheduled restart.
+func (r *AutoRestartPodReconciler) triggerRestart(ctx context.Context, obj *stablev1.AutoRestartPod) (bool, error) {
+	log := logf.FromContext(ctx)
+	log.Info("Performing triggered restart", "restartTrigger", obj.Spec.RestartTrigger)
+
+	obj.Status.LastTriggeredRestart = obj.Spec.RestartTrigger
+	return r.restartNow(ctx, obj)
+}
+
+// restartNow restarts the pods of obj right away, those of every entry of
+// Spec.Restarts when no selector or targetRef is set. It reports pending
+// when the restart needs further passes.
+func (r *AutoRestartPodReconciler) restartNow(ctx context.Context, obj *stablev1.AutoRestartPod) (bool, error) {
+	log := logf.FromContext(ctx)
+
+	now := r.now()
+	if obj.Spec.Selector == nil && obj.Spec.TargetRef == nil && len(obj.Spec.Restarts) > 0 {
+		for i := range obj.Spec.Restarts {
+			pending, err := r.restartRule(ctx, obj, &obj.Spec.Restarts[i], now, now)
+			if err != nil || pending {
+				return pending, err
+			}
+		}
+		return false, nil
+	}
+
+	if err := validateTarget(&obj.Spec); err != nil {
+		log.Error(err, "Invalid restart target")
+		return false, err
+	}
+	return r.restart(ctx, obj, now, now)
+}
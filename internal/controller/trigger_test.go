@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Triggered restarts", func() {
+	var (
+		ctx       context.Context
+		fakeClock *testingclock.FakePassiveClock
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fakeClock = testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+	})
+
+	// setTrigger sets Spec.RestartTrigger of obj to value.
+	setTrigger := func(c client.Client, obj *stablev1.AutoRestartPod, value int64) {
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		updated.Spec.RestartTrigger = value
+		Expect(c.Update(ctx, updated)).To(Succeed())
+	}
+
+	It("should restart once for every increment of the trigger", func() {
+		// Nothing is due on the daily schedule
+		obj := newTestAutoRestartPod("triggered", "0 3 * * *")
+		c := newFakeClient(obj)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		restarts := 0
+		for i, trigger := range []int64{0, 1, 1, 2, 2} {
+			pod := newTestPod(fmt.Sprintf("nginx-%d", i))
+			Expect(c.Create(ctx, pod)).To(Succeed())
+			setTrigger(c, obj, trigger)
+
+			_, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			if !podExists(c, pod) {
+				restarts++
+			} else {
+				Expect(c.Delete(ctx, pod)).To(Succeed())
+			}
+			fakeClock.SetTime(fakeClock.Now().Add(time.Minute))
+		}
+		Expect(restarts).To(Equal(2))
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.LastTriggeredRestart).To(Equal(int64(2)))
+	})
+
+	It("should restart the pods of every rule without a top-level target", func() {
+		obj := newTestAutoRestartPod("triggered-rules", "")
+		obj.Spec.Selector = nil
+		obj.Spec.Restarts = []stablev1.RestartRule{{
+			Name:     "nginx",
+			Schedule: "0 3 * * *",
+			Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}},
+		}}
+		obj.Spec.RestartTrigger = 1
+		pod := newTestPod("nginx-0")
+		c := newFakeClient(obj, pod)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		_, err := r.Reconcile(ctx, requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, pod)).To(BeFalse())
+	})
+
+	It("should not complete a one-time restart that has not fired yet", func() {
+		obj := newTestAutoRestartPod("triggered-at", "")
+		obj.Spec.At = &metav1.Time{Time: fakeClock.Now().Add(time.Hour)}
+		obj.Spec.MaxPodsPerRestart = ptr.To[int32](1)
+		obj.Spec.RestartTrigger = 1
+		c := newFakeClient(append(newAgedPods(2, fakeClock.Now()), obj)...)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		for range 2 {
+			_, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			fakeClock.SetTime(fakeClock.Now().Add(runRequeueInterval))
+		}
+		Expect(remainingPods(c)).To(BeEmpty())
+
+		updated := &stablev1.AutoRestartPod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+		Expect(updated.Status.ActiveRun).To(BeNil())
+		Expect(meta.FindStatusCondition(updated.Status.Conditions, stablev1.ConditionCompleted)).To(BeNil())
+	})
+})
@@ -0,0 +1,200 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// maxSkippedTicks bounds how many ticks on skip dates are passed over
+// looking for the next restart.
+const maxSkippedTicks = 366
+
+// UpcomingRestart is a restart reported by UpcomingRestarts.
+type UpcomingRestart struct {
+	// Namespace and Name identify the AutoRestartPod.
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Rule names the restart rule, empty for the top-level schedule.
+	Rule string `json:"rule,omitempty"`
+	// Schedule is the cron expression of the restart, empty for a one-time
+	// restart.
+	Schedule string `json:"schedule,omitempty"`
+	// NextRestartTime is when the restart is due.
+	NextRestartTime time.Time `json:"nextRestartTime"`
+}
+
+// UpcomingHandler serves the next restart of every AutoRestartPod and of
+// each of its rules as JSON, soonest first, so change-freeze dashboards can
+// tell what is about to restart. The namespace query parameter limits the
+// objects listed and within (a duration such as "1h") the restarts
+// reported.
+func (r *AutoRestartPodReconciler) UpcomingHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		params := req.URL.Query()
+		var within time.Duration
+		if v := params.Get("within"); v != "" {
+			var err error
+			if within, err = time.ParseDuration(v); err != nil {
+				http.Error(w, "invalid within: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		upcoming, err := r.UpcomingRestarts(req.Context(), params.Get("namespace"), within)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(upcoming)
+	})
+}
+
+// UpcomingRestarts returns the next restart of every AutoRestartPod in
+// namespace, or in every namespace when empty, and of each of its rules,
+// soonest first. Restarts further away than within are left out unless
+// within is zero. Suspended objects have no upcoming restarts. The objects
+// are read through r's client, which is the cached client of the manager.
+func (r *AutoRestartPodReconciler) UpcomingRestarts(ctx context.Context, namespace string,
+	within time.Duration) ([]UpcomingRestart, error) {
+	list := &stablev1.AutoRestartPodList{}
+	if err := r.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	now := r.now()
+	upcoming := []UpcomingRestart{}
+	for i := range list.Items {
+		obj := &list.Items[i]
+		if obj.Spec.Suspend {
+			continue
+		}
+		if obj.Spec.PolicyRef != nil {
+			// Objects whose policy is missing are listed with their own fields
+			policy := &stablev1.AutoRestartPolicy{}
+			if err := r.Get(ctx, types.NamespacedName{Name: obj.Spec.PolicyRef.Name}, policy); err == nil {
+				inheritPolicy(&obj.Spec, &policy.Spec)
+			}
+		}
+		for _, restart := range r.nextRestarts(obj) {
+			if within > 0 && restart.NextRestartTime.Sub(now) > within {
+				continue
+			}
+			upcoming = append(upcoming, restart)
+		}
+	}
+	slices.SortStableFunc(upcoming, func(a, b UpcomingRestart) int {
+		return a.NextRestartTime.Compare(b.NextRestartTime)
+	})
+	return upcoming, nil
+}
+
+// nextRestarts returns the next restart of the top-level schedule of obj
+// and of each of its rules. Schedules and timezones that do not parse are
+// left out; reconciling obj reports them.
+func (r *AutoRestartPodReconciler) nextRestarts(obj *stablev1.AutoRestartPod) []UpcomingRestart {
+	var restarts []UpcomingRestart
+	switch {
+	case obj.Spec.At != nil:
+		if !meta.IsStatusConditionTrue(obj.Status.Conditions, stablev1.ConditionCompleted) {
+			// A one-time restart due during a pause happens once it ends
+			next := obj.Spec.At.Time
+			if until := obj.Spec.PauseUntil; until != nil && next.Before(until.Time) {
+				next = until.Time
+			}
+			restarts = append(restarts, UpcomingRestart{
+				Namespace:       obj.Namespace,
+				Name:            obj.Name,
+				NextRestartTime: next,
+			})
+		}
+	case hasRecurringSchedule(obj):
+		schedule, err := topLevelSchedule(obj)
+		if err != nil {
+			break
+		}
+		if obj.Spec.AlignToMidnight {
+			schedule = alignToMidnight(schedule)
+		}
+		schedule = withJitter(obj, schedule)
+		if next, ok := r.nextRestart(obj, schedule, obj.Spec.TimeZone); ok {
+			restarts = append(restarts, UpcomingRestart{
+				Namespace:       obj.Namespace,
+				Name:            obj.Name,
+				Schedule:        effectiveSchedule(obj),
+				NextRestartTime: next,
+			})
+		}
+	}
+	for _, rule := range obj.Spec.Restarts {
+		schedule, err := parseCronSchedule(rule.Schedule)
+		if err != nil {
+			continue
+		}
+		if next, ok := r.nextRestart(obj, schedule, rule.TimeZone); ok {
+			restarts = append(restarts, UpcomingRestart{
+				Namespace:       obj.Namespace,
+				Name:            obj.Name,
+				Rule:            rule.Name,
+				Schedule:        rule.Schedule,
+				NextRestartTime: next,
+			})
+		}
+	}
+	return restarts
+}
+
+// nextRestart returns the next fire time of schedule in timeZone that does
+// not fall on one of the skip dates of obj or outside its time windows.
+func (r *AutoRestartPodReconciler) nextRestart(obj *stablev1.AutoRestartPod, schedule cron.Schedule,
+	timeZone string) (time.Time, bool) {
+	if obj.Spec.AlignToMidnight {
+		schedule = alignToMidnight(schedule)
+	}
+	now, err := r.nowIn(timeZone)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	next := schedule.Next(pauseEnd(obj, now))
+	for range maxSkippedTicks {
+		if next.IsZero() {
+			break
+		}
+		inWindow, err := inTimeWindow(obj, next)
+		if err != nil {
+			return time.Time{}, false
+		}
+		if inWindow && !onSkipDate(obj, next) {
+			return next, true
+		}
+		next = schedule.Next(next)
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Upcoming restarts", func() {
+	var r *AutoRestartPodReconciler
+
+	BeforeEach(func() {
+		fakeClock := testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+
+		daily := newTestAutoRestartPod("daily", "0 3 * * *")
+		hourly := newTestAutoRestartPod("hourly", "30 * * * *")
+		rules := newTestAutoRestartPod("rules", "")
+		rules.Spec.Restarts = []stablev1.RestartRule{{Name: "web", Schedule: "5 11 * * *"}}
+		skipping := newTestAutoRestartPod("skipping", "0 12 * * *")
+		skipping.Spec.SkipDates = []string{"2025-05-26"}
+		completed := newTestAutoRestartPod("completed", "")
+		completed.Spec.At = &metav1.Time{Time: time.Date(2025, 5, 26, 9, 0, 0, 0, time.UTC)}
+		completed.Status.Conditions = []metav1.Condition{{
+			Type: stablev1.ConditionCompleted, Status: metav1.ConditionTrue, Reason: "Restarted",
+			LastTransitionTime: metav1.Now(),
+		}}
+		// Suspended objects are left out
+		suspended := newTestAutoRestartPod("suspended", "0 * * * *")
+		suspended.Spec.Suspend = true
+		once := newTestAutoRestartPod("once", "")
+		once.Namespace = "other"
+		once.Spec.At = &metav1.Time{Time: time.Date(2025, 5, 26, 12, 0, 0, 0, time.UTC)}
+
+		c := newFakeClient(daily, hourly, rules, skipping, completed, suspended, once)
+		r = &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+	})
+
+	// get serves target with the handler and decodes the upcoming restarts
+	get := func(target string) []UpcomingRestart {
+		rec := httptest.NewRecorder()
+		r.UpcomingHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Type")).To(Equal("application/json"))
+
+		var upcoming []UpcomingRestart
+		Expect(json.Unmarshal(rec.Body.Bytes(), &upcoming)).To(Succeed())
+		return upcoming
+	}
+
+	It("should list the next restart of every object soonest first", func() {
+		Expect(get("/upcoming")).To(Equal([]UpcomingRestart{
+			{Namespace: "default", Name: "hourly", Schedule: "30 * * * *",
+				NextRestartTime: time.Date(2025, 5, 26, 10, 30, 0, 0, time.UTC)},
+			{Namespace: "default", Name: "rules", Rule: "web", Schedule: "5 11 * * *",
+				NextRestartTime: time.Date(2025, 5, 26, 11, 5, 0, 0, time.UTC)},
+			{Namespace: "other", Name: "once",
+				NextRestartTime: time.Date(2025, 5, 26, 12, 0, 0, 0, time.UTC)},
+			{Namespace: "default", Name: "daily", Schedule: "0 3 * * *",
+				NextRestartTime: time.Date(2025, 5, 27, 3, 0, 0, 0, time.UTC)},
+			// The tick on the skip date is passed over
+			{Namespace: "default", Name: "skipping", Schedule: "0 12 * * *",
+				NextRestartTime: time.Date(2025, 5, 27, 12, 0, 0, 0, time.UTC)},
+		}))
+	})
+
+	It("should only list restarts due within the given duration", func() {
+		upcoming := get("/upcoming?within=1h")
+		Expect(upcoming).To(HaveLen(1))
+		Expect(upcoming[0].Name).To(Equal("hourly"))
+	})
+
+	It("should only list objects of the given namespace", func() {
+		upcoming := get("/upcoming?namespace=other")
+		Expect(upcoming).To(HaveLen(1))
+		Expect(upcoming[0].Name).To(Equal("once"))
+	})
+
+	It("should reject an invalid duration", func() {
+		rec := httptest.NewRecorder()
+		r.UpcomingHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/upcoming?within=soon", nil))
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+	})
+})
@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// These specs exercise the CEL rules of the CRD and need the envtest API server.
+var _ = Describe("AutoRestartPod API server validation", func() {
+	ctx := context.Background()
+
+	newResource := func(name string) *stablev1.AutoRestartPod {
+		return &stablev1.AutoRestartPod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: stablev1.AutoRestartPodSpec{
+				Schedule: "*/5 * * * *",
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}},
+			},
+		}
+	}
+
+	expectRejected := func(obj *stablev1.AutoRestartPod, message string) {
+		err := k8sClient.Create(ctx, obj)
+		Expect(errors.IsInvalid(err)).To(BeTrue(), "expected an Invalid error, got %v", err)
+		Expect(err.Error()).To(ContainSubstring(message))
+	}
+
+	It("should accept a valid resource", func() {
+		obj := newResource("valid")
+		Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, obj)).To(Succeed())
+	})
+
+	It("should accept restart rules without a top-level schedule", func() {
+		obj := newResource("rules-only")
+		obj.Spec.Schedule = ""
+		obj.Spec.Selector = nil
+		obj.Spec.Restarts = []stablev1.RestartRule{{
+			Name:     "web",
+			Schedule: "5 * * * *",
+			Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		}}
+		Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, obj)).To(Succeed())
+	})
+
+	It("should reject a resource without a schedule", func() {
+		obj := newResource("no-schedule")
+		obj.Spec.Schedule = ""
+		expectRejected(obj, "schedule must be set unless weekly, weeklySchedules, at, restarts or policyRef are set")
+	})
+
+	It("should accept a one-time restart without a schedule", func() {
+		obj := newResource("one-time")
+		obj.Spec.Schedule = ""
+		obj.Spec.At = &metav1.Time{Time: time.Date(2025, 5, 26, 10, 30, 0, 0, time.UTC)}
+		Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, obj)).To(Succeed())
+	})
+
+	It("should reject both a schedule and at", func() {
+		obj := newResource("schedule-and-at")
+		obj.Spec.At = &metav1.Time{Time: time.Date(2025, 5, 26, 10, 30, 0, 0, time.UTC)}
+		expectRejected(obj, "only one of schedule and at may be set")
+	})
+
+	It("should reject both a selector and a targetRef", func() {
+		obj := newResource("both-targets")
+		obj.Spec.TargetRef = &stablev1.TargetReference{Kind: "Deployment", Name: "web"}
+		expectRejected(obj, "only one of selector and targetRef may be set")
+	})
+
+	It("should reject a schedule without a target", func() {
+		obj := newResource("no-target")
+		obj.Spec.Selector = nil
+		expectRejected(obj, "one of selector and targetRef must be set")
+	})
+
+	It("should reject maxUnavailablePerDomain without a topologyKey", func() {
+		obj := newResource("no-topology")
+		obj.Spec.MaxUnavailablePerDomain = ptr.To[int32](1)
+		expectRejected(obj, "maxUnavailablePerDomain requires topologyKey")
+	})
+
+	It("should reject a restart budget without a window", func() {
+		obj := newResource("no-window")
+		obj.Spec.MaxRestartsPerWindow = ptr.To[int32](3)
+		expectRejected(obj, "maxRestartsPerWindow and window must be set together")
+	})
+})
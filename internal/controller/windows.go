@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// inTimeWindow reports whether the tick at tick falls in one of
+// Spec.TimeWindows. Each window is read in its own timezone, falling back to
+// the location of tick.
+func inTimeWindow(obj *stablev1.AutoRestartPod, tick time.Time) (bool, error) {
+	if len(obj.Spec.TimeWindows) == 0 {
+		return true, nil
+	}
+	for _, window := range obj.Spec.TimeWindows {
+		in, err := windowContains(window, tick)
+		if err != nil {
+			return false, err
+		}
+		if in {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// windowContains reports whether t falls in window.
+func windowContains(window stablev1.TimeWindow, t time.Time) (bool, error) {
+	if window.TimeZone != "" {
+		loc, err := time.LoadLocation(window.TimeZone)
+		if err != nil {
+			return false, fmt.Errorf("invalid timezone %q of time window: %w", window.TimeZone, err)
+		}
+		t = t.In(loc)
+	}
+	start, err := minuteOfDay(window.Start)
+	if err != nil {
+		return false, err
+	}
+	end, err := minuteOfDay(window.End)
+	if err != nil {
+		return false, err
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	if start <= end {
+		return start <= minute && minute < end, nil
+	}
+	// The window spans midnight
+	return minute >= start || minute < end, nil
+}
+
+// minuteOfDay parses an "HH:MM" time of day into minutes since midnight.
+func minuteOfDay(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, must be HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
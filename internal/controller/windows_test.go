@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("Time windows", func() {
+	var obj *stablev1.AutoRestartPod
+
+	BeforeEach(func() {
+		obj = newTestAutoRestartPod("windows", "0 * * * *")
+		// 18:00-20:00 and 05:00-07:00 UTC in May
+		obj.Spec.TimeWindows = []stablev1.TimeWindow{
+			{Start: "02:00", End: "04:00", TimeZone: "Asia/Shanghai"},
+			{Start: "01:00", End: "03:00", TimeZone: "America/New_York"},
+		}
+	})
+
+	It("should read every window in its own timezone", func() {
+		for hour, in := range map[int]bool{
+			17: false, 18: true, 19: true, 20: false,
+			4: false, 5: true, 6: true, 7: false, 10: false,
+		} {
+			tick := time.Date(2025, 5, 26, hour, 0, 0, 0, time.UTC)
+			Expect(inTimeWindow(obj, tick)).To(Equal(in), "%02d:00 UTC", hour)
+		}
+	})
+
+	It("should fall back to the location of the tick", func() {
+		shanghai, err := time.LoadLocation("Asia/Shanghai")
+		Expect(err).NotTo(HaveOccurred())
+		obj.Spec.TimeWindows = []stablev1.TimeWindow{{Start: "22:00", End: "02:00"}}
+
+		// The window spans midnight
+		Expect(inTimeWindow(obj, time.Date(2025, 5, 26, 23, 0, 0, 0, shanghai))).To(BeTrue())
+		Expect(inTimeWindow(obj, time.Date(2025, 5, 26, 1, 59, 0, 0, shanghai))).To(BeTrue())
+		Expect(inTimeWindow(obj, time.Date(2025, 5, 26, 2, 0, 0, 0, shanghai))).To(BeFalse())
+		// 23:00 in Shanghai is 15:00 in UTC
+		Expect(inTimeWindow(obj, time.Date(2025, 5, 26, 23, 0, 0, 0, shanghai).UTC())).To(BeFalse())
+	})
+
+	It("should allow every tick without windows", func() {
+		obj.Spec.TimeWindows = nil
+		Expect(inTimeWindow(obj, time.Now())).To(BeTrue())
+	})
+
+	It("should reject an unknown timezone", func() {
+		obj.Spec.TimeWindows[1].TimeZone = "Mars/Olympus"
+		_, err := inTimeWindow(obj, time.Date(2025, 5, 26, 10, 0, 0, 0, time.UTC))
+		Expect(err).To(MatchError(ContainSubstring("Mars/Olympus")))
+	})
+
+	It("should only restart on ticks inside a window", func() {
+		ctx := context.Background()
+		fakeClock := testingclock.NewFakePassiveClock(time.Time{})
+		c := newFakeClient(obj)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		updated := &stablev1.AutoRestartPod{}
+		for i, tick := range []struct {
+			hour     int
+			restarts bool
+		}{{4, false}, {6, true}, {10, false}, {18, true}} {
+			fakeClock.SetTime(time.Date(2025, 5, 26, tick.hour-1, 59, 30, 0, time.UTC))
+			pod := newTestPod(fmt.Sprintf("nginx-%d", i))
+			Expect(c.Create(ctx, pod)).To(Succeed())
+
+			_, err := r.Reconcile(ctx, requestFor(obj))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podExists(c, pod)).To(Equal(!tick.restarts), "%02d:00 UTC", tick.hour)
+
+			// A tick outside the windows counts as handled
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+			Expect(updated.Status.LastScheduleTime.Time).To(BeTemporally("==", fakeClock.Now().Add(30*time.Second)))
+		}
+	})
+})
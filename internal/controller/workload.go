@@ -0,0 +1,296 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// restartedAtAnnotation is the pod template annotation `kubectl rollout restart` sets.
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// restartAnnotationKey returns the annotation obj stamps pod templates with
+// the restart time, restartedAtAnnotation unless Spec.RestartAnnotationKey
+// names another.
+func restartAnnotationKey(obj *stablev1.AutoRestartPod) string {
+	if obj.Spec.RestartAnnotationKey != "" {
+		return obj.Spec.RestartAnnotationKey
+	}
+	return restartedAtAnnotation
+}
+
+// restartCountAnnotation numbers the rollout restarts of a workload's pod
+// template, so tooling can tell two restarts apart even within one second.
+const restartCountAnnotation = "autorestart.crazyfrank.com/restart-count"
+
+// lastRestartByAnnotation names the AutoRestartPod that last restarted a
+// workload, and lastRestartAtAnnotation when, so those debugging the
+// workload can tell who restarted it.
+const (
+	lastRestartByAnnotation = "autorestart.crazyfrank.com/last-restart-by"
+	lastRestartAtAnnotation = "autorestart.crazyfrank.com/last-restart-at"
+)
+
+// validateTarget checks that exactly one of Selector and TargetRef is set.
+func validateTarget(spec *stablev1.AutoRestartPodSpec) error {
+	switch {
+	case spec.Selector != nil && spec.TargetRef != nil:
+		return errors.New("only one of selector and targetRef may be set")
+	case spec.Selector == nil && spec.TargetRef == nil:
+		return errors.New("one of selector and targetRef must be set")
+	}
+	return nil
+}
+
+// newWorkload returns an empty object for a supported TargetReference kind.
+func newWorkload(kind string) (client.Object, error) {
+	switch kind {
+	case "Deployment":
+		return &appsv1.Deployment{}, nil
+	case "StatefulSet":
+		return &appsv1.StatefulSet{}, nil
+	}
+	return nil, fmt.Errorf("unsupported target kind %q", kind)
+}
+
+// workloadSelector returns the pod selector of a workload.
+func workloadSelector(workload client.Object) *metav1.LabelSelector {
+	switch w := workload.(type) {
+	case *appsv1.Deployment:
+		return w.Spec.Selector
+	case *appsv1.StatefulSet:
+		return w.Spec.Selector
+	}
+	return nil
+}
+
+// podTemplate returns the pod template of a workload.
+func podTemplate(workload client.Object) *corev1.PodTemplateSpec {
+	switch w := workload.(type) {
+	case *appsv1.Deployment:
+		return &w.Spec.Template
+	case *appsv1.StatefulSet:
+		return &w.Spec.Template
+	}
+	return nil
+}
+
+// workloadName returns a "Kind/name" label for a workload used in logs and status.
+func workloadName(workload client.Object) string {
+	switch workload.(type) {
+	case *appsv1.Deployment:
+		return "Deployment/" + workload.GetName()
+	case *appsv1.StatefulSet:
+		return "StatefulSet/" + workload.GetName()
+	}
+	return workload.GetName()
+}
+
+// restartedKinds returns the distinct kinds of the restarted objects in name
+// order. Workloads are named "Kind/name" by workloadName; pod names cannot
+// contain a slash.
+func restartedKinds(restarted []string) []string {
+	var kinds []string
+	for _, name := range restarted {
+		kind := "Pod"
+		if i := strings.Index(name, "/"); i >= 0 {
+			kind = name[:i]
+		}
+		if !slices.Contains(kinds, kind) {
+			kinds = append(kinds, kind)
+		}
+	}
+	slices.Sort(kinds)
+	return kinds
+}
+
+// restartedStrategy returns the strategy that restarted the objects in
+// restarted, named as in restartedKinds: RolloutRestart when a workload is
+// among them, otherwise the strategy that restarts pods directly.
+func restartedStrategy(obj *stablev1.AutoRestartPod, restarted []string) stablev1.RestartStrategy {
+	if slices.ContainsFunc(restarted, func(name string) bool { return strings.Contains(name, "/") }) {
+		return stablev1.RolloutRestartStrategy
+	}
+	if obj.Spec.Strategy == stablev1.EvictStrategy {
+		return stablev1.EvictStrategy
+	}
+	return stablev1.DeleteStrategy
+}
+
+// resolveTargetRef fetches the workload referenced by Spec.TargetRef and
+// derives the selector of the pods it manages.
+func (r *AutoRestartPodReconciler) resolveTargetRef(ctx context.Context,
+	obj *stablev1.AutoRestartPod) (client.Object, labels.Selector, error) {
+	ref := obj.Spec.TargetRef
+	workload, err := newWorkload(ref.Kind)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: ref.Name}, workload); err != nil {
+		return nil, nil, err
+	}
+
+	labelSelector := workloadSelector(workload)
+	if labelSelector == nil {
+		return nil, nil, fmt.Errorf("%s has no pod selector", workloadName(workload))
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, nil, err
+	}
+	return workload, selector, nil
+}
+
+// rolloutRestart stamps the workload's pod template with the restart time,
+// under restartAnnotationKey, and the next restart count so that its
+// controller replaces every pod with a rolling update. The workload itself
+// is annotated with obj and the restart time. A paused Deployment is only restarted when
+// Spec.ResumePaused allows resuming it, and errWorkloadPaused is returned
+// otherwise.
+func (r *AutoRestartPodReconciler) rolloutRestart(ctx context.Context, obj *stablev1.AutoRestartPod,
+	workload client.Object, now time.Time) error {
+	// The optimistic lock keeps the counter from being incremented twice
+	// from the same version of the workload
+	patch := client.MergeFromWithOptions(workload.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+	if isPaused(workload) {
+		if !obj.Spec.ResumePaused {
+			return errWorkloadPaused
+		}
+		// The Deployment rolls out the restart once resumed and is paused
+		// again by repauseWorkloads when every replica has been updated
+		deploy := workload.(*appsv1.Deployment)
+		deploy.Spec.Paused = false
+		metav1.SetMetaDataAnnotation(&deploy.ObjectMeta, resumedByAnnotation, resumedBy(obj))
+	}
+	template := podTemplate(workload)
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	// An unparsable counter starts over
+	count, _ := strconv.ParseInt(template.Annotations[restartCountAnnotation], 10, 64)
+	template.Annotations[restartCountAnnotation] = strconv.FormatInt(count+1, 10)
+	template.Annotations[restartAnnotationKey(obj)] = now.Format(time.RFC3339)
+	annotations := workload.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[lastRestartByAnnotation] = obj.Name
+	annotations[lastRestartAtAnnotation] = now.Format(time.RFC3339)
+	workload.SetAnnotations(annotations)
+	return r.Patch(ctx, workload, patch)
+}
+
+// ownerWorkload returns the Deployment or StatefulSet that controls pod,
+// or nil when the pod has no such owner.
+func (r *AutoRestartPodReconciler) ownerWorkload(ctx context.Context, pod *corev1.Pod) (client.Object, error) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return nil, nil
+	}
+
+	switch owner.Kind {
+	case "StatefulSet":
+		sts := &appsv1.StatefulSet{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: owner.Name}, sts); err != nil {
+			return nil, err
+		}
+		return sts, nil
+	case "ReplicaSet":
+		// Deployments own their pods through a ReplicaSet
+		rs := &appsv1.ReplicaSet{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: owner.Name}, rs); err != nil {
+			return nil, err
+		}
+		rsOwner := metav1.GetControllerOf(rs)
+		if rsOwner == nil || rsOwner.Kind != "Deployment" {
+			return nil, nil
+		}
+		deploy := &appsv1.Deployment{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: rsOwner.Name}, deploy); err != nil {
+			return nil, err
+		}
+		return deploy, nil
+	}
+	return nil, nil
+}
+
+// rolloutRestartOwners rollout-restarts every distinct workload owning one of
+// pods. Pods without a restartable owner fall back to being deleted. Paused
+// workloads that could not be restarted are returned separately. It stops
+// early once ctx is cancelled.
+func (r *AutoRestartPodReconciler) rolloutRestartOwners(ctx context.Context, obj *stablev1.AutoRestartPod,
+	pods []corev1.Pod, now time.Time) (results podResults, paused []string) {
+	log := logf.FromContext(ctx)
+
+	seen := map[string]bool{}
+	var bare []corev1.Pod
+	for i := range pods {
+		// A manager shutting down restarts no more workloads
+		if ctx.Err() != nil {
+			return results, paused
+		}
+		workload, err := r.ownerWorkload(ctx, &pods[i])
+		if err != nil {
+			log.Error(err, "Failed to resolve pod owner", "pod", pods[i].Name)
+			results.add(pods[i].Name, err)
+			continue
+		}
+		if workload == nil {
+			bare = append(bare, pods[i])
+			continue
+		}
+
+		name := workloadName(workload)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if err := r.rolloutRestart(ctx, obj, workload, now); errors.Is(err, errWorkloadPaused) {
+			log.Info("Skipping paused workload", "workload", name)
+			paused = append(paused, name)
+		} else if err != nil {
+			log.Error(err, "Failed to rollout restart workload", "workload", name)
+			r.recordFailure()
+			results.add(name, err)
+		} else {
+			log.Info("Restarted workload", "workload", name)
+			results.add(name, nil)
+			r.receipt(workload, obj, "rollout restarted")
+		}
+	}
+
+	if len(bare) > 0 {
+		results = append(results, r.deletePods(ctx, obj, bare)...)
+	}
+	return results, paused
+}
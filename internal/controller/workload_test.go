@@ -0,0 +1,402 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// newTestDeployment returns a Deployment in the default namespace selecting app=<name>.
+func newTestDeployment(name string) *appsv1.Deployment {
+	labels := map[string]string{"app": name}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("deploy-" + name)},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: labels}},
+		},
+	}
+}
+
+// newTestStatefulSet returns a StatefulSet in the default namespace selecting app=<name>.
+func newTestStatefulSet(name string) *appsv1.StatefulSet {
+	labels := map[string]string{"app": name}
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("sts-" + name)},
+		Spec: appsv1.StatefulSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: labels}},
+		},
+	}
+}
+
+// newOwnedPod returns a pod labeled app=<app> controlled by owner.
+func newOwnedPod(name, app string, owner client.Object, kind string) *corev1.Pod {
+	pod := newTestPod(name)
+	pod.Labels = map[string]string{"app": app}
+	pod.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: "apps/v1",
+		Kind:       kind,
+		Name:       owner.GetName(),
+		UID:        owner.GetUID(),
+		Controller: ptr.To(true),
+	}}
+	return pod
+}
+
+// targetKinds returns Status.TargetKinds of obj as stored by c.
+func targetKinds(c client.Client, obj *stablev1.AutoRestartPod) []string {
+	updated := &stablev1.AutoRestartPod{}
+	Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+	return updated.Status.TargetKinds
+}
+
+// lastRestartStrategy returns Status.LastRestartStrategy of obj as stored by c.
+func lastRestartStrategy(c client.Client, obj *stablev1.AutoRestartPod) stablev1.RestartStrategy {
+	updated := &stablev1.AutoRestartPod{}
+	Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), updated)).To(Succeed())
+	return updated.Status.LastRestartStrategy
+}
+
+var _ = Describe("Workload targets", func() {
+	var fakeClock *testingclock.FakePassiveClock
+
+	BeforeEach(func() {
+		fakeClock = testingclock.NewFakePassiveClock(time.Date(2025, 5, 26, 10, 4, 30, 0, time.UTC))
+	})
+
+	newTargetedAutoRestartPod := func(kind, name string) *stablev1.AutoRestartPod {
+		obj := newTestAutoRestartPod("targeted", "*/5 * * * *")
+		obj.Spec.Selector = nil
+		obj.Spec.TargetRef = &stablev1.TargetReference{Kind: kind, Name: name}
+		return obj
+	}
+
+	It("should restart the pods of a referenced Deployment", func() {
+		deploy := newTestDeployment("web")
+		obj := newTargetedAutoRestartPod("Deployment", "web")
+		web := newTestPod("web-0")
+		web.Labels = map[string]string{"app": "web"}
+		other := newTestPod("nginx-0")
+
+		c := newFakeClient(obj, deploy, web, other)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, web)).To(BeFalse())
+		Expect(podExists(c, other)).To(BeTrue())
+	})
+
+	It("should restart the pods of a referenced StatefulSet", func() {
+		sts := newTestStatefulSet("db")
+		obj := newTargetedAutoRestartPod("StatefulSet", "db")
+		db := newTestPod("db-0")
+		db.Labels = map[string]string{"app": "db"}
+		other := newTestPod("nginx-0")
+
+		c := newFakeClient(obj, sts, db, other)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, db)).To(BeFalse())
+		Expect(podExists(c, other)).To(BeTrue())
+		Expect(targetKinds(c, obj)).To(Equal([]string{"Pod"}))
+	})
+
+	It("should patch the referenced workload for RolloutRestart", func() {
+		deploy := newTestDeployment("web")
+		obj := newTargetedAutoRestartPod("Deployment", "web")
+		obj.Spec.Strategy = stablev1.RolloutRestartStrategy
+		web := newTestPod("web-0")
+		web.Labels = map[string]string{"app": "web"}
+
+		c := newFakeClient(obj, deploy, web)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &appsv1.Deployment{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(deploy), updated)).To(Succeed())
+		Expect(updated.Spec.Template.Annotations).To(HaveKeyWithValue(restartedAtAnnotation, "2025-05-26T10:04:30Z"))
+		Expect(updated.Annotations).To(HaveKeyWithValue(lastRestartByAnnotation, "targeted"))
+		Expect(updated.Annotations).To(HaveKeyWithValue(lastRestartAtAnnotation, "2025-05-26T10:04:30Z"))
+		Expect(podExists(c, web)).To(BeTrue(), "the rollout replaces pods, the controller does not delete them")
+		Expect(targetKinds(c, obj)).To(Equal([]string{"Deployment"}))
+	})
+
+	It("should set a configured restart annotation key for RolloutRestart", func() {
+		deploy := newTestDeployment("web")
+		obj := newTargetedAutoRestartPod("Deployment", "web")
+		obj.Spec.Strategy = stablev1.RolloutRestartStrategy
+		obj.Spec.RestartAnnotationKey = "deploy.example.com/restarted-at"
+		web := newTestPod("web-0")
+		web.Labels = map[string]string{"app": "web"}
+
+		c := newFakeClient(obj, deploy, web)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &appsv1.Deployment{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(deploy), updated)).To(Succeed())
+		Expect(updated.Spec.Template.Annotations).To(HaveKeyWithValue("deploy.example.com/restarted-at",
+			"2025-05-26T10:04:30Z"))
+		Expect(updated.Spec.Template.Annotations).NotTo(HaveKey(restartedAtAnnotation))
+	})
+
+	It("should count every rollout restart of a workload", func() {
+		deploy := newTestDeployment("web")
+		obj := newTargetedAutoRestartPod("Deployment", "web")
+		obj.Spec.Strategy = stablev1.RolloutRestartStrategy
+
+		c := newFakeClient(obj, deploy)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		updated := &appsv1.Deployment{}
+		for _, expected := range []string{"1", "2", "3"} {
+			Expect(c.Get(context.Background(), client.ObjectKeyFromObject(deploy), updated)).To(Succeed())
+			// Restarts within the same second still get distinct markers
+			Expect(r.rolloutRestart(context.Background(), obj, updated, fakeClock.Now())).To(Succeed())
+
+			Expect(c.Get(context.Background(), client.ObjectKeyFromObject(deploy), updated)).To(Succeed())
+			Expect(updated.Spec.Template.Annotations).To(HaveKeyWithValue(restartCountAnnotation, expected))
+		}
+	})
+
+	It("should not increment the count from a stale copy of the workload", func() {
+		deploy := newTestDeployment("web")
+		obj := newTargetedAutoRestartPod("Deployment", "web")
+		c := newFakeClient(deploy)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+
+		stale := &appsv1.Deployment{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(deploy), stale)).To(Succeed())
+		fresh := stale.DeepCopy()
+		Expect(r.rolloutRestart(context.Background(), obj, fresh, fakeClock.Now())).To(Succeed())
+
+		err := r.rolloutRestart(context.Background(), obj, stale, fakeClock.Now())
+		Expect(apierrors.IsConflict(err)).To(BeTrue())
+	})
+
+	It("should skip a paused Deployment and report it", func() {
+		deploy := newTestDeployment("web")
+		deploy.Spec.Paused = true
+		obj := newTargetedAutoRestartPod("Deployment", "web")
+		obj.Spec.Strategy = stablev1.RolloutRestartStrategy
+
+		c := newFakeClient(obj, deploy)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &appsv1.Deployment{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(deploy), updated)).To(Succeed())
+		Expect(updated.Spec.Template.Annotations).NotTo(HaveKey(restartedAtAnnotation))
+		Expect(updated.Spec.Paused).To(BeTrue())
+
+		status := &stablev1.AutoRestartPod{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(obj), status)).To(Succeed())
+		condition := meta.FindStatusCondition(status.Status.Conditions, stablev1.ConditionWorkloadPaused)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring("Deployment/web"))
+	})
+
+	It("should resume a paused Deployment for the restart and pause it again once rolled out", func() {
+		deploy := newTestDeployment("web")
+		deploy.Spec.Paused = true
+		deploy.Generation = 2
+		deploy.Status.ObservedGeneration = 1
+		obj := newTargetedAutoRestartPod("Deployment", "web")
+		obj.Spec.Strategy = stablev1.RolloutRestartStrategy
+		obj.Spec.ResumePaused = true
+
+		c := newFakeClient(obj, deploy)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &appsv1.Deployment{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(deploy), updated)).To(Succeed())
+		Expect(updated.Spec.Template.Annotations).To(HaveKey(restartedAtAnnotation))
+		Expect(updated.Spec.Paused).To(BeFalse())
+		Expect(updated.Annotations).To(HaveKeyWithValue(resumedByAnnotation, "default/targeted"))
+
+		// The Deployment stays resumed until its controller has observed the restart
+		result, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(runRequeueInterval))
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(deploy), updated)).To(Succeed())
+		Expect(updated.Spec.Paused).To(BeFalse())
+
+		// Observed is not rolled out while an old replica is still running
+		updated.Status.ObservedGeneration = 2
+		updated.Status.Replicas = 2
+		updated.Status.UpdatedReplicas = 1
+		updated.Status.AvailableReplicas = 1
+		Expect(c.Status().Update(context.Background(), updated)).To(Succeed())
+		result, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(runRequeueInterval))
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(deploy), updated)).To(Succeed())
+		Expect(updated.Spec.Paused).To(BeFalse())
+
+		updated.Status.Replicas = 1
+		Expect(c.Status().Update(context.Background(), updated)).To(Succeed())
+		_, err = r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(deploy), updated)).To(Succeed())
+		Expect(updated.Spec.Paused).To(BeTrue())
+		Expect(updated.Annotations).NotTo(HaveKey(resumedByAnnotation))
+	})
+
+	It("should pause Deployments again in every namespace of its pods", func() {
+		resumed := newTestDeployment("web")
+		resumed.Namespace = "team-a"
+		resumed.Annotations = map[string]string{resumedByAnnotation: "default/targeted"}
+		resumed.Status = appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+		other := newTestDeployment("api")
+		other.Namespace = "team-a"
+		other.UID = "deploy-api"
+		other.Annotations = map[string]string{resumedByAnnotation: "team-b/targeted"}
+		obj := newTestAutoRestartPod("targeted", "*/5 * * * *")
+		obj.Spec.Namespaces = []string{"team-a"}
+
+		c := newFakeClient(obj, resumed, other)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+		waiting, err := r.repauseWorkloads(context.Background(), obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(waiting).To(BeFalse())
+
+		updated := &appsv1.Deployment{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(resumed), updated)).To(Succeed())
+		Expect(updated.Spec.Paused).To(BeTrue())
+		Expect(updated.Annotations).NotTo(HaveKey(resumedByAnnotation))
+
+		// A Deployment resumed by an AutoRestartPod of the same name elsewhere is left alone
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(other), updated)).To(Succeed())
+		Expect(updated.Spec.Paused).To(BeFalse())
+		Expect(updated.Annotations).To(HaveKeyWithValue(resumedByAnnotation, "team-b/targeted"))
+	})
+
+	It("should rollout restart the owners of selected pods and delete bare pods", func() {
+		deploy := newTestDeployment("nginx")
+		rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "nginx-abc", Namespace: "default", UID: "rs-nginx"}}
+		rs.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "apps/v1", Kind: "Deployment", Name: "nginx", UID: deploy.UID, Controller: ptr.To(true),
+		}}
+		owned := newOwnedPod("nginx-abc-1", "nginx", rs, "ReplicaSet")
+		bare := newTestPod("nginx-bare")
+		obj := newTestAutoRestartPod("owners", "*/5 * * * *")
+		obj.Spec.Strategy = stablev1.RolloutRestartStrategy
+
+		c := newFakeClient(obj, deploy, rs, owned, bare)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &appsv1.Deployment{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(deploy), updated)).To(Succeed())
+		Expect(updated.Spec.Template.Annotations).To(HaveKey(restartedAtAnnotation))
+		Expect(updated.Annotations).To(HaveKeyWithValue(lastRestartByAnnotation, "owners"))
+		Expect(podExists(c, owned)).To(BeTrue())
+		Expect(podExists(c, bare)).To(BeFalse())
+		Expect(targetKinds(c, obj)).To(Equal([]string{"Deployment", "Pod"}))
+	})
+
+	It("should record the strategy the last restart used", func() {
+		deploy := newTestDeployment("web")
+		obj := newTargetedAutoRestartPod("Deployment", "web")
+		obj.Spec.Strategy = stablev1.RolloutRestartStrategy
+		web := newTestPod("web-0")
+		web.Labels = map[string]string{"app": "web"}
+
+		c := newFakeClient(obj, deploy, web)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lastRestartStrategy(c, obj)).To(Equal(stablev1.RolloutRestartStrategy))
+
+		deleted := newTestAutoRestartPod("deleted", "*/5 * * * *")
+		c = newFakeClient(deleted, newTestPod("nginx-0"))
+		r = &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+		_, err = r.Reconcile(context.Background(), requestFor(deleted))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lastRestartStrategy(c, deleted)).To(Equal(stablev1.DeleteStrategy), "Delete is the default")
+	})
+
+	It("should record Delete when RolloutRestart fell back to deleting bare pods", func() {
+		bare := newTestPod("nginx-bare")
+		obj := newTestAutoRestartPod("bare", "*/5 * * * *")
+		obj.Spec.Strategy = stablev1.RolloutRestartStrategy
+
+		c := newFakeClient(obj, bare)
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+		_, err := r.Reconcile(context.Background(), requestFor(obj))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists(c, bare)).To(BeFalse())
+		Expect(lastRestartStrategy(c, obj)).To(Equal(stablev1.DeleteStrategy))
+	})
+
+	It("should name the strategy of the restarted objects", func() {
+		rollout := newTestAutoRestartPod("rollout", "*/5 * * * *")
+		rollout.Spec.Strategy = stablev1.RolloutRestartStrategy
+		Expect(restartedStrategy(rollout, []string{"nginx-bare", "Deployment/web"})).To(Equal(stablev1.RolloutRestartStrategy))
+		Expect(restartedStrategy(rollout, []string{"nginx-bare"})).To(Equal(stablev1.DeleteStrategy))
+
+		evict := newTestAutoRestartPod("evict", "*/5 * * * *")
+		evict.Spec.Strategy = stablev1.EvictStrategy
+		Expect(restartedStrategy(evict, []string{"nginx-0"})).To(Equal(stablev1.EvictStrategy))
+	})
+
+	It("should list each restarted kind once", func() {
+		Expect(restartedKinds([]string{"StatefulSet/db", "nginx-0", "Deployment/web", "nginx-1", "Deployment/api"})).
+			To(Equal([]string{"Deployment", "Pod", "StatefulSet"}))
+		Expect(restartedKinds(nil)).To(BeEmpty())
+	})
+
+	It("should require exactly one of selector and targetRef", func() {
+		both := newTestAutoRestartPod("both", "*/5 * * * *")
+		both.Spec.TargetRef = &stablev1.TargetReference{Kind: "Deployment", Name: "web"}
+		Expect(validateTarget(&both.Spec)).To(MatchError(ContainSubstring("only one")))
+
+		neither := newTestAutoRestartPod("neither", "*/5 * * * *")
+		neither.Spec.Selector = nil
+		Expect(validateTarget(&neither.Spec)).To(MatchError(ContainSubstring("must be set")))
+
+		c := newFakeClient(both, newTestPod("nginx-0"))
+		r := &AutoRestartPodReconciler{Client: c, Scheme: c.Scheme(), Clock: fakeClock}
+		_, err := r.Reconcile(context.Background(), requestFor(both))
+		Expect(err).To(HaveOccurred())
+		Expect(podExists(c, newTestPod("nginx-0"))).To(BeTrue())
+	})
+})
@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package podfields supports field selectors over pods, e.g.
+// `spec.nodeName=node-1,status.phase=Running`. Pods are listed from the
+// informer cache, which only answers field selectors on indexed fields, so
+// only the fields indexed here can be selected on, and only for equality.
+package podfields
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// indexers extract the value of every selectable field of a pod.
+var indexers = map[string]func(pod *corev1.Pod) string{
+	"spec.nodeName":           func(pod *corev1.Pod) string { return pod.Spec.NodeName },
+	"spec.restartPolicy":      func(pod *corev1.Pod) string { return string(pod.Spec.RestartPolicy) },
+	"spec.schedulerName":      func(pod *corev1.Pod) string { return pod.Spec.SchedulerName },
+	"spec.serviceAccountName": func(pod *corev1.Pod) string { return pod.Spec.ServiceAccountName },
+	"status.phase":            func(pod *corev1.Pod) string { return string(pod.Status.Phase) },
+	"status.podIP":            func(pod *corev1.Pod) string { return pod.Status.PodIP },
+}
+
+// Fields returns the names of the selectable fields in order.
+func Fields() []string {
+	names := make([]string, 0, len(indexers))
+	for name := range indexers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Indexers returns an index function for every selectable field.
+func Indexers() map[string]client.IndexerFunc {
+	funcs := make(map[string]client.IndexerFunc, len(indexers))
+	for name, extract := range indexers {
+		funcs[name] = func(obj client.Object) []string {
+			pod, ok := obj.(*corev1.Pod)
+			if !ok {
+				return nil
+			}
+			return []string{extract(pod)}
+		}
+	}
+	return funcs
+}
+
+// Register indexes every selectable field in indexer.
+func Register(ctx context.Context, indexer client.FieldIndexer) error {
+	for name, index := range Indexers() {
+		if err := indexer.IndexField(ctx, &corev1.Pod{}, name, index); err != nil {
+			return fmt.Errorf("indexing pod field %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Parse parses a field selector, which may only require selectable fields
+// to equal a value.
+func Parse(selector string) (fields.Selector, error) {
+	parsed, err := fields.ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, requirement := range parsed.Requirements() {
+		if _, ok := indexers[requirement.Field]; !ok {
+			return nil, fmt.Errorf("field %q cannot be selected on, supported fields are %s",
+				requirement.Field, strings.Join(Fields(), ", "))
+		}
+		if requirement.Operator != selection.Equals && requirement.Operator != selection.DoubleEquals {
+			return nil, fmt.Errorf("field %q can only be matched with =", requirement.Field)
+		}
+	}
+	return parsed, nil
+}
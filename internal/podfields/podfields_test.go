@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podfields
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+var _ = Describe("Field selectors", func() {
+	It("should parse equality on indexed fields", func() {
+		selector, err := Parse("spec.nodeName=node-1,status.phase==Running")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(selector.Matches(fields.Set{"spec.nodeName": "node-1", "status.phase": "Running"})).To(BeTrue())
+		Expect(selector.Matches(fields.Set{"spec.nodeName": "node-2", "status.phase": "Running"})).To(BeFalse())
+	})
+
+	DescribeTable("rejecting selectors",
+		func(selector, message string) {
+			_, err := Parse(selector)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("on a field that is not indexed", "spec.hostname=web", `field "spec.hostname" cannot be selected on`),
+		Entry("with inequality", "status.phase!=Running", "can only be matched with ="),
+		Entry("that do not parse", "status.phase", "invalid selector"),
+	)
+
+	It("should index the value of every field", func() {
+		pod := &corev1.Pod{
+			Spec:   corev1.PodSpec{NodeName: "node-1", RestartPolicy: corev1.RestartPolicyAlways},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.7"},
+		}
+		indexers := Indexers()
+		Expect(Fields()).To(HaveLen(len(indexers)))
+		Expect(indexers["spec.nodeName"](pod)).To(Equal([]string{"node-1"}))
+		Expect(indexers["spec.restartPolicy"](pod)).To(Equal([]string{"Always"}))
+		Expect(indexers["status.phase"](pod)).To(Equal([]string{"Running"}))
+		Expect(indexers["status.podIP"](pod)).To(Equal([]string{"10.0.0.7"}))
+		Expect(indexers["spec.nodeName"](&corev1.Node{})).To(BeNil())
+	})
+})
@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package podmatch evaluates CEL expressions against pods so AutoRestartPods
+// can select pods by any of their fields, e.g.
+// `pod.spec.nodeName.startsWith('gpu-')`.
+package podmatch
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// podVariable is the name under which the pod is exposed to expressions.
+const podVariable = "pod"
+
+// Matcher is a compiled match expression. It is safe for concurrent use.
+type Matcher struct {
+	program cel.Program
+}
+
+// Compile parses and type-checks expression, which must evaluate to a bool.
+func Compile(expression string) (*Matcher, error) {
+	env, err := cel.NewEnv(cel.Variable(podVariable, cel.DynType))
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(expression)
+	if issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("expression must evaluate to bool, not %s", ast.OutputType())
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+	return &Matcher{program: program}, nil
+}
+
+// Matches evaluates the expression against pod. The pod is exposed as its
+// JSON representation, so fields use their API names (pod.metadata.labels,
+// pod.spec.nodeName, ...).
+func (m *Matcher) Matches(pod *corev1.Pod) (bool, error) {
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+	if err != nil {
+		return false, err
+	}
+	out, _, err := m.program.Eval(map[string]any{podVariable: object})
+	if err != nil {
+		return false, err
+	}
+	matched, ok := out.(types.Bool)
+	if !ok {
+		return false, fmt.Errorf("expression evaluated to %s, not bool", out.Type())
+	}
+	return bool(matched), nil
+}
+
+// Cache keeps compiled matchers by expression so each expression is only
+// compiled once. The zero value is ready to use.
+type Cache struct {
+	mu       sync.Mutex
+	matchers map[string]*Matcher
+}
+
+// Get returns the matcher for expression, compiling it on first use.
+func (c *Cache) Get(expression string) (*Matcher, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if m, ok := c.matchers[expression]; ok {
+		return m, nil
+	}
+	m, err := Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	if c.matchers == nil {
+		c.matchers = make(map[string]*Matcher)
+	}
+	c.matchers[expression] = m
+	return m, nil
+}
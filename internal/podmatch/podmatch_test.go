@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podmatch
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Matcher", func() {
+	var pod *corev1.Pod
+
+	BeforeEach(func() {
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "worker-0",
+				Namespace: "default",
+				Labels:    map[string]string{"app": "worker", "tier": "batch"},
+			},
+			Spec: corev1.PodSpec{
+				NodeName: "gpu-node-1",
+				Containers: []corev1.Container{
+					{Name: "worker", Image: "worker:1.2"},
+					{Name: "sidecar", Image: "envoy:1.30"},
+				},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	})
+
+	DescribeTable("evaluating expressions",
+		func(expression string, expected bool) {
+			m, err := Compile(expression)
+			Expect(err).NotTo(HaveOccurred())
+			matched, err := m.Matches(pod)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matched).To(Equal(expected))
+		},
+		Entry("node name prefix", "pod.spec.nodeName.startsWith('gpu-')", true),
+		Entry("node name mismatch", "pod.spec.nodeName.startsWith('cpu-')", false),
+		Entry("label value", "pod.metadata.labels.tier == 'batch'", true),
+		Entry("container images", "pod.spec.containers.exists(c, c.image.startsWith('envoy:'))", true),
+		Entry("phase and container count", "pod.status.phase == 'Running' && size(pod.spec.containers) > 2", false),
+		Entry("optional field", "has(pod.metadata.annotations) && 'skip' in pod.metadata.annotations", false),
+	)
+
+	It("should reject expressions that do not compile", func() {
+		_, err := Compile("pod.spec.nodeName.startsWith(")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject expressions that are not boolean", func() {
+		_, err := Compile("size(pod.spec.containers) + 1")
+		Expect(err).To(MatchError(ContainSubstring("must evaluate to bool")))
+	})
+
+	It("should report evaluation errors for missing fields", func() {
+		m, err := Compile("pod.metadata.annotations.skip == 'true'")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = m.Matches(pod)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should compile each expression once", func() {
+		var cache Cache
+		first, err := cache.Get("pod.spec.nodeName != ''")
+		Expect(err).NotTo(HaveOccurred())
+		second, err := cache.Get("pod.spec.nodeName != ''")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second).To(BeIdenticalTo(first))
+	})
+})
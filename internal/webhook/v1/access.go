@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// validateNamespaceAccess denies autorestartpod unless the user creating or
+// updating it may delete pods in every namespace of Spec.Namespaces, as
+// the controller deletes them there with its own cluster-wide permissions.
+// On update, old is the object before it and only namespaces it did not
+// list yet are checked.
+func validateNamespaceAccess(ctx context.Context, reviewer client.Writer,
+	autorestartpod, old *stablev1.AutoRestartPod) error {
+	var added []int
+	for i, namespace := range autorestartpod.Spec.Namespaces {
+		if old == nil || !slices.Contains(old.Spec.Namespaces, namespace) {
+			added = append(added, i)
+		}
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	path := field.NewPath("spec", "namespaces")
+	if reviewer == nil {
+		return field.Forbidden(path, "namespaces cannot be checked for access, leave them unset")
+	}
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("no admission request to check namespace access for: %w", err)
+	}
+	extra := make(map[string]authorizationv1.ExtraValue, len(req.UserInfo.Extra))
+	for key, value := range req.UserInfo.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+
+	var allErrs field.ErrorList
+	for _, i := range added {
+		namespace := autorestartpod.Spec.Namespaces[i]
+		review := &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				User:   req.UserInfo.Username,
+				Groups: req.UserInfo.Groups,
+				UID:    req.UserInfo.UID,
+				Extra:  extra,
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: namespace,
+					Verb:      "delete",
+					Resource:  "pods",
+				},
+			},
+		}
+		if err := reviewer.Create(ctx, review); err != nil {
+			return fmt.Errorf("failed to review access to namespace %s: %w", namespace, err)
+		}
+		if !review.Status.Allowed {
+			allErrs = append(allErrs, field.Forbidden(path.Index(i),
+				fmt.Sprintf("user %s may not delete pods in namespace %s", req.UserInfo.Username, namespace)))
+		}
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(stablev1.GroupVersion.WithKind("AutoRestartPod").GroupKind(),
+		autorestartpod.Name, allErrs)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"slices"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// newAccessReviewer returns a client answering SubjectAccessReviews, which
+// allows deleting pods in the namespaces allowed only. Every namespace
+// reviewed is appended to reviewed.
+func newAccessReviewer(reviewed *[]string, allowed ...string) client.Client {
+	scheme := runtime.NewScheme()
+	Expect(authorizationv1.AddToScheme(scheme)).To(Succeed())
+	return fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+			review := obj.(*authorizationv1.SubjectAccessReview)
+			attributes := review.Spec.ResourceAttributes
+			Expect(attributes.Verb).To(Equal("delete"))
+			Expect(attributes.Resource).To(Equal("pods"))
+			*reviewed = append(*reviewed, attributes.Namespace)
+			review.Status.Allowed = review.Spec.User == "alice" && slices.Contains(allowed, attributes.Namespace)
+			return nil
+		},
+	}).Build()
+}
+
+// requestContext returns a context carrying the admission request of user.
+func requestContext(user string) context.Context {
+	return admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{UserInfo: authenticationv1.UserInfo{Username: user}},
+	})
+}
+
+var _ = Describe("AutoRestartPod namespace access", func() {
+	var (
+		reviewed  []string
+		validator AutoRestartPodCustomValidator
+		obj       *stablev1.AutoRestartPod
+	)
+
+	BeforeEach(func() {
+		reviewed = nil
+		validator = AutoRestartPodCustomValidator{AccessReviewer: newAccessReviewer(&reviewed, "default", "staging")}
+		obj = newSelectorObject("web", &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}})
+		obj.Spec.Namespaces = []string{"staging", "kube-system"}
+	})
+
+	It("Should deny namespaces the requester may not delete pods in", func() {
+		_, err := validator.ValidateCreate(requestContext("alice"), obj)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("spec.namespaces[1]")))
+		Expect(err).To(MatchError(ContainSubstring("user alice may not delete pods in namespace kube-system")))
+		Expect(err).NotTo(MatchError(ContainSubstring("spec.namespaces[0]")))
+
+		obj.Spec.Namespaces = []string{"staging"}
+		_, err = validator.ValidateCreate(requestContext("mallory"), obj)
+		Expect(err).To(MatchError(ContainSubstring("user mallory may not delete pods in namespace staging")))
+	})
+
+	It("Should admit namespaces the requester may delete pods in", func() {
+		obj.Spec.Namespaces = []string{"default", "staging"}
+		_, err := validator.ValidateCreate(requestContext("alice"), obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reviewed).To(Equal([]string{"default", "staging"}))
+	})
+
+	It("Should only review the namespaces an update adds", func() {
+		old := obj.DeepCopy()
+		old.Spec.Namespaces = []string{"kube-system"}
+		obj.Spec.Namespaces = []string{"kube-system", "staging"}
+		_, err := validator.ValidateUpdate(requestContext("alice"), old, obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reviewed).To(Equal([]string{"staging"}))
+	})
+
+	It("Should deny namespaces it cannot review", func() {
+		validator.AccessReviewer = nil
+		_, err := validator.ValidateCreate(requestContext("alice"), obj)
+		Expect(err).To(MatchError(ContainSubstring("spec.namespaces")))
+
+		obj.Spec.Namespaces = nil
+		_, err = validator.ValidateCreate(context.Background(), obj)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
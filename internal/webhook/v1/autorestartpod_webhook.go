@@ -0,0 +1,392 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+	"github.com/crazyfrankie/autorestart-operator/internal/podfields"
+	"github.com/crazyfrankie/autorestart-operator/internal/podmatch"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var autorestartpodlog = logf.Log.WithName("autorestartpod-resource")
+
+// defaultTimeZone is stored when an AutoRestartPod does not specify a
+// timezone and the defaulter has no DefaultTimeZone.
+const defaultTimeZone = "UTC"
+
+// scheduleSamples is how many consecutive fire times of a schedule are checked
+// against the minimum schedule interval.
+const scheduleSamples = 100
+
+// SetupAutoRestartPodWebhookWithManager registers the webhook for AutoRestartPod in the manager.
+// Schedules firing more often than minScheduleInterval are denied; zero
+// allows any schedule. Objects without a timezone are given
+// defaultTimeZone, UTC when empty.
+func SetupAutoRestartPodWebhookWithManager(mgr ctrl.Manager, minScheduleInterval time.Duration,
+	defaultTimeZone string) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&stablev1.AutoRestartPod{}).
+		WithDefaulter(&AutoRestartPodCustomDefaulter{DefaultTimeZone: defaultTimeZone}).
+		WithValidator(&AutoRestartPodCustomValidator{
+			Client:              mgr.GetClient(),
+			AccessReviewer:      mgr.GetClient(),
+			MinScheduleInterval: minScheduleInterval,
+		}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-stable-crazyfrank-com-v1-autorestartpod,mutating=true,failurePolicy=fail,sideEffects=None,groups=stable.crazyfrank.com,resources=autorestartpods,verbs=create;update,versions=v1,name=mautorestartpod-v1.kb.io,admissionReviewVersions=v1
+
+// AutoRestartPodCustomDefaulter struct is responsible for setting default values on the custom resource of the
+// Kind AutoRestartPod when those are created or updated.
+type AutoRestartPodCustomDefaulter struct {
+	// DefaultTimeZone is stored when an AutoRestartPod does not specify a
+	// timezone. UTC is stored when empty.
+	DefaultTimeZone string
+}
+
+var _ webhook.CustomDefaulter = &AutoRestartPodCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind AutoRestartPod.
+// It normalizes the schedule and timezone and fills in the optional fields whose
+// defaults the controller otherwise has to assume, so stored objects are explicit.
+// Values that are already set are preserved.
+func (d *AutoRestartPodCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	autorestartpod, ok := obj.(*stablev1.AutoRestartPod)
+	if !ok {
+		return fmt.Errorf("expected an AutoRestartPod object but got %T", obj)
+	}
+	autorestartpodlog.Info("Defaulting for AutoRestartPod", "name", autorestartpod.GetName())
+
+	spec := &autorestartpod.Spec
+	spec.Schedule = strings.TrimSpace(spec.Schedule)
+	for env, schedule := range spec.EnvironmentSchedules {
+		spec.EnvironmentSchedules[env] = strings.TrimSpace(schedule)
+	}
+	for i := range spec.Restarts {
+		spec.Restarts[i].Schedule = strings.TrimSpace(spec.Restarts[i].Schedule)
+		spec.Restarts[i].TimeZone = strings.TrimSpace(spec.Restarts[i].TimeZone)
+	}
+	spec.TimeZone = strings.TrimSpace(spec.TimeZone)
+	// Fields left unset under a policyRef are inherited from the policy
+	if spec.PolicyRef == nil {
+		if spec.TimeZone == "" {
+			spec.TimeZone = d.DefaultTimeZone
+		}
+		if spec.TimeZone == "" {
+			spec.TimeZone = defaultTimeZone
+		}
+		if spec.Strategy == "" {
+			spec.Strategy = stablev1.DeleteStrategy
+		}
+		if spec.RestartOrder == "" {
+			spec.RestartOrder = stablev1.OldestFirst
+		}
+	}
+	if spec.MaxPodsPerRestart != nil && spec.OverflowPolicy == "" {
+		spec.OverflowPolicy = stablev1.TruncateOverflow
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-stable-crazyfrank-com-v1-autorestartpod,mutating=false,failurePolicy=fail,sideEffects=None,groups=stable.crazyfrank.com,resources=autorestartpods,verbs=create;update,versions=v1,name=vautorestartpod-v1.kb.io,admissionReviewVersions=v1
+
+// AutoRestartPodCustomValidator struct is responsible for validating the AutoRestartPod resource
+// when it is created, updated, or deleted.
+type AutoRestartPodCustomValidator struct {
+	// MinScheduleInterval is the shortest time allowed between two fire
+	// times of a schedule, protecting shared clusters from restarts every
+	// minute. Any schedule is allowed when zero.
+	MinScheduleInterval time.Duration
+
+	// Client lists the other AutoRestartPods of a namespace to deny objects
+	// targeting the same pods. Overlaps are not checked when nil.
+	Client client.Reader
+
+	// AccessReviewer creates the SubjectAccessReviews checking that the
+	// requester may delete pods in every namespace of Spec.Namespaces.
+	// Objects listing namespaces are denied when nil.
+	AccessReviewer client.Writer
+}
+
+var _ webhook.CustomValidator = &AutoRestartPodCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type AutoRestartPod.
+func (v *AutoRestartPodCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	autorestartpod, ok := obj.(*stablev1.AutoRestartPod)
+	if !ok {
+		return nil, fmt.Errorf("expected an AutoRestartPod object but got %T", obj)
+	}
+	autorestartpodlog.Info("Validation for AutoRestartPod upon creation", "name", autorestartpod.GetName())
+
+	return v.validate(ctx, autorestartpod, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type AutoRestartPod.
+func (v *AutoRestartPodCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldAutorestartpod, ok := oldObj.(*stablev1.AutoRestartPod)
+	if !ok {
+		return nil, fmt.Errorf("expected an AutoRestartPod object for the oldObj but got %T", oldObj)
+	}
+	autorestartpod, ok := newObj.(*stablev1.AutoRestartPod)
+	if !ok {
+		return nil, fmt.Errorf("expected an AutoRestartPod object for the newObj but got %T", newObj)
+	}
+	autorestartpodlog.Info("Validation for AutoRestartPod upon update", "name", autorestartpod.GetName())
+
+	return v.validate(ctx, autorestartpod, oldAutorestartpod)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type AutoRestartPod.
+// Deletion is always allowed.
+func (v *AutoRestartPodCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks the spec of autorestartpod and then that it does not target
+// the pods of another AutoRestartPod. old is the object autorestartpod
+// updates, nil on creation.
+func (v *AutoRestartPodCustomValidator) validate(ctx context.Context,
+	autorestartpod, old *stablev1.AutoRestartPod) (admission.Warnings, error) {
+	// An update that leaves the spec alone is admitted as it is, so that
+	// objects admitted before --min-schedule-interval was tightened can
+	// still be patched and finalized
+	if old != nil && specUnchanged(old, autorestartpod) {
+		return nil, nil
+	}
+	if err := validateAutoRestartPod(autorestartpod, v.MinScheduleInterval); err != nil {
+		return nil, err
+	}
+	if err := validateNamespaceAccess(ctx, v.AccessReviewer, autorestartpod, old); err != nil {
+		return nil, err
+	}
+	if v.Client == nil {
+		return nil, nil
+	}
+	return validateOverlap(ctx, v.Client, autorestartpod, old)
+}
+
+// specUnchanged reports whether the update of old to autorestartpod leaves
+// its spec alone, like a change of the metadata only, or comes while the
+// object is being deleted, like the removal of its finalizer.
+func specUnchanged(old, autorestartpod *stablev1.AutoRestartPod) bool {
+	return !autorestartpod.DeletionTimestamp.IsZero() || equality.Semantic.DeepEqual(old.Spec, autorestartpod.Spec)
+}
+
+// validateAutoRestartPod returns an Invalid error listing every problem with
+// the spec, or nil when the object is valid.
+func validateAutoRestartPod(autorestartpod *stablev1.AutoRestartPod, minScheduleInterval time.Duration) error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if autorestartpod.Spec.Schedule == "" && len(autorestartpod.Spec.Weekly) == 0 &&
+		len(autorestartpod.Spec.WeeklySchedules) == 0 && autorestartpod.Spec.At == nil &&
+		len(autorestartpod.Spec.Restarts) == 0 && autorestartpod.Spec.PolicyRef == nil {
+		allErrs = append(allErrs, field.Required(specPath.Child("schedule"),
+			"schedule is required unless weekly, weeklySchedules, at, restarts or policyRef are set"))
+	}
+	if autorestartpod.Spec.Schedule != "" && autorestartpod.Spec.At != nil {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("at"),
+			"only one of schedule and at may be set"))
+	}
+	if len(autorestartpod.Spec.Weekly) > 0 {
+		if autorestartpod.Spec.Schedule != "" || autorestartpod.Spec.At != nil {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("weekly"),
+				"weekly cannot be combined with schedule or at"))
+		}
+		allErrs = append(allErrs, validateWeekly(autorestartpod.Spec.Weekly, specPath.Child("weekly"))...)
+	}
+	if len(autorestartpod.Spec.WeeklySchedules) > 0 {
+		if len(autorestartpod.Spec.Weekly) > 0 || autorestartpod.Spec.At != nil {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("weeklySchedules"),
+				"weeklySchedules cannot be combined with weekly or at"))
+		}
+		allErrs = append(allErrs, validateWeeklySchedules(autorestartpod.Spec.WeeklySchedules,
+			specPath.Child("weeklySchedules"))...)
+	}
+	if expression := autorestartpod.Spec.MatchExpression; expression != "" {
+		if _, err := podmatch.Compile(expression); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("matchExpression"), expression, err.Error()))
+		}
+	}
+	if selector := autorestartpod.Spec.FieldSelector; selector != "" {
+		if _, err := podfields.Parse(selector); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("fieldSelector"), selector, err.Error()))
+		}
+	}
+	if key := autorestartpod.Spec.RestartAnnotationKey; key != "" {
+		for _, msg := range validation.IsQualifiedName(key) {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("restartAnnotationKey"), key, msg))
+		}
+	}
+	if minScheduleInterval > 0 {
+		allErrs = append(allErrs, validateScheduleIntervals(&autorestartpod.Spec, specPath, minScheduleInterval)...)
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(stablev1.GroupVersion.WithKind("AutoRestartPod").GroupKind(),
+		autorestartpod.Name, allErrs)
+}
+
+// validateScheduleIntervals denies every schedule of spec firing more often
+// than minInterval.
+func validateScheduleIntervals(spec *stablev1.AutoRestartPodSpec, specPath *field.Path,
+	minInterval time.Duration) field.ErrorList {
+	var allErrs field.ErrorList
+	check := func(path *field.Path, schedule string) {
+		if interval, ok := shortestInterval(schedule); ok && interval < minInterval {
+			allErrs = append(allErrs, field.Invalid(path, schedule,
+				fmt.Sprintf("fires every %s, more often than the minimum interval of %s", interval, minInterval)))
+		}
+	}
+
+	if spec.Schedule != "" {
+		check(specPath.Child("schedule"), spec.Schedule)
+	}
+	if interval, ok := weeklyInterval(spec.Weekly); ok && interval < minInterval {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("weekly"), len(spec.Weekly),
+			fmt.Sprintf("restarts %s apart, more often than the minimum interval of %s", interval, minInterval)))
+	}
+	for env, schedule := range spec.EnvironmentSchedules {
+		check(specPath.Child("environmentSchedules").Key(env), schedule)
+	}
+	for day, schedule := range spec.WeeklySchedules {
+		check(specPath.Child("weeklySchedules").Key(day), schedule)
+	}
+	for i, rule := range spec.Restarts {
+		check(specPath.Child("restarts").Index(i).Child("schedule"), rule.Schedule)
+	}
+	return allErrs
+}
+
+// weekdays are the days a weekly entry may name.
+var weekdays = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+
+// validateWeekly checks the day and time of every weekly entry.
+func validateWeekly(entries []stablev1.WeeklyEntry, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, entry := range entries {
+		if !slices.Contains(weekdays, entry.Day) {
+			allErrs = append(allErrs, field.NotSupported(path.Index(i).Child("day"), entry.Day, weekdays))
+		}
+		if _, err := time.Parse("15:04", entry.Time); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Index(i).Child("time"), entry.Time,
+				"must be a time of day in HH:MM format"))
+		}
+	}
+	return allErrs
+}
+
+// weekdayKeys are the keys weeklySchedules may use.
+var weekdayKeys = []string{"MON", "TUE", "WED", "THU", "FRI", "SAT", "SUN"}
+
+// validateWeeklySchedules checks that weeklySchedules is keyed by weekday
+// and that every schedule parses.
+func validateWeeklySchedules(schedules map[string]string, path *field.Path) field.ErrorList {
+	// Like the controller, accept both 5-field and 6-field expressions
+	parser := cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+	var allErrs field.ErrorList
+	for _, day := range slices.Sorted(maps.Keys(schedules)) {
+		schedule := schedules[day]
+		if !slices.Contains(weekdayKeys, day) {
+			allErrs = append(allErrs, field.NotSupported(path, day, weekdayKeys))
+			continue
+		}
+		if _, err := parser.Parse(schedule); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Key(day), schedule, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// weeklyInterval returns the shortest time between two consecutive entries
+// of a weekly pattern, counting the wrap into the next week. Invalid entries
+// are left out; it reports false without any valid entry.
+func weeklyInterval(entries []stablev1.WeeklyEntry) (time.Duration, bool) {
+	const week = 7 * 24 * time.Hour
+	var offsets []time.Duration
+	for _, entry := range entries {
+		day := slices.Index(weekdays, entry.Day)
+		at, err := time.Parse("15:04", entry.Time)
+		if day < 0 || err != nil {
+			continue
+		}
+		offsets = append(offsets, time.Duration(day)*24*time.Hour+
+			time.Duration(at.Hour())*time.Hour+time.Duration(at.Minute())*time.Minute)
+	}
+	if len(offsets) == 0 {
+		return 0, false
+	}
+	slices.Sort(offsets)
+
+	shortest := week - offsets[len(offsets)-1] + offsets[0]
+	for i := 1; i < len(offsets); i++ {
+		// Duplicate entries fire once
+		if gap := offsets[i] - offsets[i-1]; gap > 0 && gap < shortest {
+			shortest = gap
+		}
+	}
+	return shortest, true
+}
+
+// shortestInterval returns the shortest time between two consecutive fire
+// times of schedule among its next scheduleSamples fire times. It reports
+// false when the schedule cannot be parsed; the controller reports those.
+func shortestInterval(schedule string) (time.Duration, bool) {
+	// Like the controller, accept both 5-field and 6-field expressions
+	parser := cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+	sched, err := parser.Parse(schedule)
+	if err != nil {
+		return 0, false
+	}
+
+	var shortest time.Duration
+	prev := sched.Next(time.Now())
+	for range scheduleSamples {
+		next := sched.Next(prev)
+		if next.IsZero() {
+			break
+		}
+		if interval := next.Sub(prev); shortest == 0 || interval < shortest {
+			shortest = interval
+		}
+		prev = next
+	}
+	return shortest, shortest > 0
+}
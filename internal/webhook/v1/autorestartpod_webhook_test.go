@@ -0,0 +1,326 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+var _ = Describe("AutoRestartPod Webhook", func() {
+	var (
+		obj       *stablev1.AutoRestartPod
+		defaulter AutoRestartPodCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		obj = &stablev1.AutoRestartPod{
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default"},
+			Spec: stablev1.AutoRestartPodSpec{
+				Schedule: "  0 3 * * *\n",
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}},
+			},
+		}
+		defaulter = AutoRestartPodCustomDefaulter{}
+	})
+
+	Context("When creating AutoRestartPod under Defaulting Webhook", func() {
+		It("Should apply defaults when fields are not set", func() {
+			obj.Spec.MaxPodsPerRestart = ptr.To[int32](5)
+			Expect(defaulter.Default(context.Background(), obj)).To(Succeed())
+
+			Expect(obj.Spec.Schedule).To(Equal("0 3 * * *"))
+			Expect(obj.Spec.TimeZone).To(Equal("UTC"))
+			Expect(obj.Spec.Strategy).To(Equal(stablev1.DeleteStrategy))
+			Expect(obj.Spec.RestartOrder).To(Equal(stablev1.OldestFirst))
+			Expect(obj.Spec.OverflowPolicy).To(Equal(stablev1.TruncateOverflow))
+		})
+
+		It("Should trim padded environment schedules", func() {
+			obj.Spec.EnvironmentSchedules = map[string]string{"staging": " */30 * * * * "}
+			Expect(defaulter.Default(context.Background(), obj)).To(Succeed())
+			Expect(obj.Spec.EnvironmentSchedules).To(HaveKeyWithValue("staging", "*/30 * * * *"))
+		})
+
+		It("Should trim padded restart rules", func() {
+			obj.Spec.Restarts = []stablev1.RestartRule{{Name: "web", Schedule: " 5 * * * *", TimeZone: "UTC "}}
+			Expect(defaulter.Default(context.Background(), obj)).To(Succeed())
+			Expect(obj.Spec.Restarts[0].Schedule).To(Equal("5 * * * *"))
+			Expect(obj.Spec.Restarts[0].TimeZone).To(Equal("UTC"))
+		})
+
+		It("Should apply the configured default timezone", func() {
+			defaulter.DefaultTimeZone = "Europe/Berlin"
+			Expect(defaulter.Default(context.Background(), obj)).To(Succeed())
+			Expect(obj.Spec.TimeZone).To(Equal("Europe/Berlin"))
+		})
+
+		It("Should trim a padded timezone", func() {
+			obj.Spec.TimeZone = " Asia/Shanghai "
+			Expect(defaulter.Default(context.Background(), obj)).To(Succeed())
+			Expect(obj.Spec.TimeZone).To(Equal("Asia/Shanghai"))
+		})
+
+		It("Should not default the overflow policy without a pod limit", func() {
+			Expect(defaulter.Default(context.Background(), obj)).To(Succeed())
+			Expect(obj.Spec.OverflowPolicy).To(BeEmpty())
+		})
+
+		It("Should leave fields inherited from a policy unset", func() {
+			obj.Spec.PolicyRef = &stablev1.PolicyReference{Name: "nightly"}
+			Expect(defaulter.Default(context.Background(), obj)).To(Succeed())
+			Expect(obj.Spec.TimeZone).To(BeEmpty())
+			Expect(obj.Spec.Strategy).To(BeEmpty())
+			Expect(obj.Spec.RestartOrder).To(BeEmpty())
+		})
+	})
+
+	Context("When updating AutoRestartPod under Defaulting Webhook", func() {
+		It("Should preserve values that are already set", func() {
+			obj.Spec.Schedule = "*/5 * * * *"
+			obj.Spec.TimeZone = "America/New_York"
+			obj.Spec.Strategy = stablev1.RolloutRestartStrategy
+			obj.Spec.RestartOrder = stablev1.RandomOrder
+			obj.Spec.MaxPodsPerRestart = ptr.To[int32](5)
+			obj.Spec.OverflowPolicy = stablev1.RefuseOverflow
+			expected := obj.Spec.DeepCopy()
+
+			Expect(defaulter.Default(context.Background(), obj)).To(Succeed())
+			Expect(obj.Spec).To(Equal(*expected))
+		})
+	})
+
+	It("Should reject objects of another kind", func() {
+		Expect(defaulter.Default(context.Background(), &stablev1.AutoRestartPodList{})).NotTo(Succeed())
+	})
+
+	Context("When creating or updating AutoRestartPod under Validating Webhook", func() {
+		var validator AutoRestartPodCustomValidator
+
+		It("Should deny an object without any schedule", func() {
+			obj.Spec.Schedule = ""
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("spec.schedule")))
+		})
+
+		It("Should admit restart rules without a top-level schedule", func() {
+			obj.Spec.Schedule = ""
+			obj.Spec.Restarts = []stablev1.RestartRule{{Name: "web", Schedule: "5 * * * *"}}
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should admit a policy reference without a schedule", func() {
+			obj.Spec.Schedule = ""
+			obj.Spec.PolicyRef = &stablev1.PolicyReference{Name: "nightly"}
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should admit a one-time restart without a schedule", func() {
+			obj.Spec.Schedule = ""
+			obj.Spec.At = &metav1.Time{Time: time.Date(2025, 5, 26, 10, 30, 0, 0, time.UTC)}
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny both a schedule and a one-time restart", func() {
+			obj.Spec.Schedule = "*/5 * * * *"
+			obj.Spec.At = &metav1.Time{Time: time.Date(2025, 5, 26, 10, 30, 0, 0, time.UTC)}
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("spec.at")))
+		})
+
+		It("Should admit a valid match expression", func() {
+			obj.Spec.MatchExpression = "pod.spec.nodeName.startsWith('gpu-')"
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a match expression that does not compile", func() {
+			obj.Spec.MatchExpression = "pod.spec.nodeName.startsWith("
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("spec.matchExpression")))
+		})
+
+		It("Should deny a match expression that is not boolean on update", func() {
+			oldObj := obj.DeepCopy()
+			obj.Spec.MatchExpression = "size(pod.metadata.name)"
+			_, err := validator.ValidateUpdate(context.Background(), oldObj, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		})
+
+		It("Should admit a field selector on indexed fields", func() {
+			obj.Spec.FieldSelector = "spec.nodeName=node-1,status.phase==Running"
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a field selector the controller cannot answer", func() {
+			obj.Spec.FieldSelector = "status.phase!=Running"
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("spec.fieldSelector")))
+		})
+
+		It("Should admit a qualified restart annotation key", func() {
+			obj.Spec.RestartAnnotationKey = "deploy.example.com/restarted-at"
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a restart annotation key that is not a legal annotation key", func() {
+			obj.Spec.RestartAnnotationKey = "restarted at"
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("spec.restartAnnotationKey")))
+		})
+
+		It("Should deny a schedule firing more often than the minimum interval", func() {
+			validator := AutoRestartPodCustomValidator{MinScheduleInterval: 5 * time.Minute}
+			obj.Spec.Schedule = "* * * * *"
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("spec.schedule")))
+			Expect(err).To(MatchError(ContainSubstring("fires every 1m0s")))
+
+			// Any schedule is allowed without a minimum
+			_, err = (&AutoRestartPodCustomValidator{}).ValidateCreate(context.Background(), obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should admit updates leaving a schedule under a tightened minimum interval alone", func() {
+			validator := AutoRestartPodCustomValidator{MinScheduleInterval: 5 * time.Minute}
+			obj.Spec.Schedule = "* * * * *"
+			obj.Finalizers = []string{"autorestart.crazyfrank.com/finalizer"}
+
+			labeled := obj.DeepCopy()
+			labeled.Labels = map[string]string{"team": "web"}
+			_, err := validator.ValidateUpdate(context.Background(), obj, labeled)
+			Expect(err).NotTo(HaveOccurred())
+
+			deleting := obj.DeepCopy()
+			deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			deleting.Finalizers = nil
+			_, err = validator.ValidateUpdate(context.Background(), obj, deleting)
+			Expect(err).NotTo(HaveOccurred())
+
+			// A change of the spec is checked against the minimum
+			changed := obj.DeepCopy()
+			changed.Spec.TimeZone = "Europe/Berlin"
+			_, err = validator.ValidateUpdate(context.Background(), obj, changed)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		})
+
+		It("Should admit an hourly schedule under the minimum interval", func() {
+			validator := AutoRestartPodCustomValidator{MinScheduleInterval: 5 * time.Minute}
+			obj.Spec.Schedule = "0 * * * *"
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should check the shortest gap of irregular schedules and of every rule", func() {
+			validator := AutoRestartPodCustomValidator{MinScheduleInterval: 5 * time.Minute}
+			oldObj := obj.DeepCopy()
+			obj.Spec.Schedule = "0,2 3 * * *"
+			obj.Spec.Restarts = []stablev1.RestartRule{{Name: "web", Schedule: "*/30 * * * * *"}}
+			obj.Spec.EnvironmentSchedules = map[string]string{"staging": "@hourly"}
+			_, err := validator.ValidateUpdate(context.Background(), oldObj, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("spec.schedule")))
+			Expect(err).To(MatchError(ContainSubstring("spec.restarts[0].schedule")))
+			Expect(err).NotTo(MatchError(ContainSubstring("spec.environmentSchedules")))
+		})
+
+		It("Should admit a weekly pattern without a schedule", func() {
+			obj.Spec.Schedule = ""
+			obj.Spec.Weekly = []stablev1.WeeklyEntry{{Day: "Monday", Time: "03:00"}, {Day: "Friday", Time: "01:30"}}
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a weekly pattern combined with a schedule", func() {
+			obj.Spec.Weekly = []stablev1.WeeklyEntry{{Day: "Monday", Time: "03:00"}}
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("weekly cannot be combined with schedule or at")))
+		})
+
+		It("Should check the day and time of every weekly entry", func() {
+			obj.Spec.Schedule = ""
+			obj.Spec.Weekly = []stablev1.WeeklyEntry{
+				{Day: "Monday", Time: "03:00"},
+				{Day: "Fri", Time: "01:30"},
+				{Day: "Sunday", Time: "25:00"},
+			}
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("spec.weekly[1].day")))
+			Expect(err).To(MatchError(ContainSubstring("spec.weekly[2].time")))
+			Expect(err).NotTo(MatchError(ContainSubstring("spec.weekly[0]")))
+		})
+
+		It("Should admit weekday schedules without a schedule", func() {
+			obj.Spec.Schedule = ""
+			obj.Spec.WeeklySchedules = map[string]string{"FRI": "0 1 * * *"}
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny weekday schedules with unknown days or invalid expressions", func() {
+			obj.Spec.WeeklySchedules = map[string]string{"FRI": "0 1 * * *", "FRIDAY": "0 1 * * *", "SAT": "every day"}
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring(`Unsupported value: "FRIDAY"`)))
+			Expect(err).To(MatchError(ContainSubstring("spec.weeklySchedules[SAT]")))
+			Expect(err).NotTo(MatchError(ContainSubstring("spec.weeklySchedules[FRI]")))
+		})
+
+		It("Should deny weekday schedules combined with a weekly pattern", func() {
+			obj.Spec.Schedule = ""
+			obj.Spec.Weekly = []stablev1.WeeklyEntry{{Day: "Monday", Time: "03:00"}}
+			obj.Spec.WeeklySchedules = map[string]string{"FRI": "0 1 * * *"}
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("weeklySchedules cannot be combined with weekly or at")))
+		})
+
+		It("Should deny weekly entries closer than the minimum interval", func() {
+			validator := AutoRestartPodCustomValidator{MinScheduleInterval: time.Hour}
+			obj.Spec.Schedule = ""
+			obj.Spec.Weekly = []stablev1.WeeklyEntry{{Day: "Saturday", Time: "23:45"}, {Day: "Sunday", Time: "00:15"}}
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("restarts 30m0s apart")))
+
+			obj.Spec.Weekly[1].Day = "Monday"
+			_, err = validator.ValidateCreate(context.Background(), obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,235 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// validateOverlap denies autorestartpod when it targets pods that another
+// AutoRestartPod targets as well, unless Spec.AllowOverlap admits it with a
+// warning. AutoRestartPods of every namespace are compared, as
+// Spec.Namespaces reaches pods in other namespaces. On update, old is the
+// object before it: an overlap old already had is only warned about, so that
+// objects admitted later with allowOverlap do not lock it.
+func validateOverlap(ctx context.Context, c client.Reader,
+	autorestartpod, old *stablev1.AutoRestartPod) (admission.Warnings, error) {
+	list := &stablev1.AutoRestartPodList{}
+	if err := c.List(ctx, list); err != nil {
+		return nil, fmt.Errorf("failed to list AutoRestartPods: %w", err)
+	}
+
+	var existing []string
+	if old != nil {
+		for _, o := range overlappingObjects(list, old) {
+			existing = append(existing, o.name)
+		}
+	}
+	var warnings admission.Warnings
+	var allErrs field.ErrorList
+	for _, o := range overlappingObjects(list, autorestartpod) {
+		if autorestartpod.Spec.AllowOverlap || slices.Contains(existing, o.name) {
+			warnings = append(warnings, fmt.Sprintf("targets the same pods as AutoRestartPod %s", o.name))
+			continue
+		}
+		allErrs = append(allErrs, field.Forbidden(o.path,
+			fmt.Sprintf("targets the same pods as AutoRestartPod %s, set allowOverlap to admit it", o.name)))
+	}
+	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+	return nil, apierrors.NewInvalid(stablev1.GroupVersion.WithKind("AutoRestartPod").GroupKind(),
+		autorestartpod.Name, allErrs)
+}
+
+// overlap is another AutoRestartPod targeting the same pods.
+type overlap struct {
+	// name of the other object, prefixed with its namespace when that
+	// differs from the namespace of the object validated.
+	name string
+	// path is the field of the object validated that targets the pods.
+	path *field.Path
+}
+
+// overlappingObjects returns the objects of list other than autorestartpod
+// that target the same pods as it.
+func overlappingObjects(list *stablev1.AutoRestartPodList, autorestartpod *stablev1.AutoRestartPod) []overlap {
+	targets := podTargets(autorestartpod)
+	var overlapping []overlap
+	for i := range list.Items {
+		other := &list.Items[i]
+		if other.Namespace == autorestartpod.Namespace && other.Name == autorestartpod.Name {
+			continue
+		}
+		name := other.Name
+		if other.Namespace != autorestartpod.Namespace {
+			name = other.Namespace + "/" + other.Name
+		}
+		if path := firstOverlap(targets, podTargets(other)); path != nil {
+			overlapping = append(overlapping, overlap{name: name, path: path})
+		}
+	}
+	return overlapping
+}
+
+// firstOverlap returns the path of the first of targets that overlaps one of
+// others, nil when none does.
+func firstOverlap(targets, others []podTarget) *field.Path {
+	for _, target := range targets {
+		for _, other := range others {
+			overlaps, err := targetsOverlap(target, other)
+			if err != nil {
+				// An invalid selector matches no pods
+				continue
+			}
+			if overlaps {
+				return target.path
+			}
+		}
+	}
+	return nil
+}
+
+// podTarget is one way an AutoRestartPod picks the pods it restarts.
+type podTarget struct {
+	path       *field.Path
+	namespaces []string
+	selector   *metav1.LabelSelector
+	targetRef  *stablev1.TargetReference
+}
+
+// podTargets returns the pods obj restarts: those of Selector or TargetRef
+// and those of every entry of Spec.Restarts, all in the namespaces the
+// controller lists them in.
+func podTargets(obj *stablev1.AutoRestartPod) []podTarget {
+	namespaces := []string{obj.Namespace}
+	if len(obj.Spec.Namespaces) > 0 && obj.Spec.TargetRef == nil {
+		namespaces = obj.Spec.Namespaces
+	}
+	var targets []podTarget
+	switch {
+	case obj.Spec.TargetRef != nil:
+		targets = append(targets, podTarget{path: field.NewPath("spec", "targetRef"),
+			namespaces: namespaces, targetRef: obj.Spec.TargetRef})
+	case obj.Spec.Selector != nil:
+		targets = append(targets, podTarget{path: field.NewPath("spec", "selector"),
+			namespaces: namespaces, selector: obj.Spec.Selector})
+	}
+	for i := range obj.Spec.Restarts {
+		targets = append(targets, podTarget{path: field.NewPath("spec", "restarts").Index(i).Child("selector"),
+			namespaces: namespaces, selector: &obj.Spec.Restarts[i].Selector})
+	}
+	return targets
+}
+
+// targetsOverlap reports whether a and b provably target the same pods: in
+// a shared namespace, both reference the same workload or some pod labels
+// satisfy both selectors. A selector is not compared with a workload, whose
+// pods are only known once it is fetched.
+func targetsOverlap(a, b podTarget) (bool, error) {
+	if !slices.ContainsFunc(a.namespaces, func(namespace string) bool {
+		return slices.Contains(b.namespaces, namespace)
+	}) {
+		return false, nil
+	}
+	switch {
+	case a.targetRef != nil && b.targetRef != nil:
+		return *a.targetRef == *b.targetRef, nil
+	case a.selector != nil && b.selector != nil:
+		return selectorsOverlap(a.selector, b.selector)
+	}
+	return false, nil
+}
+
+// labelConstraint is what the requirements on one label key allow.
+type labelConstraint struct {
+	// exists and absent require the label to be set or not set.
+	exists, absent bool
+	// values are the values allowed, any value when nil.
+	values sets.Set[string]
+	// excluded are the values not allowed.
+	excluded sets.Set[string]
+}
+
+// satisfiable reports whether a value of the label meets the constraint.
+func (c *labelConstraint) satisfiable() bool {
+	if c.absent {
+		return !c.exists && c.values == nil
+	}
+	// Any value but the excluded ones is left when values is nil
+	return c.values == nil || c.values.Difference(c.excluded).Len() > 0
+}
+
+// selectorsOverlap reports whether some set of labels is matched by both a
+// and b. Requirements neither selector can express are assumed to overlap.
+func selectorsOverlap(a, b *metav1.LabelSelector) (bool, error) {
+	constraints := map[string]*labelConstraint{}
+	for _, selector := range []*metav1.LabelSelector{a, b} {
+		parsed, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			return false, err
+		}
+		requirements, _ := parsed.Requirements()
+		for _, requirement := range requirements {
+			constraint := constraints[requirement.Key()]
+			if constraint == nil {
+				constraint = &labelConstraint{excluded: sets.New[string]()}
+				constraints[requirement.Key()] = constraint
+			}
+			addRequirement(constraint, requirement)
+		}
+	}
+	for _, constraint := range constraints {
+		if !constraint.satisfiable() {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// addRequirement narrows constraint down by requirement.
+func addRequirement(constraint *labelConstraint, requirement labels.Requirement) {
+	values := sets.New(requirement.Values().UnsortedList()...)
+	switch requirement.Operator() {
+	case selection.Equals, selection.DoubleEquals, selection.In:
+		constraint.exists = true
+		if constraint.values == nil {
+			constraint.values = values
+		} else {
+			constraint.values = constraint.values.Intersection(values)
+		}
+	case selection.NotEquals, selection.NotIn:
+		constraint.excluded = constraint.excluded.Union(values)
+	case selection.Exists:
+		constraint.exists = true
+	case selection.DoesNotExist:
+		constraint.absent = true
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	stablev1 "github.com/crazyfrankie/autorestart-operator/api/v1"
+)
+
+// newSelectorObject returns an AutoRestartPod named name selecting pods by selector.
+func newSelectorObject(name string, selector *metav1.LabelSelector) *stablev1.AutoRestartPod {
+	return &stablev1.AutoRestartPod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       stablev1.AutoRestartPodSpec{Schedule: "0 3 * * *", Selector: selector},
+	}
+}
+
+var _ = Describe("AutoRestartPod overlap", func() {
+	var validator AutoRestartPodCustomValidator
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(stablev1.AddToScheme(scheme)).To(Succeed())
+		existing := newSelectorObject("nginx", &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}})
+		other := newSelectorObject("web", &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}})
+		other.Namespace = "other"
+		deploy := newSelectorObject("deploy", nil)
+		deploy.Spec.TargetRef = &stablev1.TargetReference{Kind: "Deployment", Name: "api"}
+		validator = AutoRestartPodCustomValidator{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing, other, deploy).Build(),
+		}
+	})
+
+	It("Should deny a selector overlapping another object", func() {
+		obj := newSelectorObject("nginx-canary", &metav1.LabelSelector{MatchLabels: map[string]string{
+			"app": "nginx", "track": "canary",
+		}})
+		_, err := validator.ValidateCreate(context.Background(), obj)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("spec.selector")))
+		Expect(err).To(MatchError(ContainSubstring("AutoRestartPod nginx")))
+	})
+
+	It("Should admit disjoint selectors", func() {
+		for _, selector := range []*metav1.LabelSelector{
+			{MatchLabels: map[string]string{"app": "web"}},
+			{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "app", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"nginx"}},
+			}},
+			{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "app", Operator: metav1.LabelSelectorOpDoesNotExist},
+			}},
+			{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"api", "db"}},
+			}},
+		} {
+			_, err := validator.ValidateCreate(context.Background(), newSelectorObject("disjoint", selector))
+			Expect(err).NotTo(HaveOccurred(), "selector %v", selector)
+		}
+	})
+
+	It("Should deny selectors matching the same labels through expressions", func() {
+		obj := newSelectorObject("nginx-any", &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"nginx", "api"}},
+			{Key: "tier", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"db"}},
+		}})
+		_, err := validator.ValidateCreate(context.Background(), obj)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+
+		// An empty selector matches every pod
+		_, err = validator.ValidateCreate(context.Background(), newSelectorObject("all", &metav1.LabelSelector{}))
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	})
+
+	It("Should deny a reference to an already targeted workload", func() {
+		obj := newSelectorObject("deploy-nightly", nil)
+		obj.Spec.TargetRef = &stablev1.TargetReference{Kind: "Deployment", Name: "api"}
+		_, err := validator.ValidateCreate(context.Background(), obj)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("spec.targetRef")))
+
+		obj.Spec.TargetRef.Name = "worker"
+		_, err = validator.ValidateCreate(context.Background(), obj)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Should admit an overlap with a warning when allowed", func() {
+		obj := newSelectorObject("nginx-canary", &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}})
+		obj.Spec.AllowOverlap = true
+		warnings, err := validator.ValidateCreate(context.Background(), obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ConsistOf("targets the same pods as AutoRestartPod nginx"))
+	})
+
+	It("Should deny a rule selector overlapping the selector of another object", func() {
+		obj := newSelectorObject("rules", nil)
+		obj.Spec.Restarts = []stablev1.RestartRule{
+			{Name: "api", Schedule: "0 4 * * *", Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}}},
+			{Name: "nginx", Schedule: "0 5 * * *", Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}}},
+		}
+		_, err := validator.ValidateCreate(context.Background(), obj)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("spec.restarts[1].selector")))
+		Expect(err).To(MatchError(ContainSubstring("AutoRestartPod nginx")))
+	})
+
+	It("Should compare selectors across the namespaces they pick pods in", func() {
+		var reviewed []string
+		validator.AccessReviewer = newAccessReviewer(&reviewed, "staging", "other")
+		obj := newSelectorObject("web", &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}})
+		obj.Spec.Namespaces = []string{"staging", "other"}
+		_, err := validator.ValidateCreate(requestContext("alice"), obj)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("AutoRestartPod other/web")))
+
+		obj.Spec.Namespaces = []string{"staging"}
+		_, err = validator.ValidateCreate(requestContext("alice"), obj)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Context("with an object admitted over it by allowOverlap", func() {
+		var existing, canary *stablev1.AutoRestartPod
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(stablev1.AddToScheme(scheme)).To(Succeed())
+			existing = newSelectorObject("nginx", &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}})
+			existing.Finalizers = []string{"autorestart.crazyfrank.com/finalizer"}
+			canary = newSelectorObject("nginx-canary", &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}})
+			canary.Spec.AllowOverlap = true
+			validator = AutoRestartPodCustomValidator{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing, canary).Build(),
+			}
+		})
+
+		It("Should admit the removal of the finalizer of the object being deleted", func() {
+			deleting := existing.DeepCopy()
+			deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			deleting.Finalizers = nil
+			warnings, err := validator.ValidateUpdate(context.Background(), existing, deleting)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should admit a metadata change", func() {
+			labeled := existing.DeepCopy()
+			labeled.Labels = map[string]string{"team": "web"}
+			_, err := validator.ValidateUpdate(context.Background(), existing, labeled)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should only warn about an overlap the object already had", func() {
+			updated := existing.DeepCopy()
+			updated.Spec.Schedule = "0 4 * * *"
+			warnings, err := validator.ValidateUpdate(context.Background(), existing, updated)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf("targets the same pods as AutoRestartPod nginx-canary"))
+		})
+
+		It("Should deny a spec change introducing an overlap", func() {
+			web := newSelectorObject("web", &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}})
+			updated := web.DeepCopy()
+			updated.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}}
+			_, err := validator.ValidateUpdate(context.Background(), web, updated)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("AutoRestartPod nginx,")))
+			Expect(err).To(MatchError(ContainSubstring("AutoRestartPod nginx-canary,")))
+		})
+	})
+
+	It("Should not compare an updated object with itself", func() {
+		obj := newSelectorObject("nginx", &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}})
+		warnings, err := validator.ValidateUpdate(context.Background(), obj, obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+})